@@ -0,0 +1,533 @@
+// Package buffertest 提供 buffer.Reader 及 buffer.Writer 实现的属性测试工具.
+//
+// 测试数据为一组有类型的操作(Op), 可随机生成或由模糊测试的输入构造. 将操作依次写入
+// Writer 后再自 Reader 中依次读取, 读取的值须与写入的值一致; 截断编码后的数据后读取,
+// 须返回 io.EOF 或 io.ErrUnexpectedEOF 而不是 panic 或错误的值.
+package buffertest
+
+import (
+	std_bytes "bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"reflect"
+
+	"github.com/godyy/gutils/buffer"
+)
+
+// Kind 操作的数据类型, 对应 buffer.Reader/buffer.Writer 的一组读写方法
+type Kind uint8
+
+const (
+	KindByte Kind = iota
+	KindInt8
+	KindUint8
+	KindBool
+	KindInt16
+	KindUint16
+	KindInt32
+	KindUint32
+	KindInt64
+	KindUint64
+	KindFloat32
+	KindFloat64
+	KindBigInt16
+	KindBigUint16
+	KindBigInt32
+	KindBigUint32
+	KindBigInt64
+	KindBigUint64
+	KindBigFloat32
+	KindBigFloat64
+	KindLitInt16
+	KindLitUint16
+	KindLitInt32
+	KindLitUint32
+	KindLitInt64
+	KindLitUint64
+	KindLitFloat32
+	KindLitFloat64
+	KindVarint16
+	KindUvarint16
+	KindVarint32
+	KindUvarint32
+	KindVarint64
+	KindUvarint64
+	KindString // WriteString/ReadString
+	KindRaw    // Write/io.ReadFull
+	numKinds
+)
+
+var kindNames = [numKinds]string{
+	"Byte", "Int8", "Uint8", "Bool",
+	"Int16", "Uint16", "Int32", "Uint32", "Int64", "Uint64", "Float32", "Float64",
+	"BigInt16", "BigUint16", "BigInt32", "BigUint32", "BigInt64", "BigUint64", "BigFloat32", "BigFloat64",
+	"LitInt16", "LitUint16", "LitInt32", "LitUint32", "LitInt64", "LitUint64", "LitFloat32", "LitFloat64",
+	"Varint16", "Uvarint16", "Varint32", "Uvarint32", "Varint64", "Uvarint64",
+	"String", "Raw",
+}
+
+func (k Kind) String() string {
+	if k < numKinds {
+		return kindNames[k]
+	}
+	return fmt.Sprintf("Kind(%d)", uint8(k))
+}
+
+// Op 一次有类型的写入及对应的读取
+// 数值类型的值为 Bits 截断至对应类型后的值, 浮点数为 Bits 对应的 IEEE 754 位模式;
+// KindString 及 KindRaw 的值为 Data.
+type Op struct {
+	Kind Kind
+	Bits uint64
+	Data []byte
+}
+
+func (op Op) String() string {
+	if op.Kind == KindString || op.Kind == KindRaw {
+		return fmt.Sprintf("%s(%q)", op.Kind, op.Data)
+	}
+	return fmt.Sprintf("%s(%#x)", op.Kind, op.Bits)
+}
+
+// Size 获取 op 编码后的字节数
+func (op Op) Size() int {
+	var scratch [binary.MaxVarintLen64]byte
+	switch op.Kind {
+	case KindByte, KindInt8, KindUint8, KindBool:
+		return 1
+	case KindInt16, KindUint16, KindBigInt16, KindBigUint16, KindLitInt16, KindLitUint16:
+		return 2
+	case KindInt32, KindUint32, KindFloat32, KindBigInt32, KindBigUint32, KindBigFloat32,
+		KindLitInt32, KindLitUint32, KindLitFloat32:
+		return 4
+	case KindVarint16:
+		return binary.PutVarint(scratch[:], int64(int16(op.Bits)))
+	case KindUvarint16:
+		return binary.PutUvarint(scratch[:], uint64(uint16(op.Bits)))
+	case KindVarint32:
+		return binary.PutVarint(scratch[:], int64(int32(op.Bits)))
+	case KindUvarint32:
+		return binary.PutUvarint(scratch[:], uint64(uint32(op.Bits)))
+	case KindVarint64:
+		return binary.PutVarint(scratch[:], int64(op.Bits))
+	case KindUvarint64:
+		return binary.PutUvarint(scratch[:], op.Bits)
+	case KindString:
+		return binary.PutVarint(scratch[:], int64(len(op.Data))) + len(op.Data)
+	case KindRaw:
+		return len(op.Data)
+	default:
+		return 8
+	}
+}
+
+// Size 获取 ops 编码后的总字节数
+func Size(ops []Op) int {
+	n := 0
+	for _, op := range ops {
+		n += op.Size()
+	}
+	return n
+}
+
+// maxDataLen 生成的字符串及原始数据的最大长度
+const maxDataLen = 300
+
+// Generate 随机生成 n 个操作, 数值偏向于 0、类型边界及 varint 字节数的边界
+func Generate(r *rand.Rand, n int) []Op {
+	ops := make([]Op, n)
+	for i := range ops {
+		op := Op{Kind: Kind(r.Intn(int(numKinds)))}
+		if op.Kind == KindString || op.Kind == KindRaw {
+			op.Data = make([]byte, r.Intn(maxDataLen))
+			r.Read(op.Data)
+		} else {
+			op.Bits = genBits(r)
+		}
+		ops[i] = op
+	}
+	return ops
+}
+
+// genBits 随机生成数值, 偏向于边界值
+func genBits(r *rand.Rand) uint64 {
+	switch r.Intn(6) {
+	case 0:
+		return 0
+	case 1:
+		// 7 位一组的 varint 字节数边界
+		v := uint64(1) << (7 * uint(1+r.Intn(9)))
+		return v - uint64(r.Intn(2))
+	case 2:
+		// 类型的最大值及最小值
+		bits := []uint64{math.MaxInt16, math.MaxUint16, math.MaxInt32, math.MaxUint32, math.MaxInt64, math.MaxUint64}
+		v := bits[r.Intn(len(bits))]
+		if r.Intn(2) == 0 {
+			v = ^v
+		}
+		return v
+	case 3:
+		// 小的负数
+		return uint64(-int64(r.Intn(300)))
+	default:
+		return r.Uint64()
+	}
+}
+
+// FromBytes 由模糊测试的输入确定性地构造操作
+// 每个操作的首字节选择类型, 数值类型随后的 8 个字节为小端的值(不足时补 0),
+// KindString 及 KindRaw 随后的 1 个字节为数据长度.
+func FromBytes(data []byte) []Op {
+	var ops []Op
+	for len(data) > 0 {
+		op := Op{Kind: Kind(data[0] % byte(numKinds))}
+		data = data[1:]
+		if op.Kind == KindString || op.Kind == KindRaw {
+			n := 0
+			if len(data) > 0 {
+				n, data = int(data[0]), data[1:]
+			}
+			n = min(n, len(data))
+			op.Data, data = data[:n:n], data[n:]
+		} else {
+			var p [8]byte
+			n := copy(p[:], data)
+			op.Bits, data = binary.LittleEndian.Uint64(p[:]), data[n:]
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
+
+// Write 将 ops 依次写入 w
+func Write(w buffer.Writer, ops []Op) error {
+	for i, op := range ops {
+		if err := writeOp(w, op); err != nil {
+			return fmt.Errorf("op %d %s: %w", i, op, err)
+		}
+	}
+	return nil
+}
+
+func writeOp(w buffer.Writer, op Op) error {
+	v := op.Bits
+	var n int
+	var err error
+	switch op.Kind {
+	case KindByte:
+		return w.WriteByte(byte(v))
+	case KindInt8:
+		return w.WriteInt8(int8(v))
+	case KindUint8:
+		return w.WriteUint8(uint8(v))
+	case KindBool:
+		return w.WriteBool(v&1 != 0)
+	case KindInt16:
+		return w.WriteInt16(int16(v))
+	case KindUint16:
+		return w.WriteUint16(uint16(v))
+	case KindInt32:
+		return w.WriteInt32(int32(v))
+	case KindUint32:
+		return w.WriteUint32(uint32(v))
+	case KindInt64:
+		return w.WriteInt64(int64(v))
+	case KindUint64:
+		return w.WriteUint64(v)
+	case KindFloat32:
+		return w.WriteFloat32(math.Float32frombits(uint32(v)))
+	case KindFloat64:
+		return w.WriteFloat64(math.Float64frombits(v))
+	case KindBigInt16:
+		return w.WriteBigInt16(int16(v))
+	case KindBigUint16:
+		return w.WriteBigUint16(uint16(v))
+	case KindBigInt32:
+		return w.WriteBigInt32(int32(v))
+	case KindBigUint32:
+		return w.WriteBigUint32(uint32(v))
+	case KindBigInt64:
+		return w.WriteBigInt64(int64(v))
+	case KindBigUint64:
+		return w.WriteBigUint64(v)
+	case KindBigFloat32:
+		return w.WriteBigFloat32(math.Float32frombits(uint32(v)))
+	case KindBigFloat64:
+		return w.WriteBigFloat64(math.Float64frombits(v))
+	case KindLitInt16:
+		return w.WriteLitInt16(int16(v))
+	case KindLitUint16:
+		return w.WriteLitUint16(uint16(v))
+	case KindLitInt32:
+		return w.WriteLitInt32(int32(v))
+	case KindLitUint32:
+		return w.WriteLitUint32(uint32(v))
+	case KindLitInt64:
+		return w.WriteLitInt64(int64(v))
+	case KindLitUint64:
+		return w.WriteLitUint64(v)
+	case KindLitFloat32:
+		return w.WriteLitFloat32(math.Float32frombits(uint32(v)))
+	case KindLitFloat64:
+		return w.WriteLitFloat64(math.Float64frombits(v))
+	case KindVarint16:
+		n, err = w.WriteVarint16(int16(v))
+	case KindUvarint16:
+		n, err = w.WriteUvarint16(uint16(v))
+	case KindVarint32:
+		n, err = w.WriteVarint32(int32(v))
+	case KindUvarint32:
+		n, err = w.WriteUvarint32(uint32(v))
+	case KindVarint64:
+		n, err = w.WriteVarint64(int64(v))
+	case KindUvarint64:
+		n, err = w.WriteUvarint64(v)
+	case KindString:
+		return w.WriteString(string(op.Data))
+	case KindRaw:
+		n, err = w.Write(op.Data)
+	default:
+		panic("buffertest: invalid kind")
+	}
+	if err == nil && n != op.Size() {
+		return fmt.Errorf("wrote %d bytes, want %d", n, op.Size())
+	}
+	return err
+}
+
+// Read 自 r 中依次读取 ops 并与写入的值比较
+// 读取出错或值不一致时返回描述出错操作的错误, 可通过 errors.Is 判断读取返回的错误.
+func Read(r buffer.Reader, ops []Op) error {
+	for i, op := range ops {
+		if err := readOp(r, op); err != nil {
+			return fmt.Errorf("op %d %s: %w", i, op, err)
+		}
+	}
+	return nil
+}
+
+// readOp 读取 op 并与写入的值比较
+func readOp(r buffer.Reader, op Op) error {
+	got, err := readBits(r, op)
+	if err != nil {
+		return err
+	}
+
+	want := op.Bits
+	switch op.Kind {
+	case KindBool:
+		want &= 1
+	case KindString, KindRaw:
+		if !std_bytes.Equal(got.([]byte), op.Data) {
+			return fmt.Errorf("got %q", got)
+		}
+		return nil
+	}
+	if mask := bitsMask(op.Kind); got.(uint64) != want&mask {
+		return fmt.Errorf("got %#x", got)
+	}
+	return nil
+}
+
+// bitsMask 获取 kind 对应类型的有效位
+func bitsMask(k Kind) uint64 {
+	switch k {
+	case KindByte, KindInt8, KindUint8:
+		return math.MaxUint8
+	case KindInt16, KindUint16, KindBigInt16, KindBigUint16, KindLitInt16, KindLitUint16,
+		KindVarint16, KindUvarint16:
+		return math.MaxUint16
+	case KindInt32, KindUint32, KindFloat32, KindBigInt32, KindBigUint32, KindBigFloat32,
+		KindLitInt32, KindLitUint32, KindLitFloat32, KindVarint32, KindUvarint32:
+		return math.MaxUint32
+	default:
+		return math.MaxUint64
+	}
+}
+
+// readBits 读取 op 对应类型的值, 数值以位模式返回, 字符串及原始数据以 []byte 返回
+func readBits(r buffer.Reader, op Op) (any, error) {
+	var v uint64
+	var err error
+	switch op.Kind {
+	case KindByte:
+		var c byte
+		c, err = r.ReadByte()
+		v = uint64(c)
+	case KindInt8:
+		var i int8
+		i, err = r.ReadInt8()
+		v = uint64(uint8(i))
+	case KindUint8:
+		var i uint8
+		i, err = r.ReadUint8()
+		v = uint64(i)
+	case KindBool:
+		var b bool
+		b, err = r.ReadBool()
+		if b {
+			v = 1
+		}
+	case KindInt16, KindBigInt16, KindLitInt16, KindVarint16:
+		var i int16
+		switch op.Kind {
+		case KindInt16:
+			i, err = r.ReadInt16()
+		case KindBigInt16:
+			i, err = r.ReadBigInt16()
+		case KindLitInt16:
+			i, err = r.ReadLitInt16()
+		default:
+			i, err = r.ReadVarint16()
+		}
+		v = uint64(uint16(i))
+	case KindUint16, KindBigUint16, KindLitUint16, KindUvarint16:
+		var i uint16
+		switch op.Kind {
+		case KindUint16:
+			i, err = r.ReadUint16()
+		case KindBigUint16:
+			i, err = r.ReadBigUint16()
+		case KindLitUint16:
+			i, err = r.ReadLitUint16()
+		default:
+			i, err = r.ReadUvarint16()
+		}
+		v = uint64(i)
+	case KindInt32, KindBigInt32, KindLitInt32, KindVarint32:
+		var i int32
+		switch op.Kind {
+		case KindInt32:
+			i, err = r.ReadInt32()
+		case KindBigInt32:
+			i, err = r.ReadBigInt32()
+		case KindLitInt32:
+			i, err = r.ReadLitInt32()
+		default:
+			i, err = r.ReadVarint32()
+		}
+		v = uint64(uint32(i))
+	case KindUint32, KindBigUint32, KindLitUint32, KindUvarint32:
+		var i uint32
+		switch op.Kind {
+		case KindUint32:
+			i, err = r.ReadUint32()
+		case KindBigUint32:
+			i, err = r.ReadBigUint32()
+		case KindLitUint32:
+			i, err = r.ReadLitUint32()
+		default:
+			i, err = r.ReadUvarint32()
+		}
+		v = uint64(i)
+	case KindInt64, KindBigInt64, KindLitInt64, KindVarint64:
+		var i int64
+		switch op.Kind {
+		case KindInt64:
+			i, err = r.ReadInt64()
+		case KindBigInt64:
+			i, err = r.ReadBigInt64()
+		case KindLitInt64:
+			i, err = r.ReadLitInt64()
+		default:
+			i, err = r.ReadVarint64()
+		}
+		v = uint64(i)
+	case KindUint64:
+		v, err = r.ReadUint64()
+	case KindBigUint64:
+		v, err = r.ReadBigUint64()
+	case KindLitUint64:
+		v, err = r.ReadLitUint64()
+	case KindUvarint64:
+		v, err = r.ReadUvarint64()
+	case KindFloat32, KindBigFloat32, KindLitFloat32:
+		var f float32
+		switch op.Kind {
+		case KindFloat32:
+			f, err = r.ReadFloat32()
+		case KindBigFloat32:
+			f, err = r.ReadBigFloat32()
+		default:
+			f, err = r.ReadLitFloat32()
+		}
+		v = uint64(math.Float32bits(f))
+	case KindFloat64, KindBigFloat64, KindLitFloat64:
+		var f float64
+		switch op.Kind {
+		case KindFloat64:
+			f, err = r.ReadFloat64()
+		case KindBigFloat64:
+			f, err = r.ReadBigFloat64()
+		default:
+			f, err = r.ReadLitFloat64()
+		}
+		v = math.Float64bits(f)
+	case KindString:
+		var s string
+		if s, err = r.ReadString(); err != nil {
+			return nil, err
+		}
+		return []byte(s), nil
+	case KindRaw:
+		p := make([]byte, len(op.Data))
+		if _, err = io.ReadFull(r, p); err != nil {
+			return nil, err
+		}
+		return p, nil
+	default:
+		panic("buffertest: invalid kind")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// ReadTruncated 自仅包含 ops 编码结果前 cut 个字节的 r 中读取 ops
+// 被截断的操作之前的值须与写入的值一致, 被截断的操作须返回 io.EOF 或
+// io.ErrUnexpectedEOF. 不满足时返回描述原因的错误.
+func ReadTruncated(r buffer.Reader, ops []Op, cut int) error {
+	off := 0
+	for i, op := range ops {
+		end := off + op.Size()
+		if end <= cut {
+			if err := readOp(r, op); err != nil {
+				return fmt.Errorf("op %d %s before cut %d: %w", i, op, cut, err)
+			}
+			off = end
+			continue
+		}
+
+		_, err := readBits(r, op)
+		if err != io.EOF && err != io.ErrUnexpectedEOF {
+			return fmt.Errorf("op %d %s truncated at %d/%d: expected io.EOF or io.ErrUnexpectedEOF, got %v",
+				i, op, cut-off, op.Size(), err)
+		}
+		return nil
+	}
+	return nil
+}
+
+// Compare 自 a 与 b 中依次读取 ops 对应类型的值(忽略 ops 中的值), 两者读取的值及
+// 返回的错误须一致, 用于检查不同实现对任意输入的解码结果是否相同. 读取出错后不再
+// 继续读取.
+func Compare(a, b buffer.Reader, ops []Op) error {
+	for i, op := range ops {
+		va, errA := readBits(a, op)
+		vb, errB := readBits(b, op)
+		if (errA == nil) != (errB == nil) || (errA != nil && errA.Error() != errB.Error()) {
+			return fmt.Errorf("op %d %s: errors differ: %v, %v", i, op.Kind, errA, errB)
+		}
+		if errA != nil {
+			return nil
+		}
+		if !reflect.DeepEqual(va, vb) {
+			return fmt.Errorf("op %d %s: values differ: %#v, %#v", i, op.Kind, va, vb)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package bytes
+
+import "io"
+
+// BitWriter 按位写入数据
+// 位按照从低到高的顺序填充至字节中(LSB first), 写满一个字节即写入底层的 w, w 通常
+// 为 Buffer 或 FixedBuffer. 写入结束后须调用 Flush 写出不足一个字节的剩余位.
+type BitWriter struct {
+	w   io.ByteWriter
+	acc uint64 // 待写出的位
+	n   uint   // acc 中的位数, 调用间始终小于 8
+}
+
+// NewBitWriter 创建写入 w 的 BitWriter
+func NewBitWriter(w io.ByteWriter) *BitWriter {
+	return &BitWriter{w: w}
+}
+
+// WriteBits 写入 v 的低 n 位, n 的取值范围为 [0, 64]
+func (w *BitWriter) WriteBits(v uint64, n int) error {
+	if n < 0 || n > 64 {
+		panic("bytes.BitWriter.WriteBits: n out of range")
+	}
+
+	for n > 0 {
+		// acc 中已有不足 8 位, 每次至多追加 56 位以免溢出
+		k := uint(min(n, 56))
+		w.acc |= (v & (1<<k - 1)) << w.n
+		w.n += k
+		v >>= k
+		n -= int(k)
+
+		for w.n >= 8 {
+			if err := w.w.WriteByte(byte(w.acc)); err != nil {
+				return err
+			}
+			w.acc >>= 8
+			w.n -= 8
+		}
+	}
+	return nil
+}
+
+// WriteBool 以 1 位写入布尔值
+func (w *BitWriter) WriteBool(v bool) error {
+	if v {
+		return w.WriteBits(1, 1)
+	}
+	return w.WriteBits(0, 1)
+}
+
+// Buffered 获取尚未写出的位数
+func (w *BitWriter) Buffered() int {
+	return int(w.n)
+}
+
+// Flush 以 0 填充至字节边界, 并写出剩余的位
+func (w *BitWriter) Flush() error {
+	if w.n == 0 {
+		return nil
+	}
+	if err := w.w.WriteByte(byte(w.acc)); err != nil {
+		return err
+	}
+	w.acc = 0
+	w.n = 0
+	return nil
+}
+
+// BitReader 按位读取 BitWriter 写入的数据
+type BitReader struct {
+	r   io.ByteReader
+	acc uint64 // 已读取但未消费的位
+	n   uint   // acc 中的位数
+}
+
+// NewBitReader 创建读取 r 的 BitReader
+func NewBitReader(r io.ByteReader) *BitReader {
+	return &BitReader{r: r}
+}
+
+// ReadBits 读取 n 位, n 的取值范围为 [0, 64]
+// 未读取到任何位时返回 io.EOF, 读取了部分位时返回 io.ErrUnexpectedEOF.
+func (r *BitReader) ReadBits(n int) (uint64, error) {
+	if n < 0 || n > 64 {
+		panic("bytes.BitReader.ReadBits: n out of range")
+	}
+
+	var v uint64
+	var got uint
+	for got < uint(n) {
+		if r.n == 0 {
+			c, err := r.r.ReadByte()
+			if err != nil {
+				if err == io.EOF && got > 0 {
+					err = io.ErrUnexpectedEOF
+				}
+				return 0, err
+			}
+			r.acc = uint64(c)
+			r.n = 8
+		}
+
+		k := min(uint(n)-got, r.n)
+		v |= (r.acc & (1<<k - 1)) << got
+		r.acc >>= k
+		r.n -= k
+		got += k
+	}
+	return v, nil
+}
+
+// ReadBool 读取 1 位布尔值
+func (r *BitReader) ReadBool() (bool, error) {
+	v, err := r.ReadBits(1)
+	return v == 1, err
+}
+
+// Align 丢弃当前字节中剩余的位, 与 BitWriter.Flush 对应
+func (r *BitReader) Align() {
+	r.acc = 0
+	r.n = 0
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"io"
+	"unsafe"
 
 	"github.com/godyy/gutils/buffer"
 	pkg_errors "github.com/pkg/errors"
@@ -19,23 +20,30 @@ const maxInt = int(^uint(0) >> 1)
 
 // Buffer 变长byte缓冲区
 type Buffer struct {
-	buf []byte
-	off int
+	buf     []byte
+	off     int
+	marks   []int       // 读取位置标记栈, 记录逻辑读取位置
+	shifted int         // 自buf前端丢弃的字节数, 逻辑读取位置 = shifted + off
+	lent    lentTracker // 借出的数据视图
+	lim     *limits     // 解码限制
 }
 
-// NewBufferWithCap 已指定的容量创建Buffer
-func NewBufferWithCap(cap int) *Buffer {
+var _ buffer.ReadWriter = (*Buffer)(nil)
+
+// NewBufferWithCap 已指定的容量创建Buffer, opts 用于配置解码限制
+func NewBufferWithCap(cap int, opts ...Option) *Buffer {
 	if cap < smallBufferSize {
 		cap = smallBufferSize
 	}
 	return &Buffer{
 		buf: make([]byte, 0, cap),
 		off: 0,
+		lim: buildLimits(opts),
 	}
 }
 
-// NewBuffer 指定buf创建Buffer
-func NewBuffer(buf []byte) *Buffer {
+// NewBuffer 指定buf创建Buffer, opts 用于配置解码限制
+func NewBuffer(buf []byte, opts ...Option) *Buffer {
 	if buf == nil {
 		buf = make([]byte, 0, smallBufferSize)
 	}
@@ -43,6 +51,7 @@ func NewBuffer(buf []byte) *Buffer {
 	b := &Buffer{
 		buf: buf,
 		off: 0,
+		lim: buildLimits(opts),
 	}
 	return b
 }
@@ -59,17 +68,25 @@ func (b *Buffer) Cap() int {
 
 // Reset 重置buf
 func (b *Buffer) Reset() {
+	b.lent.release(b.buf, 0, 0)
 	if b.buf != nil {
 		b.buf = b.buf[:0]
 	}
 	b.off = 0
+	b.marks = b.marks[:0]
+	b.shifted = 0
+	b.lim.reset()
 }
 
 // SetBuf 设置buf并返回之前的buf
 func (b *Buffer) SetBuf(buf []byte) []byte {
+	b.lent.release(b.buf, 0, 0)
 	old := b.buf
 	b.buf = buf
 	b.off = 0
+	b.marks = b.marks[:0]
+	b.shifted = 0
+	b.lim.reset()
 	return old
 }
 
@@ -99,8 +116,18 @@ func (b *Buffer) UnreadData() []byte {
 	return b.buf[b.off:]
 }
 
+// Truncate 丢弃未读数据中除前 n 字节外的数据, 用于撤销出错时已写入的部分数据
+// n 小于 0 或大于可读取字节数时 panic.
+func (b *Buffer) Truncate(n int) {
+	if n < 0 || n > b.Readable() {
+		panic("bytes.Buffer.Truncate: n out of range")
+	}
+	b.buf = b.buf[:b.off+n]
+}
+
 // tryGrowByReslice 尝试扩张buf
 func (b *Buffer) tryGrowByReslice(n int) (int, bool) {
+	b.lent.release(b.buf, b.retained(), len(b.buf))
 	if l := len(b.buf); n <= cap(b.buf)-l {
 		b.buf = b.buf[:l+n]
 		return l, true
@@ -135,12 +162,27 @@ func growBuffSlice(b []byte, n int) []byte {
 	return b2[:len(b)]
 }
 
+// retained 获取buf中须保留的数据的起始位置, 存在读取位置标记时为最早的标记位置
+func (b *Buffer) retained() int {
+	if len(b.marks) > 0 {
+		return b.marks[0] - b.shifted
+	}
+	return b.off
+}
+
+// discard 丢弃buf前端的n个字节, 调用方负责移动数据
+func (b *Buffer) discard(n int) {
+	b.off -= n
+	b.shifted += n
+}
+
 // grow 扩张buf使其能容纳n个字节
 func (b *Buffer) grow(n int) int {
-	m := b.Readable()
 	// If buffer is empty, reset to recover space.
-	if m == 0 && b.off != 0 {
-		b.Reset()
+	if b.Readable() == 0 && b.off != 0 && len(b.marks) == 0 {
+		b.lent.release(b.buf, 0, 0)
+		b.discard(b.off)
+		b.buf = b.buf[:0]
 	}
 	// Try to grow by means of a reslice.
 	if i, ok := b.tryGrowByReslice(n); ok {
@@ -150,21 +192,24 @@ func (b *Buffer) grow(n int) int {
 		b.buf = make([]byte, n, smallBufferSize)
 		return 0
 	}
+	// 存在读取位置标记时, 保留最早的标记之后的数据
+	keep := b.retained()
+	m := len(b.buf) - keep
 	c := cap(b.buf)
 	if n <= c/2-m {
 		// We can slide things down instead of allocating a new
 		// slice. We only need m+n <= c to slide, but
 		// we instead let capacity get twice as large so we
 		// don't spend all our time copying.
-		copy(b.buf, b.buf[b.off:])
+		copy(b.buf, b.buf[keep:])
 	} else if c > maxInt-c-n {
 		panic(ErrBufferTooLarge)
 	} else {
-		// Add b.off to account for b.buf[:b.off] being sliced off the front.
-		b.buf = growBuffSlice(b.buf[b.off:], b.off+n)
+		// Add keep to account for b.buf[:keep] being sliced off the front.
+		b.buf = growBuffSlice(b.buf[keep:], keep+n)
 	}
 	// Restore b.off and len(b.buf).
-	b.off = 0
+	b.discard(keep)
 	b.buf = b.buf[:m+n]
 
 	return m
@@ -474,37 +519,46 @@ func (b *Buffer) Write(p []byte) (n int, err error) {
 	return copy(b.buf[m:], p), nil
 }
 
-func (b *Buffer) ReadString() (string, error) {
+// peekLenPrefixed 获取以 varint 长度为前缀的数据在buf中的视图, 以及包含长度前缀
+// 在内占用的字节数, 不移动读取位置
+func (b *Buffer) peekLenPrefixed(k lenKind) ([]byte, int, error) {
 	i, n := binary.Varint(b.buf[b.off:])
 	if n == 0 {
-		return "", io.EOF
+		return nil, 0, io.EOF
 	}
 	if n < 0 || n > MaxStringLenLen {
-		return "", pkg_errors.WithMessage(ErrVarintOverflow, "read length")
+		return nil, 0, pkg_errors.WithMessage(ErrVarintOverflow, "read length")
 	}
 
 	l := int(i)
-	if l < 0 {
-		return "", buffer.ErrStringLenExceedLimit
+	if err := b.lim.checkRead(k, l); err != nil {
+		return nil, 0, err
 	}
 
 	if l+n > b.Readable() {
-		return "", io.ErrUnexpectedEOF
+		return nil, 0, io.ErrUnexpectedEOF
 	}
 
-	b.off += n
-	if l == 0 {
-		return "", nil
+	return b.buf[b.off+n : b.off+n+l : b.off+n+l], n + l, nil
+}
+
+// readLenPrefixed 读取以 varint 长度为前缀的数据, 返回buf中数据的视图
+// 长度超过限制时返回错误, 且不移动读取位置.
+func (b *Buffer) readLenPrefixed(k lenKind) ([]byte, error) {
+	p, n, err := b.peekLenPrefixed(k)
+	if err != nil {
+		return nil, err
 	}
-	s := string(b.buf[b.off : b.off+l])
-	b.off += l
-	return s, nil
+	b.off += n
+	b.lim.addDecoded(len(p))
+	return p, nil
 }
 
-func (b *Buffer) WriteString(s string) error {
-	l := len(s)
-	if l > MaxStringLength {
-		return buffer.ErrStringLenExceedLimit
+// bufferWriteLenPrefixed 写入以 varint 长度为前缀的数据
+func bufferWriteLenPrefixed[T ~[]byte | ~string](b *Buffer, k lenKind, p T) error {
+	l := len(p)
+	if err := b.lim.checkWrite(k, l); err != nil {
+		return err
 	}
 
 	var buf [MaxStringLenLen]byte
@@ -517,10 +571,71 @@ func (b *Buffer) WriteString(s string) error {
 
 	copy(b.buf[m:m+ll], buf[:ll])
 	m = m + ll
-	copy(b.buf[m:m+l], s)
+	copy(b.buf[m:m+l], p)
 	return nil
 }
 
+func (b *Buffer) ReadString() (string, error) {
+	p, err := b.readLenPrefixed(lenString)
+	if err != nil || len(p) == 0 {
+		return "", err
+	}
+	return string(p), nil
+}
+
+func (b *Buffer) WriteString(s string) error {
+	return bufferWriteLenPrefixed(b, lenString, s)
+}
+
+// ReadBytes 读取 WriteBytes 写入的字节切片, 编码与字符串一致, 长度为 0 时返回 nil
+func (b *Buffer) ReadBytes() ([]byte, error) {
+	p, err := b.readLenPrefixed(lenBytes)
+	if err != nil || len(p) == 0 {
+		return nil, err
+	}
+	return append([]byte(nil), p...), nil
+}
+
+// WriteBytes 写入以 varint 长度为前缀的字节切片
+func (b *Buffer) WriteBytes(p []byte) error {
+	return bufferWriteLenPrefixed(b, lenBytes, p)
+}
+
+// ReadStringNoCopy 读取字符串, 返回的字符串直接引用buf中的数据, 不分配内存
+// 返回值仅在下一次写入、Grow、Reset 或 SetBuf 之前有效, 此后其内容可能被覆盖,
+// 需要长期持有时应使用 ReadString. 以 gutils_bufdebug 构建标签编译时, 失效的数据
+// 将被填充为 0xdd, 以便在测试中发现误用.
+func (b *Buffer) ReadStringNoCopy() (string, error) {
+	p, err := b.readLenPrefixed(lenString)
+	if err != nil || len(p) == 0 {
+		return "", err
+	}
+	b.lent.lend(p)
+	return unsafe.String(&p[0], len(p)), nil
+}
+
+// ReadBytesNoCopy 读取字节切片, 返回的切片直接引用buf中的数据, 有效期与
+// ReadStringNoCopy 相同. 调用方不得修改返回的切片.
+func (b *Buffer) ReadBytesNoCopy() ([]byte, error) {
+	p, err := b.readLenPrefixed(lenBytes)
+	if err != nil || len(p) == 0 {
+		return nil, err
+	}
+	b.lent.lend(p)
+	return p, nil
+}
+
+// PeekString 获取下一个字符串但不移动读取位置, 返回的字符串直接引用buf中的数据,
+// 有效期与 ReadStringNoCopy 相同
+func (b *Buffer) PeekString() (string, error) {
+	p, _, err := b.peekLenPrefixed(lenString)
+	if err != nil || len(p) == 0 {
+		return "", err
+	}
+	b.lent.lend(p)
+	return unsafe.String(&p[0], len(p)), nil
+}
+
 func (b *Buffer) ReadFrom(r io.Reader) (int64, error) {
 	l := len(b.buf)
 	c := cap(b.buf)
@@ -102,11 +102,11 @@ func (b *Buffer) writeUint64(i uint64, bo byteOrder) error {
 }
 
 func (b *Buffer) readFloat32(bo byteOrder) (float32, error) {
-	var buf [4]byte
-	if _, err := b.Read(buf[:]); err != nil {
+	i, err := b.readUint32(bo)
+	if err != nil {
 		return 0, err
 	}
-	return math.Float32frombits(bo.Uint32(buf[:])), nil
+	return math.Float32frombits(i), nil
 }
 
 func (b *Buffer) writeFloat32(f float32, bo byteOrder) error {
@@ -117,11 +117,11 @@ func (b *Buffer) writeFloat32(f float32, bo byteOrder) error {
 }
 
 func (b *Buffer) readFloat64(bo byteOrder) (float64, error) {
-	var buf [8]byte
-	if _, err := b.Read(buf[:]); err != nil {
+	i, err := b.readUint64(bo)
+	if err != nil {
 		return 0, err
 	}
-	return math.Float64frombits(bo.Uint64(buf[:])), nil
+	return math.Float64frombits(i), nil
 }
 
 func (b *Buffer) writeFloat64(f float64, bo byteOrder) error {
@@ -288,3 +288,35 @@ func TestBuffer(t *testing.T) {
 	}
 
 }
+
+func TestBuffer_Truncate(t *testing.T) {
+	b := NewBuffer(nil)
+	_ = b.WriteString("hello")
+	_, _ = b.ReadByte()
+	_, _ = b.Write([]byte("world"))
+
+	b.Truncate(5)
+	if got := string(b.UnreadData()); got != "hello" {
+		t.Fatalf("expected %q, got %q", "hello", got)
+	}
+	// 截断后继续写入覆盖被丢弃的数据
+	_ = b.WriteByte('!')
+	if got := string(b.UnreadData()); got != "hello!" {
+		t.Fatalf("expected %q, got %q", "hello!", got)
+	}
+	b.Truncate(0)
+	if b.Readable() != 0 {
+		t.Fatalf("expected no unread data, got %d", b.Readable())
+	}
+
+	for _, n := range []int{-1, 1} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("Truncate(%d): expected panic", n)
+				}
+			}()
+			b.Truncate(n)
+		}()
+	}
+}
@@ -0,0 +1,210 @@
+package bytes
+
+import (
+	"io"
+	"net"
+)
+
+// defaultChunkSize ChainBuffer 默认的数据块大小
+const defaultChunkSize = 4 << 10
+
+// chainPool ChainBuffer 未指定 Pool 时使用的默认 Pool
+var chainPool = NewPool()
+
+// chunk ChainBuffer 的数据块
+type chunk struct {
+	data []byte       // 数据
+	r, w int          // 读写位置
+	fb   *FixedBuffer // 数据所属的池化缓冲区, 引用外部数据时为 nil
+	next *chunk
+}
+
+// ChainBuffer 链式字节缓冲区
+// 数据存储在一组定长数据块组成的链表中, 数据块取自 Pool. 写入大量数据时只需追加
+// 数据块, 不会像 Buffer 扩容那样复制已有数据; 同时支持以引用方式追加外部数据.
+type ChainBuffer struct {
+	pool      *Pool
+	chunkSize int
+	head      *chunk
+	tail      *chunk
+	n         int         // 可读数据长度
+	vec       net.Buffers // WriteTo 使用的向量缓存
+}
+
+// NewChainBuffer 创建ChainBuffer, 数据块大小为 chunkSize, 数据块取自 pool
+// chunkSize <= 0 时使用默认大小, pool 为 nil 时使用默认 Pool.
+func NewChainBuffer(chunkSize int, pool *Pool) *ChainBuffer {
+	if chunkSize <= 0 {
+		chunkSize = defaultChunkSize
+	}
+	if pool == nil {
+		pool = chainPool
+	}
+	return &ChainBuffer{
+		pool:      pool,
+		chunkSize: chunkSize,
+	}
+}
+
+// Readable 获取可读取数据长度
+func (b *ChainBuffer) Readable() int {
+	return b.n
+}
+
+// Chunks 获取数据块的数量
+func (b *ChainBuffer) Chunks() int {
+	n := 0
+	for c := b.head; c != nil; c = c.next {
+		n++
+	}
+	return n
+}
+
+// Reset 清空数据, 并将数据块归还至 Pool
+func (b *ChainBuffer) Reset() {
+	for c := b.head; c != nil; {
+		next := c.next
+		b.release(c)
+		c = next
+	}
+	b.head, b.tail = nil, nil
+	b.n = 0
+}
+
+// release 释放数据块
+func (b *ChainBuffer) release(c *chunk) {
+	if c.fb != nil {
+		b.pool.PutFixed(c.fb)
+	}
+	c.data, c.fb, c.next = nil, nil, nil
+}
+
+// push 追加数据块至链表尾部
+func (b *ChainBuffer) push(c *chunk) {
+	if b.tail == nil {
+		b.head = c
+	} else {
+		b.tail.next = c
+	}
+	b.tail = c
+}
+
+// writableChunk 获取可写入数据的尾部数据块, 必要时自 Pool 中获取新的数据块
+func (b *ChainBuffer) writableChunk() *chunk {
+	if c := b.tail; c != nil && c.fb != nil && c.w < len(c.data) {
+		return c
+	}
+	fb := b.pool.GetFixed(b.chunkSize)
+	c := &chunk{data: fb.buf, fb: fb}
+	b.push(c)
+	return c
+}
+
+// pop 移除已读完的头部数据块
+func (b *ChainBuffer) pop() {
+	c := b.head
+	b.head = c.next
+	if b.head == nil {
+		b.tail = nil
+	}
+	b.release(c)
+}
+
+// consume 丢弃 n 个字节的未读数据
+func (b *ChainBuffer) consume(n int) {
+	b.n -= n
+	for n > 0 {
+		c := b.head
+		if m := c.w - c.r; m > n {
+			c.r += n
+			return
+		}
+		n -= c.w - c.r
+		b.pop()
+	}
+}
+
+func (b *ChainBuffer) Write(p []byte) (n int, err error) {
+	for len(p) > 0 {
+		c := b.writableChunk()
+		m := copy(c.data[c.w:], p)
+		c.w += m
+		b.n += m
+		n += m
+		p = p[m:]
+	}
+	return n, nil
+}
+
+func (b *ChainBuffer) WriteByte(c byte) error {
+	ch := b.writableChunk()
+	ch.data[ch.w] = c
+	ch.w++
+	b.n++
+	return nil
+}
+
+// AppendRef 以引用的方式追加 p, 不复制数据
+// p 被完全读取或调用 Reset 之前, 调用方不得修改 p.
+func (b *ChainBuffer) AppendRef(p []byte) {
+	if len(p) == 0 {
+		return
+	}
+	b.push(&chunk{data: p, w: len(p)})
+	b.n += len(p)
+}
+
+func (b *ChainBuffer) Read(p []byte) (int, error) {
+	if len(p) <= 0 {
+		return 0, io.ErrShortBuffer
+	}
+
+	if b.n == 0 {
+		return 0, io.EOF
+	}
+
+	n := 0
+	for c := b.head; c != nil && n < len(p); c = c.next {
+		n += copy(p[n:], c.data[c.r:c.w])
+	}
+	b.consume(n)
+	return n, nil
+}
+
+func (b *ChainBuffer) ReadByte() (byte, error) {
+	if b.n == 0 {
+		return 0, io.EOF
+	}
+
+	c := b.head
+	for c.r == c.w {
+		c = c.next
+	}
+	x := c.data[c.r]
+	b.consume(1)
+	return x, nil
+}
+
+// WriteTo 将所有未读数据写入 w
+// 通过 net.Buffers 写出全部数据块, 若 w 为 net.Conn 则以一次向量化写入完成.
+func (b *ChainBuffer) WriteTo(w io.Writer) (int64, error) {
+	if b.n == 0 {
+		return 0, nil
+	}
+
+	b.vec = b.vec[:0]
+	for c := b.head; c != nil; c = c.next {
+		if c.r < c.w {
+			b.vec = append(b.vec, c.data[c.r:c.w])
+		}
+	}
+	vec := b.vec
+	n, err := vec.WriteTo(w)
+	clear(b.vec)
+	if n > int64(b.n) {
+		panic("bytes.ChainBuffer.WriteTo: invalid Write count")
+	}
+
+	b.consume(int(n))
+	return n, err
+}
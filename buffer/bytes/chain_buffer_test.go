@@ -0,0 +1,89 @@
+package bytes
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestChainBuffer(t *testing.T) {
+	b := NewChainBuffer(64, NewPool())
+
+	head := strings.Repeat("h", 100)
+	ref := []byte(strings.Repeat("r", 50))
+	tail := strings.Repeat("t", 30)
+
+	if n, err := b.Write([]byte(head)); err != nil || n != len(head) {
+		t.Fatalf("write head: %d, %v", n, err)
+	}
+	b.AppendRef(ref)
+	_, _ = b.Write([]byte(tail))
+	if b.Readable() != 180 {
+		t.Fatalf("expected 180 readable bytes, got %d", b.Readable())
+	}
+	if b.Chunks() != 4 {
+		t.Fatalf("expected 4 chunks, got %d", b.Chunks())
+	}
+
+	if c, err := b.ReadByte(); err != nil || c != 'h' {
+		t.Fatalf("read byte: %c, %v", c, err)
+	}
+	p := make([]byte, 70)
+	if n, err := b.Read(p); err != nil || n != 70 || string(p) != head[1:71] {
+		t.Fatalf("read: %d, %v", n, err)
+	}
+	if b.Chunks() != 3 {
+		t.Fatalf("expected 3 chunks after read, got %d", b.Chunks())
+	}
+
+	var dst bytes.Buffer
+	if n, err := b.WriteTo(&dst); err != nil || n != 109 {
+		t.Fatalf("write to: %d, %v", n, err)
+	}
+	if dst.String() != head[71:]+string(ref)+tail {
+		t.Fatalf("write to: unexpected data %q", dst.String())
+	}
+	if b.Readable() != 0 || b.Chunks() != 0 {
+		t.Fatalf("expected empty buffer, got %d bytes in %d chunks", b.Readable(), b.Chunks())
+	}
+	if _, err := b.Read(p); err != io.EOF {
+		t.Fatalf("read empty buffer: expected io.EOF, got %v", err)
+	}
+}
+
+func TestChainBuffer_Reset(t *testing.T) {
+	pool := NewPool(WithStats)
+	b := NewChainBuffer(128, pool)
+	_, _ = b.Write(make([]byte, 1000))
+	b.AppendRef(make([]byte, 10))
+	b.Reset()
+	if b.Readable() != 0 || b.Chunks() != 0 {
+		t.Fatalf("expected empty buffer after reset")
+	}
+	if stats := pool.Stats(); stats.Retained != 8*128 {
+		t.Fatalf("expected 8 chunks retained, got %+v", stats)
+	}
+}
+
+func BenchmarkChainBuffer_Write(b *testing.B) {
+	data := make([]byte, 1<<20)
+	cb := NewChainBuffer(0, nil)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		_, _ = cb.Write(data)
+		_, _ = cb.WriteTo(io.Discard)
+	}
+}
+
+func BenchmarkBuffer_Write(b *testing.B) {
+	data := make([]byte, 1<<20)
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		buf := NewBuffer(nil)
+		for j := 0; j < len(data); j += 4 << 10 {
+			_, _ = buf.Write(data[j : j+4<<10])
+		}
+		_, _ = buf.WriteTo(io.Discard)
+	}
+}
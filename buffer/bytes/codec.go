@@ -0,0 +1,854 @@
+package bytes
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/godyy/gutils/buffer"
+	pkg_errors "github.com/pkg/errors"
+)
+
+// ErrUnsupportedType 不支持编解码的类型
+var ErrUnsupportedType = errors.New("bytes: unsupported type")
+
+// ErrInvalidTag 无效的 buf 标签
+var ErrInvalidTag = errors.New("bytes: invalid buf tag")
+
+// ErrInvalidUnmarshal 解码目标不是非 nil 指针
+var ErrInvalidUnmarshal = errors.New("bytes: unmarshal requires non-nil pointer")
+
+// TagName 结构体字段标签名
+//
+// 支持的标签值:
+//   - ""       使用本地字节序定长编码, 与 WriteInt32 等方法一致
+//   - "big"    使用大端字节序定长编码, 与 WriteBigInt32 等方法一致
+//   - "lit"    使用小端字节序定长编码, 与 WriteLitInt32 等方法一致
+//   - "varint" 使用 varint 编码, 有符号整数与 WriteVarint32 等方法一致,
+//     无符号整数与 WriteUvarint32 等方法一致
+//   - "-"      忽略该字段
+//
+// 标签作用于字段中的标量值, 对于切片、数组、map 以及指针, 作用于其元素.
+const TagName = "buf"
+
+// encoding 标量编码方式
+type encoding int8
+
+const (
+	encNative encoding = iota // 本地字节序
+	encBig                    // 大端字节序
+	encLit                    // 小端字节序
+	encVarint                 // varint
+)
+
+// parseEncoding 解析 buf 标签, skip 表示忽略该字段
+func parseEncoding(tag string) (e encoding, skip bool, err error) {
+	switch strings.TrimSpace(tag) {
+	case "":
+		return encNative, false, nil
+	case "big":
+		return encBig, false, nil
+	case "lit":
+		return encLit, false, nil
+	case "varint":
+		return encVarint, false, nil
+	case "-":
+		return 0, true, nil
+	default:
+		return 0, false, pkg_errors.WithMessagef(ErrInvalidTag, "%q", tag)
+	}
+}
+
+// Marshal 通过反射将 v 编码为字节序列
+//
+// 支持布尔、整数、浮点、字符串、结构体、切片、数组、map 以及指针类型. 编码结果
+// 与依次手动调用 Buffer 对应的 Write* 方法完全一致:
+//   - 字符串使用 WriteString 编码
+//   - 切片及 map 先以 varint 编码长度(与字符串长度前缀一致), 再依次编码元素,
+//     []byte 的编码与字符串一致
+//   - 数组仅依次编码元素
+//   - map 按照键的升序编码, 键只能是整数、浮点或字符串类型
+//   - 指针先以 WriteBool 编码是否为 nil, 非 nil 时再编码指向的值
+//   - 结构体按照字段声明顺序编码所有导出字段, 编码方式由 buf 标签控制
+func Marshal(v any) ([]byte, error) {
+	b := NewBuffer(nil)
+	if err := b.WriteValue(v); err != nil {
+		return nil, err
+	}
+	return b.Data(), nil
+}
+
+// Unmarshal 将 Marshal 编码的数据解码至 v, v 必须是非 nil 指针
+func Unmarshal(data []byte, v any) error {
+	return NewBuffer(data).ReadValue(v)
+}
+
+// WriteValue 通过反射将 v 编码写入 buf, 编码规则参见 Marshal
+// 若 v 为指针, 则编码其指向的值, 与 ReadValue 对应
+func (b *Buffer) WriteValue(v any) error {
+	return EncodeValue(b, v)
+}
+
+// ReadValue 通过反射自 buf 中解码数据至 v, v 必须是非 nil 指针
+func (b *Buffer) ReadValue(v any) error {
+	return DecodeValue(b, v)
+}
+
+// WriteValue 通过反射将 v 编码写入 buf, 编码规则参见 Marshal
+func (b *FixedBuffer) WriteValue(v any) error {
+	return EncodeValue(b, v)
+}
+
+// ReadValue 通过反射自 buf 中解码数据至 v, v 必须是非 nil 指针
+func (b *FixedBuffer) ReadValue(v any) error {
+	return DecodeValue(b, v)
+}
+
+// EncodeValue 通过反射将 v 编码写入 w, 编码规则参见 Marshal
+// 若 v 为指针, 则编码其指向的值, 与 DecodeValue 对应
+func EncodeValue(w buffer.Writer, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Kind() == reflect.Pointer {
+		return pkg_errors.WithMessage(ErrUnsupportedType, "nil")
+	}
+	c, err := codecFor(rv.Type(), encNative)
+	if err != nil {
+		return err
+	}
+	return c.enc(w, rv)
+}
+
+// DecodeValue 通过反射自 r 中解码数据至 v, v 必须是非 nil 指针
+// 未读取任何数据时返回 io.EOF, 读取了部分数据时返回 io.ErrUnexpectedEOF.
+func DecodeValue(r buffer.Reader, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return ErrInvalidUnmarshal
+	}
+	rv = rv.Elem()
+	c, err := codecFor(rv.Type(), encNative)
+	if err != nil {
+		return err
+	}
+
+	offset, ok := readOffset(r)
+	if err := c.dec(r, rv); err != nil {
+		if err == io.EOF && ok {
+			if o, _ := readOffset(r); o != offset {
+				err = io.ErrUnexpectedEOF
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// readableReader 可获取可读数据长度的 Reader
+type readableReader interface {
+	Readable() int
+}
+
+// readOffset 获取 r 当前的读取进度, 仅用于比较是否读取了数据
+func readOffset(r buffer.Reader) (int64, bool) {
+	switch r := r.(type) {
+	case readableReader:
+		return -int64(r.Readable()), true
+	case *IOReader:
+		return r.n, true
+	default:
+		return 0, false
+	}
+}
+
+// maxAllocHint 无法获取可读数据长度时, 解码切片或 map 预分配的最大元素数量
+const maxAllocHint = 1024
+
+// allocHint 获取解码 n 个元素时预分配的数量, 避免恶意长度导致的过量分配
+func allocHint(r buffer.Reader, n int) int {
+	if rr, ok := r.(readableReader); ok {
+		return min(n, rr.Readable())
+	}
+	return min(n, maxAllocHint)
+}
+
+type encodeFunc func(b buffer.Writer, v reflect.Value) error
+type decodeFunc func(b buffer.Reader, v reflect.Value) error
+
+// typeCodec 类型编解码器
+type typeCodec struct {
+	enc encodeFunc
+	dec decodeFunc
+}
+
+type codecKey struct {
+	t reflect.Type
+	e encoding
+}
+
+// codecCache 缓存已编译的类型编解码器
+var codecCache sync.Map // codecKey -> *typeCodec
+
+// codecFor 获取类型 t 以编码方式 e 编解码的编解码器
+func codecFor(t reflect.Type, e encoding) (*typeCodec, error) {
+	key := codecKey{t: t, e: e}
+	if c, ok := codecCache.Load(key); ok {
+		return c.(*typeCodec), nil
+	}
+
+	compiling := make(map[codecKey]*typeCodec)
+	c, err := compileCodec(t, e, compiling)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range compiling {
+		codecCache.LoadOrStore(k, v)
+	}
+	return c, nil
+}
+
+// compileCodec 编译类型编解码器, compiling 用于处理递归类型
+func compileCodec(t reflect.Type, e encoding, compiling map[codecKey]*typeCodec) (*typeCodec, error) {
+	key := codecKey{t: t, e: e}
+	if c, ok := compiling[key]; ok {
+		return c, nil
+	}
+	if c, ok := codecCache.Load(key); ok {
+		return c.(*typeCodec), nil
+	}
+
+	c := &typeCodec{}
+	compiling[key] = c
+
+	var err error
+	switch t.Kind() {
+	case reflect.Bool:
+		c.enc, c.dec = encodeBool, decodeBool
+	case reflect.Int8, reflect.Uint8:
+		c.enc, c.dec = encodeByte, decodeByte
+	case reflect.Int16:
+		c.enc, c.dec = int16Codec(e)
+	case reflect.Int32:
+		c.enc, c.dec = int32Codec(e)
+	case reflect.Int64, reflect.Int:
+		c.enc, c.dec = int64Codec(e)
+	case reflect.Uint16:
+		c.enc, c.dec = uint16Codec(e)
+	case reflect.Uint32:
+		c.enc, c.dec = uint32Codec(e)
+	case reflect.Uint64, reflect.Uint:
+		c.enc, c.dec = uint64Codec(e)
+	case reflect.Float32:
+		c.enc, c.dec, err = float32Codec(e)
+	case reflect.Float64:
+		c.enc, c.dec, err = float64Codec(e)
+	case reflect.String:
+		c.enc, c.dec = encodeString, decodeString
+	case reflect.Slice:
+		err = compileSlice(c, t, e, compiling)
+	case reflect.Array:
+		err = compileArray(c, t, e, compiling)
+	case reflect.Map:
+		err = compileMap(c, t, e, compiling)
+	case reflect.Pointer:
+		err = compilePointer(c, t, e, compiling)
+	case reflect.Struct:
+		err = compileStruct(c, t, compiling)
+	default:
+		err = pkg_errors.WithMessage(ErrUnsupportedType, t.String())
+	}
+
+	if err != nil {
+		delete(compiling, key)
+		return nil, err
+	}
+	return c, nil
+}
+
+func encodeBool(b buffer.Writer, v reflect.Value) error {
+	return b.WriteBool(v.Bool())
+}
+
+func decodeBool(b buffer.Reader, v reflect.Value) error {
+	x, err := b.ReadBool()
+	if err != nil {
+		return err
+	}
+	v.SetBool(x)
+	return nil
+}
+
+func encodeByte(b buffer.Writer, v reflect.Value) error {
+	if v.CanInt() {
+		return b.WriteInt8(int8(v.Int()))
+	}
+	return b.WriteUint8(uint8(v.Uint()))
+}
+
+func decodeByte(b buffer.Reader, v reflect.Value) error {
+	x, err := b.ReadByte()
+	if err != nil {
+		return err
+	}
+	if v.CanInt() {
+		v.SetInt(int64(int8(x)))
+	} else {
+		v.SetUint(uint64(x))
+	}
+	return nil
+}
+
+func int16Codec(e encoding) (encodeFunc, decodeFunc) {
+	var (
+		write func(buffer.Writer, int16) error
+		read  func(buffer.Reader) (int16, error)
+	)
+	switch e {
+	case encBig:
+		write, read = buffer.Writer.WriteBigInt16, buffer.Reader.ReadBigInt16
+	case encLit:
+		write, read = buffer.Writer.WriteLitInt16, buffer.Reader.ReadLitInt16
+	case encVarint:
+		write = func(b buffer.Writer, i int16) error { _, err := b.WriteVarint16(i); return err }
+		read = buffer.Reader.ReadVarint16
+	default:
+		write, read = buffer.Writer.WriteInt16, buffer.Reader.ReadInt16
+	}
+	return func(b buffer.Writer, v reflect.Value) error {
+			return write(b, int16(v.Int()))
+		}, func(b buffer.Reader, v reflect.Value) error {
+			x, err := read(b)
+			if err != nil {
+				return err
+			}
+			v.SetInt(int64(x))
+			return nil
+		}
+}
+
+func int32Codec(e encoding) (encodeFunc, decodeFunc) {
+	var (
+		write func(buffer.Writer, int32) error
+		read  func(buffer.Reader) (int32, error)
+	)
+	switch e {
+	case encBig:
+		write, read = buffer.Writer.WriteBigInt32, buffer.Reader.ReadBigInt32
+	case encLit:
+		write, read = buffer.Writer.WriteLitInt32, buffer.Reader.ReadLitInt32
+	case encVarint:
+		write = func(b buffer.Writer, i int32) error { _, err := b.WriteVarint32(i); return err }
+		read = buffer.Reader.ReadVarint32
+	default:
+		write, read = buffer.Writer.WriteInt32, buffer.Reader.ReadInt32
+	}
+	return func(b buffer.Writer, v reflect.Value) error {
+			return write(b, int32(v.Int()))
+		}, func(b buffer.Reader, v reflect.Value) error {
+			x, err := read(b)
+			if err != nil {
+				return err
+			}
+			v.SetInt(int64(x))
+			return nil
+		}
+}
+
+func int64Codec(e encoding) (encodeFunc, decodeFunc) {
+	var (
+		write func(buffer.Writer, int64) error
+		read  func(buffer.Reader) (int64, error)
+	)
+	switch e {
+	case encBig:
+		write, read = buffer.Writer.WriteBigInt64, buffer.Reader.ReadBigInt64
+	case encLit:
+		write, read = buffer.Writer.WriteLitInt64, buffer.Reader.ReadLitInt64
+	case encVarint:
+		write = func(b buffer.Writer, i int64) error { _, err := b.WriteVarint64(i); return err }
+		read = buffer.Reader.ReadVarint64
+	default:
+		write, read = buffer.Writer.WriteInt64, buffer.Reader.ReadInt64
+	}
+	return func(b buffer.Writer, v reflect.Value) error {
+			return write(b, v.Int())
+		}, func(b buffer.Reader, v reflect.Value) error {
+			x, err := read(b)
+			if err != nil {
+				return err
+			}
+			v.SetInt(x)
+			return nil
+		}
+}
+
+func uint16Codec(e encoding) (encodeFunc, decodeFunc) {
+	var (
+		write func(buffer.Writer, uint16) error
+		read  func(buffer.Reader) (uint16, error)
+	)
+	switch e {
+	case encBig:
+		write, read = buffer.Writer.WriteBigUint16, buffer.Reader.ReadBigUint16
+	case encLit:
+		write, read = buffer.Writer.WriteLitUint16, buffer.Reader.ReadLitUint16
+	case encVarint:
+		write = func(b buffer.Writer, i uint16) error { _, err := b.WriteUvarint16(i); return err }
+		read = buffer.Reader.ReadUvarint16
+	default:
+		write, read = buffer.Writer.WriteUint16, buffer.Reader.ReadUint16
+	}
+	return func(b buffer.Writer, v reflect.Value) error {
+			return write(b, uint16(v.Uint()))
+		}, func(b buffer.Reader, v reflect.Value) error {
+			x, err := read(b)
+			if err != nil {
+				return err
+			}
+			v.SetUint(uint64(x))
+			return nil
+		}
+}
+
+func uint32Codec(e encoding) (encodeFunc, decodeFunc) {
+	var (
+		write func(buffer.Writer, uint32) error
+		read  func(buffer.Reader) (uint32, error)
+	)
+	switch e {
+	case encBig:
+		write, read = buffer.Writer.WriteBigUint32, buffer.Reader.ReadBigUint32
+	case encLit:
+		write, read = buffer.Writer.WriteLitUint32, buffer.Reader.ReadLitUint32
+	case encVarint:
+		write = func(b buffer.Writer, i uint32) error { _, err := b.WriteUvarint32(i); return err }
+		read = buffer.Reader.ReadUvarint32
+	default:
+		write, read = buffer.Writer.WriteUint32, buffer.Reader.ReadUint32
+	}
+	return func(b buffer.Writer, v reflect.Value) error {
+			return write(b, uint32(v.Uint()))
+		}, func(b buffer.Reader, v reflect.Value) error {
+			x, err := read(b)
+			if err != nil {
+				return err
+			}
+			v.SetUint(uint64(x))
+			return nil
+		}
+}
+
+func uint64Codec(e encoding) (encodeFunc, decodeFunc) {
+	var (
+		write func(buffer.Writer, uint64) error
+		read  func(buffer.Reader) (uint64, error)
+	)
+	switch e {
+	case encBig:
+		write, read = buffer.Writer.WriteBigUint64, buffer.Reader.ReadBigUint64
+	case encLit:
+		write, read = buffer.Writer.WriteLitUint64, buffer.Reader.ReadLitUint64
+	case encVarint:
+		write = func(b buffer.Writer, i uint64) error { _, err := b.WriteUvarint64(i); return err }
+		read = buffer.Reader.ReadUvarint64
+	default:
+		write, read = buffer.Writer.WriteUint64, buffer.Reader.ReadUint64
+	}
+	return func(b buffer.Writer, v reflect.Value) error {
+			return write(b, v.Uint())
+		}, func(b buffer.Reader, v reflect.Value) error {
+			x, err := read(b)
+			if err != nil {
+				return err
+			}
+			v.SetUint(x)
+			return nil
+		}
+}
+
+func float32Codec(e encoding) (encodeFunc, decodeFunc, error) {
+	var (
+		write func(buffer.Writer, float32) error
+		read  func(buffer.Reader) (float32, error)
+	)
+	switch e {
+	case encBig:
+		write, read = buffer.Writer.WriteBigFloat32, buffer.Reader.ReadBigFloat32
+	case encLit:
+		write, read = buffer.Writer.WriteLitFloat32, buffer.Reader.ReadLitFloat32
+	case encVarint:
+		return nil, nil, pkg_errors.WithMessage(ErrInvalidTag, "varint on float32")
+	default:
+		write, read = buffer.Writer.WriteFloat32, buffer.Reader.ReadFloat32
+	}
+	return func(b buffer.Writer, v reflect.Value) error {
+			return write(b, float32(v.Float()))
+		}, func(b buffer.Reader, v reflect.Value) error {
+			x, err := read(b)
+			if err != nil {
+				return err
+			}
+			v.SetFloat(float64(x))
+			return nil
+		}, nil
+}
+
+func float64Codec(e encoding) (encodeFunc, decodeFunc, error) {
+	var (
+		write func(buffer.Writer, float64) error
+		read  func(buffer.Reader) (float64, error)
+	)
+	switch e {
+	case encBig:
+		write, read = buffer.Writer.WriteBigFloat64, buffer.Reader.ReadBigFloat64
+	case encLit:
+		write, read = buffer.Writer.WriteLitFloat64, buffer.Reader.ReadLitFloat64
+	case encVarint:
+		return nil, nil, pkg_errors.WithMessage(ErrInvalidTag, "varint on float64")
+	default:
+		write, read = buffer.Writer.WriteFloat64, buffer.Reader.ReadFloat64
+	}
+	return func(b buffer.Writer, v reflect.Value) error {
+			return write(b, v.Float())
+		}, func(b buffer.Reader, v reflect.Value) error {
+			x, err := read(b)
+			if err != nil {
+				return err
+			}
+			v.SetFloat(x)
+			return nil
+		}, nil
+}
+
+func encodeString(b buffer.Writer, v reflect.Value) error {
+	return b.WriteString(v.String())
+}
+
+func decodeString(b buffer.Reader, v reflect.Value) error {
+	s, err := b.ReadString()
+	if err != nil {
+		return err
+	}
+	v.SetString(s)
+	return nil
+}
+
+// writeLen 写入切片或 map 的长度, 与字符串长度前缀编码一致
+func writeLen(b buffer.Writer, l int) error {
+	if l > MaxStringLength {
+		return buffer.ErrExceedBufferLimit
+	}
+	_, err := b.WriteVarint32(int32(l))
+	return err
+}
+
+// readLen 读取切片或 map 的长度
+func readLen(b buffer.Reader) (int, error) {
+	l, err := b.ReadVarint32()
+	if err != nil {
+		return 0, err
+	}
+	if l < 0 {
+		return 0, buffer.ErrExceedBufferLimit
+	}
+	return int(l), nil
+}
+
+func encodeBytes(b buffer.Writer, v reflect.Value) error {
+	p := v.Bytes()
+	if err := writeLen(b, len(p)); err != nil {
+		return err
+	}
+	// FixedBuffer 空间不足时仅写入部分数据且不返回错误
+	if n, err := b.Write(p); err != nil {
+		return err
+	} else if n != len(p) {
+		return buffer.ErrExceedBufferLimit
+	}
+	return nil
+}
+
+// bytesReader 支持 ReadBytes 的 Reader, 由其负责检查解码限制
+type bytesReader interface {
+	ReadBytes() ([]byte, error)
+}
+
+func decodeBytes(b buffer.Reader, v reflect.Value) error {
+	if br, ok := b.(bytesReader); ok {
+		p, err := br.ReadBytes()
+		if err != nil {
+			return err
+		}
+		if len(p) == 0 {
+			v.SetZero()
+		} else {
+			v.SetBytes(p)
+		}
+		return nil
+	}
+
+	l, err := readLen(b)
+	if err != nil {
+		return err
+	}
+	if rr, ok := b.(readableReader); ok && l > rr.Readable() {
+		return io.ErrUnexpectedEOF
+	}
+	if l == 0 {
+		v.SetZero()
+		return nil
+	}
+	p := reflect.MakeSlice(v.Type(), l, l)
+	if _, err := io.ReadFull(b, p.Bytes()); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	v.Set(p)
+	return nil
+}
+
+func compileSlice(c *typeCodec, t reflect.Type, e encoding, compiling map[codecKey]*typeCodec) error {
+	if t.Elem().Kind() == reflect.Uint8 {
+		c.enc, c.dec = encodeBytes, decodeBytes
+		return nil
+	}
+
+	ec, err := compileCodec(t.Elem(), e, compiling)
+	if err != nil {
+		return err
+	}
+
+	c.enc = func(b buffer.Writer, v reflect.Value) error {
+		n := v.Len()
+		if err := writeLen(b, n); err != nil {
+			return err
+		}
+		for i := 0; i < n; i++ {
+			if err := ec.enc(b, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	c.dec = func(b buffer.Reader, v reflect.Value) error {
+		n, err := readLen(b)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			v.SetZero()
+			return nil
+		}
+		// 依据可读数据长度预分配, 避免恶意长度导致的过量分配
+		m := allocHint(b, n)
+		s := reflect.MakeSlice(t, m, m)
+		for i := 0; i < n; i++ {
+			if i >= s.Len() {
+				s = reflect.Append(s, reflect.Zero(t.Elem()))
+			}
+			if err := ec.dec(b, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	}
+	return nil
+}
+
+func compileArray(c *typeCodec, t reflect.Type, e encoding, compiling map[codecKey]*typeCodec) error {
+	ec, err := compileCodec(t.Elem(), e, compiling)
+	if err != nil {
+		return err
+	}
+
+	n := t.Len()
+	c.enc = func(b buffer.Writer, v reflect.Value) error {
+		for i := 0; i < n; i++ {
+			if err := ec.enc(b, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	c.dec = func(b buffer.Reader, v reflect.Value) error {
+		for i := 0; i < n; i++ {
+			if err := ec.dec(b, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// mapKeyCompare 返回 map 键的比较函数, 仅支持有序的基础类型
+func mapKeyCompare(t reflect.Type) (func(a, b reflect.Value) int, bool) {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b reflect.Value) int { return compareOrdered(a.Int(), b.Int()) }, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(a, b reflect.Value) int { return compareOrdered(a.Uint(), b.Uint()) }, true
+	case reflect.Float32, reflect.Float64:
+		return func(a, b reflect.Value) int { return compareOrdered(a.Float(), b.Float()) }, true
+	case reflect.String:
+		return func(a, b reflect.Value) int { return strings.Compare(a.String(), b.String()) }, true
+	default:
+		return nil, false
+	}
+}
+
+func compareOrdered[T int64 | uint64 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func compileMap(c *typeCodec, t reflect.Type, e encoding, compiling map[codecKey]*typeCodec) error {
+	cmp, ok := mapKeyCompare(t.Key())
+	if !ok {
+		return pkg_errors.WithMessagef(ErrUnsupportedType, "map key %s", t.Key())
+	}
+	kc, err := compileCodec(t.Key(), e, compiling)
+	if err != nil {
+		return err
+	}
+	vc, err := compileCodec(t.Elem(), e, compiling)
+	if err != nil {
+		return err
+	}
+
+	c.enc = func(b buffer.Writer, v reflect.Value) error {
+		if err := writeLen(b, v.Len()); err != nil {
+			return err
+		}
+		keys := v.MapKeys()
+		slices.SortFunc(keys, cmp)
+		for _, k := range keys {
+			if err := kc.enc(b, k); err != nil {
+				return err
+			}
+			if err := vc.enc(b, v.MapIndex(k)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	c.dec = func(b buffer.Reader, v reflect.Value) error {
+		n, err := readLen(b)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			v.SetZero()
+			return nil
+		}
+		m := reflect.MakeMapWithSize(t, allocHint(b, n))
+		for i := 0; i < n; i++ {
+			k := reflect.New(t.Key()).Elem()
+			if err := kc.dec(b, k); err != nil {
+				return err
+			}
+			e := reflect.New(t.Elem()).Elem()
+			if err := vc.dec(b, e); err != nil {
+				return err
+			}
+			m.SetMapIndex(k, e)
+		}
+		v.Set(m)
+		return nil
+	}
+	return nil
+}
+
+func compilePointer(c *typeCodec, t reflect.Type, e encoding, compiling map[codecKey]*typeCodec) error {
+	ec, err := compileCodec(t.Elem(), e, compiling)
+	if err != nil {
+		return err
+	}
+
+	c.enc = func(b buffer.Writer, v reflect.Value) error {
+		if v.IsNil() {
+			return b.WriteBool(false)
+		}
+		if err := b.WriteBool(true); err != nil {
+			return err
+		}
+		return ec.enc(b, v.Elem())
+	}
+	c.dec = func(b buffer.Reader, v reflect.Value) error {
+		ok, err := b.ReadBool()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			v.SetZero()
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(t.Elem()))
+		}
+		return ec.dec(b, v.Elem())
+	}
+	return nil
+}
+
+// fieldCodec 结构体字段编解码器
+type fieldCodec struct {
+	index int
+	codec *typeCodec
+}
+
+func compileStruct(c *typeCodec, t reflect.Type, compiling map[codecKey]*typeCodec) error {
+	fields := make([]fieldCodec, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		e, skip, err := parseEncoding(f.Tag.Get(TagName))
+		if err != nil {
+			return pkg_errors.WithMessagef(err, "field %s.%s", t, f.Name)
+		}
+		if skip {
+			continue
+		}
+
+		fc, err := compileCodec(f.Type, e, compiling)
+		if err != nil {
+			return pkg_errors.WithMessagef(err, "field %s.%s", t, f.Name)
+		}
+		fields = append(fields, fieldCodec{index: i, codec: fc})
+	}
+
+	c.enc = func(b buffer.Writer, v reflect.Value) error {
+		for _, f := range fields {
+			if err := f.codec.enc(b, v.Field(f.index)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	c.dec = func(b buffer.Reader, v reflect.Value) error {
+		for _, f := range fields {
+			if err := f.codec.dec(b, v.Field(f.index)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return nil
+}
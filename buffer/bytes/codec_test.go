@@ -0,0 +1,156 @@
+package bytes
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+type codecInner struct {
+	ID   int32 `buf:"varint"`
+	Name string
+}
+
+type codecMessage struct {
+	B       bool
+	I8      int8
+	U16     uint16 `buf:"big"`
+	I32     int32  `buf:"lit"`
+	U32     uint32 `buf:"varint"`
+	I64     int64
+	F32     float32 `buf:"big"`
+	F64     float64
+	S       string
+	Data    []byte
+	Ints    []int16 `buf:"varint"`
+	Arr     [2]uint8
+	M       map[string]int32 `buf:"varint"`
+	Inner   codecInner
+	Ptr     *codecInner
+	NilPtr  *codecInner
+	Ignored int `buf:"-"`
+	hidden  int
+}
+
+func TestMarshal_Legacy(t *testing.T) {
+	msg := codecMessage{
+		B:       true,
+		I8:      -3,
+		U16:     0x1234,
+		I32:     -5,
+		U32:     300,
+		I64:     1 << 40,
+		F32:     1.5,
+		F64:     -2.25,
+		S:       "hello",
+		Data:    []byte{1, 2, 3},
+		Ints:    []int16{-1, 1000},
+		Arr:     [2]uint8{7, 8},
+		M:       map[string]int32{"b": 2, "a": 1},
+		Inner:   codecInner{ID: 9, Name: "inner"},
+		Ptr:     &codecInner{ID: -9, Name: "ptr"},
+		Ignored: 100,
+		hidden:  200,
+	}
+
+	data, err := Marshal(&msg)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	b := NewBuffer(nil)
+	_ = b.WriteBool(msg.B)
+	_ = b.WriteInt8(msg.I8)
+	_ = b.WriteBigUint16(msg.U16)
+	_ = b.WriteLitInt32(msg.I32)
+	_, _ = b.WriteUvarint32(msg.U32)
+	_ = b.WriteInt64(msg.I64)
+	_ = b.WriteBigFloat32(msg.F32)
+	_ = b.WriteFloat64(msg.F64)
+	_ = b.WriteString(msg.S)
+	_ = b.WriteString(string(msg.Data))
+	_, _ = b.WriteVarint32(int32(len(msg.Ints)))
+	for _, i := range msg.Ints {
+		_, _ = b.WriteVarint16(i)
+	}
+	_ = b.WriteUint8(msg.Arr[0])
+	_ = b.WriteUint8(msg.Arr[1])
+	_, _ = b.WriteVarint32(int32(len(msg.M)))
+	_ = b.WriteString("a")
+	_, _ = b.WriteVarint32(1)
+	_ = b.WriteString("b")
+	_, _ = b.WriteVarint32(2)
+	_, _ = b.WriteVarint32(msg.Inner.ID)
+	_ = b.WriteString(msg.Inner.Name)
+	_ = b.WriteBool(true)
+	_, _ = b.WriteVarint32(msg.Ptr.ID)
+	_ = b.WriteString(msg.Ptr.Name)
+	_ = b.WriteBool(false)
+
+	if !bytes.Equal(data, b.Data()) {
+		t.Fatalf("marshal data mismatch\n got: %v\nwant: %v", data, b.Data())
+	}
+
+	var got codecMessage
+	if err := Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	msg.Ignored, msg.hidden = 0, 0
+	if !reflect.DeepEqual(got, msg) {
+		t.Fatalf("unmarshal mismatch\n got: %+v\nwant: %+v", got, msg)
+	}
+}
+
+type codecNode struct {
+	Value int32 `buf:"varint"`
+	Next  *codecNode
+}
+
+func TestMarshal_Recursive(t *testing.T) {
+	list := &codecNode{Value: 1, Next: &codecNode{Value: 2, Next: &codecNode{Value: 3}}}
+
+	b := NewBuffer(nil)
+	if err := b.WriteValue(list); err != nil {
+		t.Fatalf("write value: %v", err)
+	}
+
+	var got codecNode
+	if err := b.ReadValue(&got); err != nil {
+		t.Fatalf("read value: %v", err)
+	}
+	if !reflect.DeepEqual(&got, list) {
+		t.Fatalf("read value mismatch: %+v", got)
+	}
+}
+
+func TestMarshal_Errors(t *testing.T) {
+	if _, err := Marshal(struct{ C chan int }{}); !errors.Is(err, ErrUnsupportedType) {
+		t.Fatalf("marshal chan: expected ErrUnsupportedType, got %v", err)
+	}
+	if _, err := Marshal(struct {
+		F float32 `buf:"varint"`
+	}{}); !errors.Is(err, ErrInvalidTag) {
+		t.Fatalf("marshal varint float: expected ErrInvalidTag, got %v", err)
+	}
+	if err := Unmarshal(nil, codecInner{}); err != ErrInvalidUnmarshal {
+		t.Fatalf("unmarshal non-pointer: expected ErrInvalidUnmarshal, got %v", err)
+	}
+
+	data, _ := Marshal(codecInner{ID: 1, Name: "name"})
+	var got codecInner
+	if err := Unmarshal(nil, &got); err != io.EOF {
+		t.Fatalf("unmarshal empty: expected io.EOF, got %v", err)
+	}
+	if err := Unmarshal(data[:1], &got); err != io.ErrUnexpectedEOF {
+		t.Fatalf("unmarshal truncated: expected io.ErrUnexpectedEOF, got %v", err)
+	}
+
+	// 长度大于剩余数据的切片
+	type slice struct{ S []int32 }
+	data, _ = Marshal(slice{S: make([]int32, 10)})
+	if err := Unmarshal(data[:len(data)-36], &slice{}); err != io.ErrUnexpectedEOF {
+		t.Fatalf("unmarshal truncated slice: expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package bytes
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrQuantizeOverflow 量化后的值超出 int64 范围, 或值为 NaN
+var ErrQuantizeOverflow = errors.New("bytes: quantized value overflow")
+
+// ErrInvalidScale 量化的缩放系数不是有限的正数
+var ErrInvalidScale = errors.New("bytes: invalid quantize scale")
+
+// checkScale 检查 scale 是否为有限的正数
+func checkScale(scale float64) error {
+	if !(scale > 0) || math.IsInf(scale, 1) {
+		return ErrInvalidScale
+	}
+	return nil
+}
+
+// quantize 以 scale 为缩放系数将 f 量化为整数, 四舍五入至最近的整数
+func quantize(f, scale float64) (int64, error) {
+	if err := checkScale(scale); err != nil {
+		return 0, err
+	}
+	q := math.Round(f * scale)
+	// float64(math.MaxInt64) 会被舍入为 2^63, 因此使用 >= 判断
+	if math.IsNaN(q) || q >= math.MaxInt64 || q < math.MinInt64 {
+		return 0, ErrQuantizeOverflow
+	}
+	return int64(q), nil
+}
+
+// dequantize 将量化的整数以 scale 为缩放系数还原为浮点数
+func dequantize(q int64, scale float64) float64 {
+	return float64(q) / scale
+}
+
+// Float32ToFloat16 将 float32 转换为 IEEE 754 半精度浮点数
+// 采用就近舍入(ties to even), 超出范围时转换为无穷大, NaN 保持为 NaN.
+func Float32ToFloat16(f float32) uint16 {
+	bits := math.Float32bits(f)
+	sign := uint16(bits>>16) & 0x8000
+	exp := int(bits>>23) & 0xff
+	mant := bits & 0x7fffff
+
+	if exp == 0xff {
+		if mant != 0 {
+			return sign | 0x7e00
+		}
+		return sign | 0x7c00
+	}
+
+	e := exp - 127 + 15
+	if e >= 0x1f {
+		return sign | 0x7c00
+	}
+
+	if e <= 0 {
+		// 非规格化数, 过小时舍入为 0
+		if e < -10 {
+			return sign
+		}
+		mant |= 0x800000
+		shift := uint(14 - e)
+		h := mant >> shift
+		rem := mant & (1<<shift - 1)
+		halfway := uint32(1) << (shift - 1)
+		if rem > halfway || (rem == halfway && h&1 == 1) {
+			h++
+		}
+		return sign | uint16(h)
+	}
+
+	// 舍入产生的进位会进入指数位, 恰好得到正确的结果(包括溢出为无穷大)
+	h := uint32(e)<<10 | mant>>13
+	rem := mant & 0x1fff
+	if rem > 0x1000 || (rem == 0x1000 && h&1 == 1) {
+		h++
+	}
+	return sign | uint16(h)
+}
+
+// Float16ToFloat32 将 IEEE 754 半精度浮点数转换为 float32, 转换是精确的
+func Float16ToFloat32(h uint16) float32 {
+	sign := uint32(h&0x8000) << 16
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+
+	switch exp {
+	case 0x1f:
+		return math.Float32frombits(sign | 0x7f800000 | mant<<13)
+	case 0:
+		if mant == 0 {
+			return math.Float32frombits(sign)
+		}
+		// 非规格化数, 规格化后转换
+		e := uint32(127 - 15 + 1)
+		for mant&0x400 == 0 {
+			mant <<= 1
+			e--
+		}
+		return math.Float32frombits(sign | e<<23 | (mant&0x3ff)<<13)
+	default:
+		return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+	}
+}
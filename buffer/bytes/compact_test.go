@@ -0,0 +1,187 @@
+package bytes
+
+import (
+	"io"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestFloat16_RoundTrip(t *testing.T) {
+	for i := 0; i < 1<<16; i++ {
+		h := uint16(i)
+		f := Float16ToFloat32(h)
+		if math.IsNaN(float64(f)) {
+			if got := Float32ToFloat16(f); got&0x7c00 != 0x7c00 || got&0x3ff == 0 {
+				t.Fatalf("NaN %#04x converted to %#04x", h, got)
+			}
+			continue
+		}
+		if got := Float32ToFloat16(f); got != h {
+			t.Fatalf("%#04x -> %v -> %#04x", h, f, got)
+		}
+	}
+}
+
+func TestFloat16_Rounding(t *testing.T) {
+	tests := []struct {
+		f    float32
+		want uint16
+	}{
+		{0, 0x0000},
+		{float32(math.Copysign(0, -1)), 0x8000},
+		{1, 0x3c00},
+		{-2, 0xc000},
+		{65504, 0x7bff},
+		{65520, 0x7c00},                          // 溢出为无穷大
+		{float32(math.Inf(-1)), 0xfc00},          // -Inf
+		{1 + 1.0/2048, 0x3c00},                   // 恰好位于中间, 舍入至偶数
+		{1 + 3.0/2048, 0x3c02},                   // 恰好位于中间, 舍入至偶数
+		{float32(math.Ldexp(1, -24)), 0x0001},    // 最小非规格化数
+		{float32(math.Ldexp(1, -25)), 0x0000},    // 恰好位于中间, 舍入至偶数
+		{float32(math.Ldexp(1.5, -25)), 0x0001},  // 舍入至最小非规格化数
+		{float32(math.Ldexp(1023, -24)), 0x03ff}, // 最大非规格化数
+	}
+	for _, tt := range tests {
+		if got := Float32ToFloat16(tt.f); got != tt.want {
+			t.Errorf("Float32ToFloat16(%v) = %#04x, want %#04x", tt.f, got, tt.want)
+		}
+	}
+}
+
+func TestBuffer_Compact(t *testing.T) {
+	b := NewBuffer(nil)
+	fb := NewFixedBuffer(64)
+	for _, w := range []interface {
+		WriteFloat16(float32) error
+		WriteBigFloat16(float32) error
+		WriteLitFloat16(float32) error
+		WriteQuantized32(float32, float32) (int, error)
+		WriteQuantized64(float64, float64) (int, error)
+	}{b, fb} {
+		_ = w.WriteFloat16(1.5)
+		_ = w.WriteBigFloat16(-0.25)
+		_ = w.WriteLitFloat16(100)
+		if n, err := w.WriteQuantized32(12.345, 100); err != nil || n != 2 {
+			t.Fatalf("WriteQuantized32: %d, %v", n, err)
+		}
+		_, _ = w.WriteQuantized64(-0.5, 1000)
+		if _, err := w.WriteQuantized64(math.NaN(), 1); err != ErrQuantizeOverflow {
+			t.Fatalf("WriteQuantized64 NaN: expected ErrQuantizeOverflow, got %v", err)
+		}
+		if _, err := w.WriteQuantized64(math.MaxFloat64, 1); err != ErrQuantizeOverflow {
+			t.Fatalf("WriteQuantized64 overflow: expected ErrQuantizeOverflow, got %v", err)
+		}
+		for _, scale := range []float64{0, -1, math.Inf(1), math.NaN()} {
+			if _, err := w.WriteQuantized64(1, scale); err != ErrInvalidScale {
+				t.Fatalf("WriteQuantized64 scale %v: expected ErrInvalidScale, got %v", scale, err)
+			}
+			if _, err := w.WriteQuantized32(1, float32(scale)); err != ErrInvalidScale {
+				t.Fatalf("WriteQuantized32 scale %v: expected ErrInvalidScale, got %v", scale, err)
+			}
+		}
+	}
+
+	for _, r := range []interface {
+		ReadFloat16() (float32, error)
+		ReadBigFloat16() (float32, error)
+		ReadLitFloat16() (float32, error)
+		ReadQuantized32(float32) (float32, error)
+		ReadQuantized64(float64) (float64, error)
+	}{b, fb} {
+		if f, err := r.ReadFloat16(); err != nil || f != 1.5 {
+			t.Fatalf("ReadFloat16: %v, %v", f, err)
+		}
+		if f, err := r.ReadBigFloat16(); err != nil || f != -0.25 {
+			t.Fatalf("ReadBigFloat16: %v, %v", f, err)
+		}
+		if f, err := r.ReadLitFloat16(); err != nil || f != 100 {
+			t.Fatalf("ReadLitFloat16: %v, %v", f, err)
+		}
+		// scale 非法时不消耗数据
+		if _, err := r.ReadQuantized32(0); err != ErrInvalidScale {
+			t.Fatalf("ReadQuantized32 zero scale: expected ErrInvalidScale, got %v", err)
+		}
+		if _, err := r.ReadQuantized64(math.Inf(1)); err != ErrInvalidScale {
+			t.Fatalf("ReadQuantized64 inf scale: expected ErrInvalidScale, got %v", err)
+		}
+		if f, err := r.ReadQuantized32(100); err != nil || f != 12.35 {
+			t.Fatalf("ReadQuantized32: %v, %v", f, err)
+		}
+		if f, err := r.ReadQuantized64(1000); err != nil || f != -0.5 {
+			t.Fatalf("ReadQuantized64: %v, %v", f, err)
+		}
+		if _, err := r.ReadFloat16(); err != io.EOF {
+			t.Fatalf("ReadFloat16 at end: expected io.EOF, got %v", err)
+		}
+	}
+}
+
+func TestBitWriter(t *testing.T) {
+	type field struct {
+		v uint64
+		n int
+	}
+	r := rand.New(rand.NewSource(1))
+	fields := make([]field, 1000)
+	for i := range fields {
+		n := r.Intn(65)
+		v := r.Uint64()
+		if n < 64 {
+			v &= 1<<n - 1
+		}
+		fields[i] = field{v: v, n: n}
+	}
+
+	b := NewBuffer(nil)
+	bw := NewBitWriter(b)
+	bits := 0
+	for _, f := range fields {
+		if err := bw.WriteBits(f.v, f.n); err != nil {
+			t.Fatal(err)
+		}
+		bits += f.n
+	}
+	if err := bw.WriteBool(true); err != nil {
+		t.Fatal(err)
+	}
+	bits++
+	if err := bw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if want := (bits + 7) / 8; b.Readable() != want {
+		t.Fatalf("written %d bytes, want %d", b.Readable(), want)
+	}
+
+	br := NewBitReader(b)
+	for i, f := range fields {
+		v, err := br.ReadBits(f.n)
+		if err != nil || v != f.v {
+			t.Fatalf("field %d: got %#x, %v, want %#x", i, v, err, f.v)
+		}
+	}
+	if v, err := br.ReadBool(); err != nil || !v {
+		t.Fatalf("ReadBool: %v, %v", v, err)
+	}
+	br.Align()
+	if _, err := br.ReadBits(1); err != io.EOF {
+		t.Fatalf("ReadBits at end: expected io.EOF, got %v", err)
+	}
+}
+
+func TestBitWriter_Packing(t *testing.T) {
+	b := NewFixedBuffer(2)
+	bw := NewBitWriter(b)
+	_ = bw.WriteBool(true)
+	_ = bw.WriteBits(0x5, 3)
+	_ = bw.WriteBits(0x3, 2)
+	_ = bw.Flush()
+	if got := b.UnreadData(); len(got) != 1 || got[0] != 0x3b {
+		t.Fatalf("packed data: %#v", got)
+	}
+
+	br := NewBitReader(NewBuffer([]byte{0xff}))
+	if _, err := br.ReadBits(9); err != io.ErrUnexpectedEOF {
+		t.Fatalf("ReadBits truncated: expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
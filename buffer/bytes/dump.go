@@ -0,0 +1,113 @@
+package bytes
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// dumpBytesPerLine hexdump 每行的字节数
+const dumpBytesPerLine = 16
+
+// dump 以 hexdump 格式输出 data, base 为 data[0] 的逻辑位置
+// 在数据行下方标注读取位置 r('r') 及读取位置标记 marks('m'), 均为逻辑位置.
+func dump(sb *strings.Builder, data []byte, base, r int, marks []int) {
+	var ascii [dumpBytesPerLine]byte
+	for line := 0; line < len(data); line += dumpBytesPerLine {
+		p := data[line:min(line+dumpBytesPerLine, len(data))]
+		fmt.Fprintf(sb, "%08x  ", base+line)
+		for i := 0; i < dumpBytesPerLine; i++ {
+			if i == dumpBytesPerLine/2 {
+				sb.WriteByte(' ')
+			}
+			if i < len(p) {
+				fmt.Fprintf(sb, "%02x ", p[i])
+				ascii[i] = p[i]
+				if p[i] < 0x20 || p[i] > 0x7e {
+					ascii[i] = '.'
+				}
+			} else {
+				sb.WriteString("   ")
+			}
+		}
+		fmt.Fprintf(sb, " |%s|\n", ascii[:len(p)])
+
+		// 标注行, 标注位于对应字节的第一个十六进制字符下方
+		notes := []byte(strings.Repeat(" ", 10+3*dumpBytesPerLine+1))
+		noted := false
+		note := func(pos int, c byte) {
+			i := pos - base - line
+			if i < 0 || i >= len(p) {
+				return
+			}
+			col := 10 + 3*i
+			if i >= dumpBytesPerLine/2 {
+				col++
+			}
+			notes[col] = c
+			noted = true
+		}
+		for _, m := range marks {
+			note(m, 'm')
+		}
+		note(r, 'r')
+		if noted {
+			sb.WriteString(strings.TrimRight(string(notes), " "))
+			sb.WriteByte('\n')
+		}
+	}
+}
+
+// dumpHeader 输出 Dump 的首行摘要
+func dumpHeader(sb *strings.Builder, name string, read, unread, capacity int, marks []int) {
+	fmt.Fprintf(sb, "%s: read %d, unread %d, cap %d", name, read, unread, capacity)
+	if len(marks) > 0 {
+		fmt.Fprintf(sb, ", marks %v", marks)
+	}
+	sb.WriteByte('\n')
+}
+
+// Dump 以带标注的 hexdump 格式输出buf中的全部数据
+// 首行为读写状态摘要, 偏移量为自创建或上次 Reset/SetBuf 起的逻辑位置. 数据行下方
+// 'r' 标注读取位置, 'm' 标注读取位置标记.
+func (b *Buffer) Dump() string {
+	var sb strings.Builder
+	dumpHeader(&sb, "bytes.Buffer", b.shifted+b.off, b.Readable(), cap(b.buf), b.marks)
+	dump(&sb, b.buf, b.shifted, b.shifted+b.off, b.marks)
+	return sb.String()
+}
+
+// Format 实现 fmt.Formatter
+// %x、%X 输出未读数据的十六进制, %+v 输出 Dump, %v、%s 输出读写状态摘要.
+func (b *Buffer) Format(f fmt.State, verb rune) {
+	format(f, verb, "bytes.Buffer", b.UnreadData(), b.shifted+b.off, cap(b.buf), b.Dump)
+}
+
+// Dump 以带标注的 hexdump 格式输出buf中已写入的数据, 参见 Buffer.Dump
+func (b *FixedBuffer) Dump() string {
+	var sb strings.Builder
+	dumpHeader(&sb, "bytes.FixedBuffer", b.shifted+b.r, b.Readable(), len(b.buf), b.marks)
+	dump(&sb, b.buf[:b.w], b.shifted, b.shifted+b.r, b.marks)
+	return sb.String()
+}
+
+// Format 实现 fmt.Formatter, 参见 Buffer.Format
+func (b *FixedBuffer) Format(f fmt.State, verb rune) {
+	format(f, verb, "bytes.FixedBuffer", b.UnreadData(), b.shifted+b.r, len(b.buf), b.Dump)
+}
+
+// format 实现 Buffer 及 FixedBuffer 的 Format
+func format(f fmt.State, verb rune, name string, unread []byte, read, capacity int, dumpFunc func() string) {
+	switch verb {
+	case 'x', 'X':
+		fmt.Fprintf(f, fmt.FormatString(f, verb), unread)
+	case 'v', 's':
+		if verb == 'v' && f.Flag('+') {
+			_, _ = io.WriteString(f, dumpFunc())
+			return
+		}
+		fmt.Fprintf(f, "%s{read: %d, unread: %d, cap: %d}", name, read, len(unread), capacity)
+	default:
+		fmt.Fprintf(f, "%%!%c(%s)", verb, name)
+	}
+}
@@ -0,0 +1,133 @@
+package bytes
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestBuffer_Dump(t *testing.T) {
+	b := NewBuffer(nil)
+	_ = b.WriteString("hello, world")
+	_ = b.WriteBigUint32(0xdeadbeef)
+	_, _ = b.Write(make([]byte, 10))
+	_, _ = b.ReadString()
+	b.Mark()
+	_, _ = b.ReadByte()
+
+	want := `bytes.Buffer: read 14, unread 13, cap 64, marks [13]
+00000000  18 68 65 6c 6c 6f 2c 20  77 6f 72 6c 64 de ad be  |.hello, world...|
+                                                  m  r
+00000010  ef 00 00 00 00 00 00 00  00 00 00                 |...........|
+`
+	if got := b.Dump(); got != want {
+		t.Fatalf("Dump:\n%s\nwant:\n%s", got, want)
+	}
+	if got := fmt.Sprintf("%+v", b); got != want {
+		t.Fatalf("%%+v:\n%s", got)
+	}
+	if got := fmt.Sprintf("%v", b); got != "bytes.Buffer{read: 14, unread: 13, cap: 64}" {
+		t.Fatalf("%%v: %s", got)
+	}
+	if got := fmt.Sprintf("% x", b); got != "ad be ef 00 00 00 00 00 00 00 00 00 00" {
+		t.Fatalf("%% x: %s", got)
+	}
+
+	fb := NewFixedBuffer(32)
+	_, _ = fb.Write([]byte("0123456789abcdefXY"))
+	_, _ = fb.Skip(17)
+	want = `bytes.FixedBuffer: read 17, unread 1, cap 32
+00000000  30 31 32 33 34 35 36 37  38 39 61 62 63 64 65 66  |0123456789abcdef|
+00000010  58 59                                             |XY|
+             r
+`
+	if got := fb.Dump(); got != want {
+		t.Fatalf("FixedBuffer.Dump:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTracer(t *testing.T) {
+	type msg struct {
+		ID   int32
+		Name string
+	}
+
+	tr := NewTracer(NewBuffer(nil))
+	if err := tr.WriteValue(msg{ID: 7, Name: "ab"}); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = tr.WriteUvarint16(300)
+
+	// 以错误的类型解码, 跟踪记录显示出错的字段
+	var got struct {
+		ID   int64
+		Name string
+	}
+	err := tr.ReadValue(&got)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("ReadValue: expected io.ErrUnexpectedEOF, got %v", err)
+	}
+
+	records := tr.Records()
+	if len(records) != 5 {
+		t.Fatalf("expected 5 records, got %d:\n%s", len(records), tr.Trace())
+	}
+	want := []TraceRecord{
+		{Op: TraceWrite, Method: "WriteInt32", Offset: 0, Len: 4, Value: int32(7)},
+		{Op: TraceWrite, Method: "WriteString", Offset: 4, Len: 3, Value: "ab"},
+		{Op: TraceWrite, Method: "WriteUvarint16", Offset: 7, Len: 2, Value: uint16(300)},
+	}
+	for i, w := range want {
+		r := records[i]
+		if r.Op != w.Op || r.Method != w.Method || r.Offset != w.Offset || r.Len != w.Len || r.Value != w.Value || r.Err != nil {
+			t.Fatalf("record %d: %v, want %v", i, r, w)
+		}
+	}
+	if r := records[3]; r.Method != "ReadInt64" || r.Len != 8 {
+		t.Fatalf("record 3: %v", r)
+	}
+	if r := records[4]; r.Method != "ReadString" || r.Offset != 8 || r.Err == nil {
+		t.Fatalf("record 4: %v", r)
+	}
+
+	trace := tr.Trace()
+	if !strings.Contains(trace, `write 00000004 +3   WriteString        "ab" [04 61 62]`) {
+		t.Fatalf("Trace:\n%s", trace)
+	}
+
+	tr.ClearRecords()
+	if len(tr.Records()) != 0 {
+		t.Fatalf("ClearRecords")
+	}
+}
+
+// 跟踪记录不得引用 buf 的内存, 否则后续写入会改写已记录的值
+func TestTracer_NoCopy(t *testing.T) {
+	tr := NewTracer(NewBuffer(nil))
+	_ = tr.WriteString("hello")
+	_ = tr.WriteBytes([]byte{1, 2, 3})
+
+	s, err := tr.ReadStringNoCopy()
+	if err != nil || s != "hello" {
+		t.Fatalf("ReadStringNoCopy: %q, %v", s, err)
+	}
+	p, err := tr.ReadBytesNoCopy()
+	if err != nil || !bytes.Equal(p, []byte{1, 2, 3}) {
+		t.Fatalf("ReadBytesNoCopy: %v, %v", p, err)
+	}
+
+	// 读取完毕后写入会复用 buf 的空间
+	tr.Reset()
+	_, _ = tr.Write(bytes.Repeat([]byte{0xdd}, 16))
+
+	records := tr.Records()
+	if v := records[2].Value; v != "hello" {
+		t.Fatalf("ReadStringNoCopy record: %q", v)
+	}
+	if v := records[3].Value.([]byte); !bytes.Equal(v, []byte{1, 2, 3}) {
+		t.Fatalf("ReadBytesNoCopy record: %v", v)
+	}
+}
@@ -5,16 +5,23 @@ import (
 	"github.com/godyy/gutils/buffer"
 	"github.com/pkg/errors"
 	"io"
+	"unsafe"
 )
 
 // FixedBuffer 定长字节缓冲区
 type FixedBuffer struct {
-	buf  []byte
-	r, w int
+	buf     []byte
+	r, w    int
+	marks   []int       // 读取位置标记栈, 记录逻辑读取位置
+	shifted int         // 自buf前端丢弃的字节数, 逻辑读取位置 = shifted + r
+	lent    lentTracker // 借出的数据视图
+	lim     *limits     // 解码限制
 }
 
-// NewFixedBuffer 使用指定size创建FixedBuffer
-func NewFixedBuffer(size int) *FixedBuffer {
+var _ buffer.ReadWriter = (*FixedBuffer)(nil)
+
+// NewFixedBuffer 使用指定size创建FixedBuffer, opts 用于配置解码限制
+func NewFixedBuffer(size int, opts ...Option) *FixedBuffer {
 	if size <= 0 {
 		panic("bytes.NewFixedBuffer: size <= 0")
 	}
@@ -22,6 +29,7 @@ func NewFixedBuffer(size int) *FixedBuffer {
 		buf: make([]byte, size),
 		r:   0,
 		w:   0,
+		lim: buildLimits(opts),
 	}
 }
 
@@ -32,16 +40,24 @@ func (b *FixedBuffer) Size() int {
 
 // Reset 重置读写状态
 func (b *FixedBuffer) Reset() {
+	b.lent.release(b.buf, 0, 0)
 	b.r = 0
 	b.w = 0
+	b.marks = b.marks[:0]
+	b.shifted = 0
+	b.lim.reset()
 }
 
 // SetBuf 设置buf并返回之前的buf
 func (b *FixedBuffer) SetBuf(buf []byte) []byte {
+	b.lent.release(b.buf, 0, 0)
 	old := b.buf
 	b.buf = buf
 	b.r = 0
 	b.w = 0
+	b.marks = b.marks[:0]
+	b.shifted = 0
+	b.lim.reset()
 	return old
 }
 
@@ -52,7 +68,7 @@ func (b *FixedBuffer) Readable() int {
 
 // Writable 获取可写入数据长度
 func (b *FixedBuffer) Writable() int {
-	return len(b.buf) - b.w + b.r
+	return len(b.buf) - b.w + b.retained()
 }
 
 // Data 获取buf中的完整写入数据
@@ -71,12 +87,24 @@ func (b *FixedBuffer) UnreadData() []byte {
 	return b.buf[b.r:b.w]
 }
 
+// retained 获取buf中须保留的数据的起始位置, 存在读取位置标记时为最早的标记位置
+func (b *FixedBuffer) retained() int {
+	if len(b.marks) > 0 {
+		return b.marks[0] - b.shifted
+	}
+	return b.r
+}
+
 // slideReadable 将可读数据滑动到buf最前端
+// 存在读取位置标记时, 保留最早的标记之后的数据.
 func (b *FixedBuffer) slideReadable() {
-	if b.r > 0 {
-		copy(b.buf, b.buf[b.r:b.w])
-		b.w -= b.r
-		b.r = 0
+	keep := b.retained()
+	b.lent.release(b.buf, keep, b.w)
+	if keep > 0 {
+		copy(b.buf, b.buf[keep:b.w])
+		b.w -= keep
+		b.r -= keep
+		b.shifted += keep
 	}
 }
 
@@ -201,7 +229,7 @@ func (b *FixedBuffer) WriteBool(v bool) error {
 }
 
 func (b *FixedBuffer) ReadVarint16() (int16, error) {
-	i, n := binary.Varint(b.buf[b.r:])
+	i, n := binary.Varint(b.buf[b.r:b.w])
 	if n == 0 {
 		return 0, io.EOF
 	}
@@ -226,7 +254,7 @@ func (b *FixedBuffer) WriteVarint16(i int16) (int, error) {
 }
 
 func (b *FixedBuffer) ReadUvarint16() (uint16, error) {
-	i, n := binary.Uvarint(b.buf[b.r:])
+	i, n := binary.Uvarint(b.buf[b.r:b.w])
 	if n == 0 {
 		return 0, io.EOF
 	}
@@ -251,7 +279,7 @@ func (b *FixedBuffer) WriteUvarint16(i uint16) (int, error) {
 }
 
 func (b *FixedBuffer) ReadVarint32() (int32, error) {
-	i, n := binary.Varint(b.buf[b.r:])
+	i, n := binary.Varint(b.buf[b.r:b.w])
 	if n == 0 {
 		return 0, io.EOF
 	}
@@ -276,7 +304,7 @@ func (b *FixedBuffer) WriteVarint32(i int32) (int, error) {
 }
 
 func (b *FixedBuffer) ReadUvarint32() (uint32, error) {
-	i, n := binary.Uvarint(b.buf[b.r:])
+	i, n := binary.Uvarint(b.buf[b.r:b.w])
 	if n == 0 {
 		return 0, io.EOF
 	}
@@ -301,7 +329,7 @@ func (b *FixedBuffer) WriteUvarint32(i uint32) (int, error) {
 }
 
 func (b *FixedBuffer) ReadVarint64() (int64, error) {
-	i, n := binary.Varint(b.buf[b.r:])
+	i, n := binary.Varint(b.buf[b.r:b.w])
 	if n == 0 {
 		return 0, io.EOF
 	}
@@ -326,7 +354,7 @@ func (b *FixedBuffer) WriteVarint64(i int64) (int, error) {
 }
 
 func (b *FixedBuffer) ReadUvarint64() (uint64, error) {
-	i, n := binary.Uvarint(b.buf[b.r:])
+	i, n := binary.Uvarint(b.buf[b.r:b.w])
 	if n == 0 {
 		return 0, io.EOF
 	}
@@ -381,37 +409,46 @@ func (b *FixedBuffer) Write(p []byte) (n int, err error) {
 	return
 }
 
-func (b *FixedBuffer) ReadString() (string, error) {
-	i, n := binary.Varint(b.buf[b.r:])
+// peekLenPrefixed 获取以 varint 长度为前缀的数据在buf中的视图, 以及包含长度前缀
+// 在内占用的字节数, 不移动读取位置
+func (b *FixedBuffer) peekLenPrefixed(k lenKind) ([]byte, int, error) {
+	i, n := binary.Varint(b.buf[b.r:b.w])
 	if n == 0 {
-		return "", io.EOF
+		return nil, 0, io.EOF
 	}
 	if n < 0 || n > MaxStringLenLen {
-		return "", errors.WithMessage(ErrVarintOverflow, "read length")
+		return nil, 0, errors.WithMessage(ErrVarintOverflow, "read length")
 	}
 
 	l := int(i)
-	if l < 0 {
-		return "", buffer.ErrStringLenExceedLimit
+	if err := b.lim.checkRead(k, l); err != nil {
+		return nil, 0, err
 	}
 
 	if l+n > b.Readable() {
-		return "", io.ErrUnexpectedEOF
+		return nil, 0, io.ErrUnexpectedEOF
 	}
 
-	b.r += n
-	if l == 0 {
-		return "", nil
+	return b.buf[b.r+n : b.r+n+l : b.r+n+l], n + l, nil
+}
+
+// readLenPrefixed 读取以 varint 长度为前缀的数据, 返回buf中数据的视图
+// 长度超过限制时返回错误, 且不移动读取位置.
+func (b *FixedBuffer) readLenPrefixed(k lenKind) ([]byte, error) {
+	p, n, err := b.peekLenPrefixed(k)
+	if err != nil {
+		return nil, err
 	}
-	s := string(b.buf[b.r : b.r+l])
-	b.r += l
-	return s, nil
+	b.r += n
+	b.lim.addDecoded(len(p))
+	return p, nil
 }
 
-func (b *FixedBuffer) WriteString(s string) error {
-	l := len(s)
-	if l > MaxStringLength {
-		return buffer.ErrStringLenExceedLimit
+// fixedWriteLenPrefixed 写入以 varint 长度为前缀的数据
+func fixedWriteLenPrefixed[T ~[]byte | ~string](b *FixedBuffer, k lenKind, p T) error {
+	l := len(p)
+	if err := b.lim.checkWrite(k, l); err != nil {
+		return err
 	}
 
 	var buf [MaxStringLenLen]byte
@@ -424,11 +461,72 @@ func (b *FixedBuffer) WriteString(s string) error {
 	b.slideReadable()
 	copy(b.buf[b.w:], buf[:ll])
 	b.w += ll
-	copy(b.buf[b.w:], s)
+	copy(b.buf[b.w:], p)
 	b.w += l
 	return nil
 }
 
+func (b *FixedBuffer) ReadString() (string, error) {
+	p, err := b.readLenPrefixed(lenString)
+	if err != nil || len(p) == 0 {
+		return "", err
+	}
+	return string(p), nil
+}
+
+func (b *FixedBuffer) WriteString(s string) error {
+	return fixedWriteLenPrefixed(b, lenString, s)
+}
+
+// ReadBytes 读取 WriteBytes 写入的字节切片, 编码与字符串一致, 长度为 0 时返回 nil
+func (b *FixedBuffer) ReadBytes() ([]byte, error) {
+	p, err := b.readLenPrefixed(lenBytes)
+	if err != nil || len(p) == 0 {
+		return nil, err
+	}
+	return append([]byte(nil), p...), nil
+}
+
+// WriteBytes 写入以 varint 长度为前缀的字节切片
+func (b *FixedBuffer) WriteBytes(p []byte) error {
+	return fixedWriteLenPrefixed(b, lenBytes, p)
+}
+
+// ReadStringNoCopy 读取字符串, 返回的字符串直接引用buf中的数据, 不分配内存
+// 返回值仅在下一次写入、Reset 或 SetBuf 之前有效, 写入时未读数据会被滑动至buf
+// 最前端, 覆盖已读数据. 以 gutils_bufdebug 构建标签编译时, 失效的数据将被填充为
+// 0xdd, 以便在测试中发现误用.
+func (b *FixedBuffer) ReadStringNoCopy() (string, error) {
+	p, err := b.readLenPrefixed(lenString)
+	if err != nil || len(p) == 0 {
+		return "", err
+	}
+	b.lent.lend(p)
+	return unsafe.String(&p[0], len(p)), nil
+}
+
+// ReadBytesNoCopy 读取字节切片, 返回的切片直接引用buf中的数据, 有效期与
+// ReadStringNoCopy 相同. 调用方不得修改返回的切片.
+func (b *FixedBuffer) ReadBytesNoCopy() ([]byte, error) {
+	p, err := b.readLenPrefixed(lenBytes)
+	if err != nil || len(p) == 0 {
+		return nil, err
+	}
+	b.lent.lend(p)
+	return p, nil
+}
+
+// PeekString 获取下一个字符串但不移动读取位置, 返回的字符串直接引用buf中的数据,
+// 有效期与 ReadStringNoCopy 相同
+func (b *FixedBuffer) PeekString() (string, error) {
+	p, _, err := b.peekLenPrefixed(lenString)
+	if err != nil || len(p) == 0 {
+		return "", err
+	}
+	b.lent.lend(p)
+	return unsafe.String(&p[0], len(p)), nil
+}
+
 func (b *FixedBuffer) ReadFrom(r io.Reader) (int64, error) {
 	if b.Writable() == 0 {
 		return 0, buffer.ErrBufferFull
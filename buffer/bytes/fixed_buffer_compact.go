@@ -0,0 +1,91 @@
+package bytes
+
+// ReadFloat16 读取本地字节序的半精度浮点数
+func (b *FixedBuffer) ReadFloat16() (float32, error) {
+	h, err := b.readUint16(nativeEndian)
+	if err != nil {
+		return 0, err
+	}
+	return Float16ToFloat32(h), nil
+}
+
+// WriteFloat16 将 f 转换为半精度浮点数后以本地字节序写入, 占用 2 个字节
+// 精度约为 3 位有效数字, 超出半精度范围的值写入为无穷大.
+func (b *FixedBuffer) WriteFloat16(f float32) error {
+	return b.writeUint16(Float32ToFloat16(f), nativeEndian)
+}
+
+// ReadBigFloat16 读取大端字节序的半精度浮点数
+func (b *FixedBuffer) ReadBigFloat16() (float32, error) {
+	h, err := b.readUint16(bigEndian)
+	if err != nil {
+		return 0, err
+	}
+	return Float16ToFloat32(h), nil
+}
+
+// WriteBigFloat16 将 f 转换为半精度浮点数后以大端字节序写入, 占用 2 个字节
+// 精度约为 3 位有效数字, 超出半精度范围的值写入为无穷大.
+func (b *FixedBuffer) WriteBigFloat16(f float32) error {
+	return b.writeUint16(Float32ToFloat16(f), bigEndian)
+}
+
+// ReadLitFloat16 读取小端字节序的半精度浮点数
+func (b *FixedBuffer) ReadLitFloat16() (float32, error) {
+	h, err := b.readUint16(littleEndian)
+	if err != nil {
+		return 0, err
+	}
+	return Float16ToFloat32(h), nil
+}
+
+// WriteLitFloat16 将 f 转换为半精度浮点数后以小端字节序写入, 占用 2 个字节
+// 精度约为 3 位有效数字, 超出半精度范围的值写入为无穷大.
+func (b *FixedBuffer) WriteLitFloat16(f float32) error {
+	return b.writeUint16(Float32ToFloat16(f), littleEndian)
+}
+
+// ReadQuantized32 读取 WriteQuantized32 写入的量化浮点数, scale 须与写入时一致
+func (b *FixedBuffer) ReadQuantized32(scale float32) (float32, error) {
+	if err := checkScale(float64(scale)); err != nil {
+		return 0, err
+	}
+	q, err := b.ReadVarint64()
+	if err != nil {
+		return 0, err
+	}
+	return float32(dequantize(q, float64(scale))), nil
+}
+
+// WriteQuantized32 以 scale 为缩放系数将 f 量化为定点整数, 并以 varint 编码写入
+// 例如 scale 为 100 时保留两位小数, 1.234 写入为整数 123, 绝对值较小的值仅占用
+// 1~2 个字节. 量化结果超出 int64 范围或 f 为 NaN 时返回 ErrQuantizeOverflow,
+// scale 不是有限的正数时返回 ErrInvalidScale.
+func (b *FixedBuffer) WriteQuantized32(f float32, scale float32) (int, error) {
+	q, err := quantize(float64(f), float64(scale))
+	if err != nil {
+		return 0, err
+	}
+	return b.WriteVarint64(q)
+}
+
+// ReadQuantized64 读取 WriteQuantized64 写入的量化浮点数, scale 须与写入时一致
+func (b *FixedBuffer) ReadQuantized64(scale float64) (float64, error) {
+	if err := checkScale(scale); err != nil {
+		return 0, err
+	}
+	q, err := b.ReadVarint64()
+	if err != nil {
+		return 0, err
+	}
+	return dequantize(q, scale), nil
+}
+
+// WriteQuantized64 以 scale 为缩放系数将 f 量化为定点整数, 并以 varint 编码写入
+func (b *FixedBuffer) WriteQuantized64(f float64, scale float64) (int, error) {
+	q, err := quantize(f, scale)
+	if err != nil {
+		return 0, err
+	}
+	return b.WriteVarint64(q)
+}
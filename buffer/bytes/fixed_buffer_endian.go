@@ -124,33 +124,27 @@ func (b *FixedBuffer) writeUint64(i uint64, bo byteOrder) error {
 }
 
 func (b *FixedBuffer) writeFloat32(f float32, bo byteOrder) error {
-	var buf [4]byte
-	bo.PutUint32(buf[:], math.Float32bits(f))
-	_, err := b.Write(buf[:])
-	return err
+	return b.writeUint32(math.Float32bits(f), bo)
 }
 
 func (b *FixedBuffer) readFloat32(bo byteOrder) (float32, error) {
-	var buf [4]byte
-	if _, err := b.Read(buf[:]); err != nil {
+	i, err := b.readUint32(bo)
+	if err != nil {
 		return 0, err
 	}
-	return math.Float32frombits(bo.Uint32(buf[:])), nil
+	return math.Float32frombits(i), nil
 }
 
 func (b *FixedBuffer) writeFloat64(f float64, bo byteOrder) error {
-	var buf [8]byte
-	bo.PutUint64(buf[:], math.Float64bits(f))
-	_, err := b.Write(buf[:])
-	return err
+	return b.writeUint64(math.Float64bits(f), bo)
 }
 
 func (b *FixedBuffer) readFloat64(bo byteOrder) (float64, error) {
-	var buf [8]byte
-	if _, err := b.Read(buf[:]); err != nil {
+	i, err := b.readUint64(bo)
+	if err != nil {
 		return 0, err
 	}
-	return math.Float64frombits(bo.Uint64(buf[:])), nil
+	return math.Float64frombits(i), nil
 }
 
 func (b *FixedBuffer) ReadBigInt16() (int16, error) {
@@ -1,12 +1,14 @@
 package bytes
 
 import (
+	"io"
 	"math"
 	"math/rand"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/godyy/gutils/buffer"
 	"github.com/rs/xid"
 )
 
@@ -276,3 +278,20 @@ func TestFixedBuffer(t *testing.T) {
 	}
 	t.Logf("buffered: %d, available: %d", b.Readable(), b.Writable())
 }
+
+func TestFixedBuffer_FloatPartial(t *testing.T) {
+	b := NewFixedBuffer(6)
+	if err := b.WriteBigFloat64(1); err != buffer.ErrExceedBufferLimit {
+		t.Fatalf("WriteBigFloat64: expected ErrExceedBufferLimit, got %v", err)
+	}
+	if b.Readable() != 0 {
+		t.Fatalf("WriteBigFloat64 must not write partially")
+	}
+	_ = b.WriteBigUint32(1)
+	if _, err := b.ReadBigFloat64(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("ReadBigFloat64: expected io.ErrUnexpectedEOF, got %v", err)
+	}
+	if b.Readable() != 4 {
+		t.Fatalf("ReadBigFloat64 must not read partially")
+	}
+}
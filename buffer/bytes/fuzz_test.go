@@ -0,0 +1,112 @@
+package bytes
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+
+	"github.com/godyy/gutils/buffer/buffertest"
+)
+
+// newStaleFixedBuffer 创建包含 data 的 FixedBuffer, 可写入空间中残留 0x80 字节,
+// 用于检查读取是否越过写入位置
+func newStaleFixedBuffer(data []byte) *FixedBuffer {
+	b := NewFixedBuffer(len(data) + MaxVarintLen64)
+	_, _ = b.Write(bytes.Repeat([]byte{0x80}, b.Size()))
+	_, _ = b.Skip(b.Size())
+	_, _ = b.Write(data)
+	return b
+}
+
+// truncateCuts 获取检查截断时的截断位置: 每个操作的起始、中间及最后一个字节
+func truncateCuts(ops []buffertest.Op) []int {
+	var cuts []int
+	off := 0
+	for _, op := range ops {
+		if n := op.Size(); n > 0 {
+			cuts = append(cuts, off, off+n/2, off+n-1)
+			off += n
+		}
+	}
+	return cuts
+}
+
+// checkOps 检查 ops 在 Buffer 与 FixedBuffer 上的往返、截断及交叉兼容性
+func checkOps(t *testing.T, ops []buffertest.Op) {
+	t.Helper()
+	size := buffertest.Size(ops)
+
+	b := NewBuffer(nil)
+	if err := buffertest.Write(b, ops); err != nil {
+		t.Fatalf("Buffer.Write: %v", err)
+	}
+	data := b.Data()
+	if len(data) != size {
+		t.Fatalf("Buffer encoded %d bytes, want %d", len(data), size)
+	}
+
+	fb := NewFixedBuffer(max(size, 1))
+	if err := buffertest.Write(fb, ops); err != nil {
+		t.Fatalf("FixedBuffer.Write: %v", err)
+	}
+	if !bytes.Equal(fb.UnreadData(), data) {
+		t.Fatalf("FixedBuffer encoding differs from Buffer")
+	}
+
+	if err := buffertest.Read(b, ops); err != nil {
+		t.Fatalf("Buffer.Read: %v", err)
+	}
+	if err := buffertest.Read(fb, ops); err != nil {
+		t.Fatalf("FixedBuffer.Read: %v", err)
+	}
+	if err := buffertest.Read(newStaleFixedBuffer(data), ops); err != nil {
+		t.Fatalf("FixedBuffer.Read Buffer encoding: %v", err)
+	}
+	if b.Readable() != 0 || fb.Readable() != 0 {
+		t.Fatalf("unread data left: %d, %d", b.Readable(), fb.Readable())
+	}
+
+	for _, cut := range truncateCuts(ops) {
+		if err := buffertest.ReadTruncated(NewBuffer(data[:cut]), ops, cut); err != nil {
+			t.Fatalf("Buffer: %v", err)
+		}
+		if err := buffertest.ReadTruncated(newStaleFixedBuffer(data[:cut]), ops, cut); err != nil {
+			t.Fatalf("FixedBuffer: %v", err)
+		}
+	}
+}
+
+func TestBuffer_Property(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		checkOps(t, buffertest.Generate(r, 1+r.Intn(50)))
+	}
+}
+
+func FuzzBuffer_RoundTrip(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{byte(buffertest.KindVarint16), 0xff, 0x7f})
+	f.Add([]byte{byte(buffertest.KindUvarint64), 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	f.Add([]byte{byte(buffertest.KindString), 3, 'a', 'b', 'c', byte(buffertest.KindLitFloat64), 1})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		checkOps(t, buffertest.FromBytes(data))
+	})
+}
+
+// FuzzBuffer_Decode 以任意数据解码, Buffer 与 FixedBuffer 的结果须一致且不 panic
+func FuzzBuffer_Decode(f *testing.F) {
+	f.Add([]byte{byte(buffertest.KindVarint16)}, []byte{0x80, 0x80, 0x80, 0x01})
+	f.Add([]byte{byte(buffertest.KindUvarint16)}, []byte{0xff, 0xff, 0x7f})
+	f.Add([]byte{byte(buffertest.KindVarint32)}, []byte{0x80, 0x80})
+	f.Add([]byte{byte(buffertest.KindString)}, []byte{0x0a, 'a'})
+	f.Add([]byte{byte(buffertest.KindUvarint64)}, bytes.Repeat([]byte{0xff}, 11))
+	f.Fuzz(func(t *testing.T, kinds, data []byte) {
+		var ops []buffertest.Op
+		for _, k := range kinds {
+			ops = append(ops, buffertest.FromBytes([]byte{k})...)
+		}
+		if err := buffertest.Compare(NewBuffer(data), newStaleFixedBuffer(data), ops); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
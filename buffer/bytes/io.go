@@ -0,0 +1,570 @@
+package bytes
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/godyy/gutils/buffer"
+	pkg_errors "github.com/pkg/errors"
+)
+
+// IOReader 将任意 io.Reader 适配为 buffer.Reader
+// 编码格式与 Buffer 一致, 定长数据通过内部的临时缓冲区读取. varint 及字符串长度
+// 需逐字节读取, 若 r 未实现 io.ByteReader, 建议以 bufio.Reader 包装.
+// 与 Buffer 不同, varint 读取了部分字节后遇到 io.EOF 时返回 io.ErrUnexpectedEOF.
+type IOReader struct {
+	r       io.Reader
+	br      io.ByteReader
+	n       int64   // 已读取的字节数
+	lim     *limits // 解码限制
+	one     [1]byte // ReadByte 使用的缓冲区, 与 scratch 分离以便逐字节读取 varint
+	scratch [MaxVarintLen64]byte
+}
+
+var _ buffer.Reader = (*IOReader)(nil)
+
+// ioReadChunk IOReader 读取长度前缀数据时的初始分配大小
+const ioReadChunk = 4 << 10
+
+// NewIOReader 创建读取 r 的 IOReader, opts 用于配置解码限制
+func NewIOReader(r io.Reader, opts ...Option) *IOReader {
+	br, _ := r.(io.ByteReader)
+	return &IOReader{r: r, br: br, lim: buildLimits(opts)}
+}
+
+// readFull 读取 n 个字节至临时缓冲区
+func (r *IOReader) readFull(n int) ([]byte, error) {
+	p := r.scratch[:n]
+	m, err := io.ReadFull(r.r, p)
+	r.n += int64(m)
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// readVarint 读取最多 maxLen 个字节的 varint 编码至临时缓冲区
+func (r *IOReader) readVarint(maxLen int) ([]byte, error) {
+	for i := 0; i < maxLen; i++ {
+		c, err := r.ReadByte()
+		if err != nil {
+			if err == io.EOF && i > 0 {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		r.scratch[i] = c
+		if c < 0x80 {
+			return r.scratch[:i+1], nil
+		}
+	}
+	return nil, ErrVarintOverflow
+}
+
+func (r *IOReader) readVarint64(maxLen int) (int64, error) {
+	p, err := r.readVarint(maxLen)
+	if err != nil {
+		return 0, err
+	}
+	i, n := binary.Varint(p)
+	if n <= 0 {
+		return 0, ErrVarintOverflow
+	}
+	return i, nil
+}
+
+func (r *IOReader) readUvarint64(maxLen int) (uint64, error) {
+	p, err := r.readVarint(maxLen)
+	if err != nil {
+		return 0, err
+	}
+	i, n := binary.Uvarint(p)
+	if n <= 0 {
+		return 0, ErrVarintOverflow
+	}
+	return i, nil
+}
+
+func (r *IOReader) readUint16(bo byteOrder) (uint16, error) {
+	p, err := r.readFull(2)
+	if err != nil {
+		return 0, err
+	}
+	return bo.Uint16(p), nil
+}
+
+func (r *IOReader) readUint32(bo byteOrder) (uint32, error) {
+	p, err := r.readFull(4)
+	if err != nil {
+		return 0, err
+	}
+	return bo.Uint32(p), nil
+}
+
+func (r *IOReader) readUint64(bo byteOrder) (uint64, error) {
+	p, err := r.readFull(8)
+	if err != nil {
+		return 0, err
+	}
+	return bo.Uint64(p), nil
+}
+
+func (r *IOReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func (r *IOReader) ReadByte() (byte, error) {
+	if r.br != nil {
+		c, err := r.br.ReadByte()
+		if err == nil {
+			r.n++
+		}
+		return c, err
+	}
+	n, err := io.ReadFull(r.r, r.one[:])
+	r.n += int64(n)
+	if err != nil {
+		return 0, err
+	}
+	return r.one[0], nil
+}
+
+func (r *IOReader) ReadInt8() (int8, error) {
+	c, err := r.ReadByte()
+	return int8(c), err
+}
+
+func (r *IOReader) ReadUint8() (uint8, error) {
+	return r.ReadByte()
+}
+
+func (r *IOReader) ReadBool() (bool, error) {
+	c, err := r.ReadByte()
+	if err != nil {
+		return false, err
+	}
+	return c == 1, nil
+}
+
+func (r *IOReader) ReadInt16() (int16, error) {
+	i, err := r.readUint16(nativeEndian)
+	return int16(i), err
+}
+
+func (r *IOReader) ReadUint16() (uint16, error) {
+	return r.readUint16(nativeEndian)
+}
+
+func (r *IOReader) ReadInt32() (int32, error) {
+	i, err := r.readUint32(nativeEndian)
+	return int32(i), err
+}
+
+func (r *IOReader) ReadUint32() (uint32, error) {
+	return r.readUint32(nativeEndian)
+}
+
+func (r *IOReader) ReadInt64() (int64, error) {
+	i, err := r.readUint64(nativeEndian)
+	return int64(i), err
+}
+
+func (r *IOReader) ReadUint64() (uint64, error) {
+	return r.readUint64(nativeEndian)
+}
+
+func (r *IOReader) ReadFloat32() (float32, error) {
+	i, err := r.readUint32(nativeEndian)
+	return math.Float32frombits(i), err
+}
+
+func (r *IOReader) ReadFloat64() (float64, error) {
+	i, err := r.readUint64(nativeEndian)
+	return math.Float64frombits(i), err
+}
+
+func (r *IOReader) ReadBigInt16() (int16, error) {
+	i, err := r.readUint16(bigEndian)
+	return int16(i), err
+}
+
+func (r *IOReader) ReadBigUint16() (uint16, error) {
+	return r.readUint16(bigEndian)
+}
+
+func (r *IOReader) ReadBigInt32() (int32, error) {
+	i, err := r.readUint32(bigEndian)
+	return int32(i), err
+}
+
+func (r *IOReader) ReadBigUint32() (uint32, error) {
+	return r.readUint32(bigEndian)
+}
+
+func (r *IOReader) ReadBigInt64() (int64, error) {
+	i, err := r.readUint64(bigEndian)
+	return int64(i), err
+}
+
+func (r *IOReader) ReadBigUint64() (uint64, error) {
+	return r.readUint64(bigEndian)
+}
+
+func (r *IOReader) ReadBigFloat32() (float32, error) {
+	i, err := r.readUint32(bigEndian)
+	return math.Float32frombits(i), err
+}
+
+func (r *IOReader) ReadBigFloat64() (float64, error) {
+	i, err := r.readUint64(bigEndian)
+	return math.Float64frombits(i), err
+}
+
+func (r *IOReader) ReadLitInt16() (int16, error) {
+	i, err := r.readUint16(littleEndian)
+	return int16(i), err
+}
+
+func (r *IOReader) ReadLitUint16() (uint16, error) {
+	return r.readUint16(littleEndian)
+}
+
+func (r *IOReader) ReadLitInt32() (int32, error) {
+	i, err := r.readUint32(littleEndian)
+	return int32(i), err
+}
+
+func (r *IOReader) ReadLitUint32() (uint32, error) {
+	return r.readUint32(littleEndian)
+}
+
+func (r *IOReader) ReadLitInt64() (int64, error) {
+	i, err := r.readUint64(littleEndian)
+	return int64(i), err
+}
+
+func (r *IOReader) ReadLitUint64() (uint64, error) {
+	return r.readUint64(littleEndian)
+}
+
+func (r *IOReader) ReadLitFloat32() (float32, error) {
+	i, err := r.readUint32(littleEndian)
+	return math.Float32frombits(i), err
+}
+
+func (r *IOReader) ReadLitFloat64() (float64, error) {
+	i, err := r.readUint64(littleEndian)
+	return math.Float64frombits(i), err
+}
+
+func (r *IOReader) ReadVarint16() (int16, error) {
+	i, err := r.readVarint64(MaxVarintLen16)
+	return int16(i), err
+}
+
+func (r *IOReader) ReadUvarint16() (uint16, error) {
+	i, err := r.readUvarint64(MaxVarintLen16)
+	return uint16(i), err
+}
+
+func (r *IOReader) ReadVarint32() (int32, error) {
+	i, err := r.readVarint64(MaxVarintLen32)
+	return int32(i), err
+}
+
+func (r *IOReader) ReadUvarint32() (uint32, error) {
+	i, err := r.readUvarint64(MaxVarintLen32)
+	return uint32(i), err
+}
+
+func (r *IOReader) ReadVarint64() (int64, error) {
+	return r.readVarint64(MaxVarintLen64)
+}
+
+func (r *IOReader) ReadUvarint64() (uint64, error) {
+	return r.readUvarint64(MaxVarintLen64)
+}
+
+// readLenPrefixed 读取以 varint 长度为前缀的数据
+// 按实际读取到的数据逐步扩容, 避免恶意长度前缀导致一次性分配过量内存.
+func (r *IOReader) readLenPrefixed(k lenKind) ([]byte, error) {
+	l, err := r.readVarint64(MaxStringLenLen)
+	if err != nil {
+		if err == ErrVarintOverflow {
+			err = pkg_errors.WithMessage(err, "read length")
+		}
+		return nil, err
+	}
+	if l > MaxStringLength {
+		return nil, k.errExceed()
+	}
+	n := int(l)
+	if err := r.lim.checkRead(k, n); err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, nil
+	}
+
+	p, m, err := readGrowing(r.r, make([]byte, 0, min(n, ioReadChunk)), n)
+	r.n += int64(m)
+	if err != nil {
+		return nil, err
+	}
+	r.lim.addDecoded(n)
+	return p, nil
+}
+
+// readGrowing 自 r 中读取数据追加至 p, 直至 len(p) 达到 n, 返回追加后的 p 及读取的
+// 字节数
+// 每次至多将 p 的容量扩大一倍, 避免虚假的长度前缀导致一次性过量分配. 数据不足时
+// 返回 io.ErrUnexpectedEOF.
+func readGrowing(r io.Reader, p []byte, n int) ([]byte, int, error) {
+	read := 0
+	for len(p) < n {
+		if len(p) == cap(p) {
+			p = slices.Grow(p, min(n-len(p), max(cap(p), ioReadChunk)))
+		}
+		m, err := io.ReadFull(r, p[len(p):min(n, cap(p))])
+		p = p[:len(p)+m]
+		read += m
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, read, err
+		}
+	}
+	return p, read, nil
+}
+
+func (r *IOReader) ReadString() (string, error) {
+	p, err := r.readLenPrefixed(lenString)
+	if err != nil || len(p) == 0 {
+		return "", err
+	}
+	return string(p), nil
+}
+
+// ReadBytes 读取以 varint 长度为前缀的字节切片, 长度为 0 时返回 nil
+func (r *IOReader) ReadBytes() ([]byte, error) {
+	return r.readLenPrefixed(lenBytes)
+}
+
+// IOWriter 将任意 io.Writer 适配为 buffer.Writer
+// 编码格式与 Buffer 一致, 每次写入均先编码至内部的临时缓冲区再写入 w, 不做额外
+// 缓冲, 大量小数据写入时建议以 bufio.Writer 包装.
+type IOWriter struct {
+	w       io.Writer
+	lim     *limits // 长度限制
+	scratch [MaxVarintLen64]byte
+}
+
+var _ buffer.Writer = (*IOWriter)(nil)
+
+// NewIOWriter 创建写入 w 的 IOWriter, opts 用于配置长度限制
+func NewIOWriter(w io.Writer, opts ...Option) *IOWriter {
+	return &IOWriter{w: w, lim: buildLimits(opts)}
+}
+
+// write 写入临时缓冲区中的前 n 个字节
+func (w *IOWriter) write(n int) error {
+	_, err := w.w.Write(w.scratch[:n])
+	return err
+}
+
+func (w *IOWriter) writeUint16(i uint16, bo byteOrder) error {
+	bo.PutUint16(w.scratch[:2], i)
+	return w.write(2)
+}
+
+func (w *IOWriter) writeUint32(i uint32, bo byteOrder) error {
+	bo.PutUint32(w.scratch[:4], i)
+	return w.write(4)
+}
+
+func (w *IOWriter) writeUint64(i uint64, bo byteOrder) error {
+	bo.PutUint64(w.scratch[:8], i)
+	return w.write(8)
+}
+
+func (w *IOWriter) writeVarint(i int64) (int, error) {
+	n := binary.PutVarint(w.scratch[:], i)
+	return n, w.write(n)
+}
+
+func (w *IOWriter) writeUvarint(i uint64) (int, error) {
+	n := binary.PutUvarint(w.scratch[:], i)
+	return n, w.write(n)
+}
+
+func (w *IOWriter) Write(p []byte) (int, error) {
+	return w.w.Write(p)
+}
+
+func (w *IOWriter) WriteByte(c byte) error {
+	w.scratch[0] = c
+	return w.write(1)
+}
+
+func (w *IOWriter) WriteInt8(i int8) error {
+	return w.WriteByte(byte(i))
+}
+
+func (w *IOWriter) WriteUint8(i uint8) error {
+	return w.WriteByte(i)
+}
+
+func (w *IOWriter) WriteBool(v bool) error {
+	if v {
+		return w.WriteByte(1)
+	}
+	return w.WriteByte(0)
+}
+
+func (w *IOWriter) WriteInt16(i int16) error {
+	return w.writeUint16(uint16(i), nativeEndian)
+}
+
+func (w *IOWriter) WriteUint16(i uint16) error {
+	return w.writeUint16(i, nativeEndian)
+}
+
+func (w *IOWriter) WriteInt32(i int32) error {
+	return w.writeUint32(uint32(i), nativeEndian)
+}
+
+func (w *IOWriter) WriteUint32(i uint32) error {
+	return w.writeUint32(i, nativeEndian)
+}
+
+func (w *IOWriter) WriteInt64(i int64) error {
+	return w.writeUint64(uint64(i), nativeEndian)
+}
+
+func (w *IOWriter) WriteUint64(i uint64) error {
+	return w.writeUint64(i, nativeEndian)
+}
+
+func (w *IOWriter) WriteFloat32(f float32) error {
+	return w.writeUint32(math.Float32bits(f), nativeEndian)
+}
+
+func (w *IOWriter) WriteFloat64(f float64) error {
+	return w.writeUint64(math.Float64bits(f), nativeEndian)
+}
+
+func (w *IOWriter) WriteBigInt16(i int16) error {
+	return w.writeUint16(uint16(i), bigEndian)
+}
+
+func (w *IOWriter) WriteBigUint16(i uint16) error {
+	return w.writeUint16(i, bigEndian)
+}
+
+func (w *IOWriter) WriteBigInt32(i int32) error {
+	return w.writeUint32(uint32(i), bigEndian)
+}
+
+func (w *IOWriter) WriteBigUint32(i uint32) error {
+	return w.writeUint32(i, bigEndian)
+}
+
+func (w *IOWriter) WriteBigInt64(i int64) error {
+	return w.writeUint64(uint64(i), bigEndian)
+}
+
+func (w *IOWriter) WriteBigUint64(i uint64) error {
+	return w.writeUint64(i, bigEndian)
+}
+
+func (w *IOWriter) WriteBigFloat32(f float32) error {
+	return w.writeUint32(math.Float32bits(f), bigEndian)
+}
+
+func (w *IOWriter) WriteBigFloat64(f float64) error {
+	return w.writeUint64(math.Float64bits(f), bigEndian)
+}
+
+func (w *IOWriter) WriteLitInt16(i int16) error {
+	return w.writeUint16(uint16(i), littleEndian)
+}
+
+func (w *IOWriter) WriteLitUint16(i uint16) error {
+	return w.writeUint16(i, littleEndian)
+}
+
+func (w *IOWriter) WriteLitInt32(i int32) error {
+	return w.writeUint32(uint32(i), littleEndian)
+}
+
+func (w *IOWriter) WriteLitUint32(i uint32) error {
+	return w.writeUint32(i, littleEndian)
+}
+
+func (w *IOWriter) WriteLitInt64(i int64) error {
+	return w.writeUint64(uint64(i), littleEndian)
+}
+
+func (w *IOWriter) WriteLitUint64(i uint64) error {
+	return w.writeUint64(i, littleEndian)
+}
+
+func (w *IOWriter) WriteLitFloat32(f float32) error {
+	return w.writeUint32(math.Float32bits(f), littleEndian)
+}
+
+func (w *IOWriter) WriteLitFloat64(f float64) error {
+	return w.writeUint64(math.Float64bits(f), littleEndian)
+}
+
+func (w *IOWriter) WriteVarint16(i int16) (int, error) {
+	return w.writeVarint(int64(i))
+}
+
+func (w *IOWriter) WriteUvarint16(i uint16) (int, error) {
+	return w.writeUvarint(uint64(i))
+}
+
+func (w *IOWriter) WriteVarint32(i int32) (int, error) {
+	return w.writeVarint(int64(i))
+}
+
+func (w *IOWriter) WriteUvarint32(i uint32) (int, error) {
+	return w.writeUvarint(uint64(i))
+}
+
+func (w *IOWriter) WriteVarint64(i int64) (int, error) {
+	return w.writeVarint(i)
+}
+
+func (w *IOWriter) WriteUvarint64(i uint64) (int, error) {
+	return w.writeUvarint(i)
+}
+
+func (w *IOWriter) WriteString(s string) error {
+	if err := w.lim.checkWrite(lenString, len(s)); err != nil {
+		return err
+	}
+	if _, err := w.writeVarint(int64(len(s))); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w.w, s)
+	return err
+}
+
+// WriteBytes 写入以 varint 长度为前缀的字节切片
+func (w *IOWriter) WriteBytes(p []byte) error {
+	if err := w.lim.checkWrite(lenBytes, len(p)); err != nil {
+		return err
+	}
+	if _, err := w.writeVarint(int64(len(p))); err != nil {
+		return err
+	}
+	_, err := w.w.Write(p)
+	return err
+}
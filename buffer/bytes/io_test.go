@@ -0,0 +1,187 @@
+package bytes
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"testing/iotest"
+
+	"github.com/godyy/gutils/buffer"
+)
+
+func writeIOSample(w buffer.Writer) error {
+	_ = w.WriteBool(true)
+	_ = w.WriteInt8(-1)
+	_ = w.WriteUint16(0x1234)
+	_ = w.WriteBigInt32(-5)
+	_ = w.WriteLitUint64(1 << 60)
+	_ = w.WriteBigFloat32(1.5)
+	_ = w.WriteLitFloat64(-2.25)
+	_, _ = w.WriteVarint16(-300)
+	_, _ = w.WriteUvarint32(1 << 30)
+	_, _ = w.WriteVarint64(-1 << 40)
+	_ = w.WriteString("hello")
+	_ = w.WriteString("")
+	_, err := w.Write([]byte{1, 2, 3})
+	return err
+}
+
+func readIOSample(t *testing.T, r buffer.Reader) {
+	t.Helper()
+	check := func(name string, got, want any, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got != want {
+			t.Fatalf("%s: got %v, want %v", name, got, want)
+		}
+	}
+
+	b, err := r.ReadBool()
+	check("ReadBool", b, true, err)
+	i8, err := r.ReadInt8()
+	check("ReadInt8", i8, int8(-1), err)
+	u16, err := r.ReadUint16()
+	check("ReadUint16", u16, uint16(0x1234), err)
+	i32, err := r.ReadBigInt32()
+	check("ReadBigInt32", i32, int32(-5), err)
+	u64, err := r.ReadLitUint64()
+	check("ReadLitUint64", u64, uint64(1<<60), err)
+	f32, err := r.ReadBigFloat32()
+	check("ReadBigFloat32", f32, float32(1.5), err)
+	f64, err := r.ReadLitFloat64()
+	check("ReadLitFloat64", f64, -2.25, err)
+	v16, err := r.ReadVarint16()
+	check("ReadVarint16", v16, int16(-300), err)
+	uv32, err := r.ReadUvarint32()
+	check("ReadUvarint32", uv32, uint32(1<<30), err)
+	v64, err := r.ReadVarint64()
+	check("ReadVarint64", v64, int64(-1<<40), err)
+	s, err := r.ReadString()
+	check("ReadString", s, "hello", err)
+	s, err = r.ReadString()
+	check("ReadString", s, "", err)
+	p := make([]byte, 3)
+	_, err = io.ReadFull(r, p)
+	check("Read", string(p), "\x01\x02\x03", err)
+	if _, err := r.ReadByte(); err != io.EOF {
+		t.Fatalf("ReadByte at end: expected io.EOF, got %v", err)
+	}
+}
+
+func TestReadWriter_Compatible(t *testing.T) {
+	want := NewBuffer(nil)
+	if err := writeIOSample(want); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	writers := map[string]buffer.Writer{
+		"FixedBuffer": NewFixedBuffer(want.Readable()),
+		"RingBuffer":  NewRingBuffer(want.Readable()),
+		"IOWriter":    NewIOWriter(&out),
+	}
+	for name, w := range writers {
+		if err := writeIOSample(w); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var data []byte
+		switch w := w.(type) {
+		case *FixedBuffer:
+			data = w.UnreadData()
+		case *RingBuffer:
+			head, tail := w.Segments()
+			data = append(head, tail...)
+		default:
+			data = out.Bytes()
+		}
+		if !bytes.Equal(data, want.Data()) {
+			t.Fatalf("%s: data mismatch\n got: %v\nwant: %v", name, data, want.Data())
+		}
+	}
+
+	readers := map[string]buffer.Reader{
+		"Buffer":         NewBuffer(want.Data()),
+		"IOReader":       NewIOReader(iotest.OneByteReader(bytes.NewReader(want.Data()))),
+		"IOReader/bufio": NewIOReader(bufio.NewReader(bytes.NewReader(want.Data()))),
+	}
+	for name, r := range readers {
+		t.Run(name, func(t *testing.T) {
+			readIOSample(t, r)
+		})
+	}
+}
+
+func TestIOReader_Truncated(t *testing.T) {
+	w := NewBuffer(nil)
+	l, _ := w.WriteVarint64(-1 << 40)
+	_ = w.WriteUint32(1)
+	_ = w.WriteString("hello")
+	data := w.Data()
+
+	r := NewIOReader(bytes.NewReader(data[:2]))
+	if _, err := r.ReadVarint64(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("truncated varint: expected io.ErrUnexpectedEOF, got %v", err)
+	}
+
+	r = NewIOReader(bytes.NewReader(data[:l+2]))
+	if _, err := r.ReadVarint64(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.ReadUint32(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("truncated uint32: expected io.ErrUnexpectedEOF, got %v", err)
+	}
+	if _, err := r.ReadUint32(); err != io.EOF {
+		t.Fatalf("empty uint32: expected io.EOF, got %v", err)
+	}
+
+	r = NewIOReader(bytes.NewReader(data[:len(data)-1]))
+	_, _ = r.ReadVarint64()
+	_, _ = r.ReadUint32()
+	if _, err := r.ReadString(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("truncated string: expected io.ErrUnexpectedEOF, got %v", err)
+	}
+
+	r = NewIOReader(bytes.NewReader([]byte{0x80, 0x80, 0x80, 0x80}))
+	if _, err := r.ReadVarint16(); err != ErrVarintOverflow {
+		t.Fatalf("overflow varint: expected ErrVarintOverflow, got %v", err)
+	}
+}
+
+func TestDecodeValue_Readers(t *testing.T) {
+	msg := codecInner{ID: 7, Name: "value"}
+	data, err := Marshal(&msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fb := NewFixedBuffer(len(data))
+	if err := fb.WriteValue(&msg); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(fb.UnreadData(), data) {
+		t.Fatalf("FixedBuffer.WriteValue mismatch: %v", fb.UnreadData())
+	}
+	var got codecInner
+	if err := fb.ReadValue(&got); err != nil || !reflect.DeepEqual(got, msg) {
+		t.Fatalf("FixedBuffer.ReadValue: %+v, %v", got, err)
+	}
+
+	got = codecInner{}
+	if err := DecodeValue(NewIOReader(bytes.NewReader(data)), &got); err != nil || !reflect.DeepEqual(got, msg) {
+		t.Fatalf("DecodeValue: %+v, %v", got, err)
+	}
+	if err := DecodeValue(NewIOReader(bytes.NewReader(data[:1])), &got); err != io.ErrUnexpectedEOF {
+		t.Fatalf("DecodeValue truncated: expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestEncodeValue_FixedBufferFull(t *testing.T) {
+	fb := NewFixedBuffer(4)
+	if err := fb.WriteValue(struct{ P []byte }{P: make([]byte, 12)}); err != buffer.ErrExceedBufferLimit {
+		t.Fatalf("expected buffer.ErrExceedBufferLimit, got %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package bytes
+
+import (
+	"math"
+
+	"github.com/godyy/gutils/buffer"
+)
+
+// LengthKind 预留长度前缀的编码方式
+type LengthKind int8
+
+const (
+	LengthBigUint16 LengthKind = iota // 大端 uint16
+	LengthLitUint16                   // 小端 uint16
+	LengthBigUint32                   // 大端 uint32
+	LengthLitUint32                   // 小端 uint32
+	LengthUvarint                     // 定宽 MaxVarintLen32 字节的 uvarint, 可由 ReadUvarint32 读取
+	LengthVarint                      // 定宽 MaxStringLenLen 字节的 varint, 与 ReadString/ReadBytes 的长度前缀兼容
+)
+
+// width 长度前缀的字节数
+func (k LengthKind) width() int {
+	switch k {
+	case LengthBigUint16, LengthLitUint16:
+		return 2
+	case LengthBigUint32, LengthLitUint32:
+		return 4
+	case LengthUvarint:
+		return MaxVarintLen32
+	case LengthVarint:
+		return MaxStringLenLen
+	default:
+		panic("bytes: invalid length kind")
+	}
+}
+
+// max 长度前缀可表示的最大长度
+func (k LengthKind) max() uint64 {
+	switch k {
+	case LengthBigUint16, LengthLitUint16:
+		return math.MaxUint16
+	case LengthVarint:
+		return MaxStringLength
+	default:
+		return math.MaxUint32
+	}
+}
+
+// put 将长度 n 编码至 p, len(p) 为 k.width()
+func (k LengthKind) put(p []byte, n uint64) {
+	switch k {
+	case LengthBigUint16:
+		bigEndian.PutUint16(p, uint16(n))
+	case LengthLitUint16:
+		littleEndian.PutUint16(p, uint16(n))
+	case LengthBigUint32:
+		bigEndian.PutUint32(p, uint32(n))
+	case LengthLitUint32:
+		littleEndian.PutUint32(p, uint32(n))
+	case LengthUvarint:
+		putPaddedUvarint(p, n)
+	case LengthVarint:
+		// 与 binary.PutVarint 相同的 zigzag 编码, 长度非负
+		putPaddedUvarint(p, n<<1)
+	}
+}
+
+// putPaddedUvarint 将 x 以恰好 len(p) 个字节的 uvarint 编码写入 p
+// 高位不足时以 0x80 填充, binary.Uvarint 可正确解码此类非最短编码.
+func putPaddedUvarint(p []byte, x uint64) {
+	last := len(p) - 1
+	for i := 0; i < last; i++ {
+		p[i] = byte(x) | 0x80
+		x >>= 7
+	}
+	p[last] = byte(x)
+}
+
+// lengthTarget 支持回填长度前缀的缓冲区
+type lengthTarget interface {
+	// writePos 获取逻辑写入位置
+	writePos() int
+	// reserved 获取逻辑位置 pos 处的 n 个字节
+	reserved(pos, n int) []byte
+}
+
+// LengthHandle 预留的长度前缀, 由 ReserveLength 创建
+// 写入子消息后调用 Finish 回填自预留位置之后写入的字节数.
+type LengthHandle struct {
+	t    lengthTarget
+	pos  int // 预留位置的逻辑位置
+	kind LengthKind
+}
+
+// Finish 回填预留的长度前缀, 长度为预留位置之后写入的字节数
+// 长度超过 kind 可表示的范围时返回 buffer.ErrExceedBufferLimit. Finish 之前不得读取
+// 预留的长度前缀, 缓冲区 Reset/SetBuf 之后句柄失效.
+func (h LengthHandle) Finish() error {
+	w := h.kind.width()
+	n := h.t.writePos() - h.pos - w
+	if uint64(n) > h.kind.max() {
+		return buffer.ErrExceedBufferLimit
+	}
+	h.kind.put(h.t.reserved(h.pos, w), uint64(n))
+	return nil
+}
+
+// Len 获取预留位置之后已写入的字节数
+func (h LengthHandle) Len() int {
+	return h.t.writePos() - h.pos - h.kind.width()
+}
+
+// ReserveLength 在当前写入位置预留 kind 对应字节数的长度前缀, 返回用于回填的句柄
+// 可嵌套使用, 以便单次写入嵌套的 TLV 结构而无需临时缓冲区.
+func (b *Buffer) ReserveLength(kind LengthKind) (LengthHandle, error) {
+	n := kind.width()
+	m, ok := b.tryGrowByReslice(n)
+	if !ok {
+		m = b.grow(n)
+	}
+	clear(b.buf[m : m+n])
+	return LengthHandle{t: b, pos: b.shifted + m, kind: kind}, nil
+}
+
+func (b *Buffer) writePos() int {
+	return b.shifted + len(b.buf)
+}
+
+func (b *Buffer) reserved(pos, n int) []byte {
+	i := pos - b.shifted
+	if i < 0 {
+		panic("bytes.Buffer: reserved length discarded")
+	}
+	return b.buf[i : i+n]
+}
+
+// ReserveLength 在当前写入位置预留 kind 对应字节数的长度前缀, 返回用于回填的句柄
+// 可写入空间不足时返回 buffer.ErrBufferFull 或 buffer.ErrExceedBufferLimit.
+func (b *FixedBuffer) ReserveLength(kind LengthKind) (LengthHandle, error) {
+	n := kind.width()
+	l := b.Writable()
+	if l == 0 {
+		return LengthHandle{}, buffer.ErrBufferFull
+	}
+	if l < n {
+		return LengthHandle{}, buffer.ErrExceedBufferLimit
+	}
+
+	b.slideReadable()
+	clear(b.buf[b.w : b.w+n])
+	h := LengthHandle{t: b, pos: b.shifted + b.w, kind: kind}
+	b.w += n
+	return h, nil
+}
+
+func (b *FixedBuffer) writePos() int {
+	return b.shifted + b.w
+}
+
+func (b *FixedBuffer) reserved(pos, n int) []byte {
+	i := pos - b.shifted
+	if i < 0 {
+		panic("bytes.FixedBuffer: reserved length discarded")
+	}
+	return b.buf[i : i+n]
+}
@@ -0,0 +1,132 @@
+package bytes
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/godyy/gutils/buffer"
+)
+
+func TestBuffer_ReserveLength(t *testing.T) {
+	b := NewBuffer(nil)
+	outer, _ := b.ReserveLength(LengthBigUint32)
+	_ = b.WriteUint16(1)
+	inner, _ := b.ReserveLength(LengthVarint)
+	_, _ = b.Write(make([]byte, 300)) // 触发扩容
+	if err := inner.Finish(); err != nil {
+		t.Fatal(err)
+	}
+	if err := outer.Finish(); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := b.ReadBigUint32()
+	if err != nil || int(n) != 2+MaxStringLenLen+300 || int(n) != b.Readable() {
+		t.Fatalf("outer length: %d, %v", n, err)
+	}
+	_, _ = b.ReadUint16()
+	p, err := b.ReadBytes()
+	if err != nil || len(p) != 300 {
+		t.Fatalf("inner ReadBytes: %d, %v", len(p), err)
+	}
+}
+
+func TestBuffer_ReserveLength_Kinds(t *testing.T) {
+	for _, kind := range []LengthKind{LengthBigUint16, LengthLitUint16, LengthBigUint32, LengthLitUint32, LengthUvarint, LengthVarint} {
+		b := NewBuffer(nil)
+		h, _ := b.ReserveLength(kind)
+		_, _ = b.Write([]byte("hello"))
+		if h.Len() != 5 {
+			t.Fatalf("kind %d: Len %d", kind, h.Len())
+		}
+		if err := h.Finish(); err != nil {
+			t.Fatalf("kind %d: %v", kind, err)
+		}
+
+		var n uint64
+		switch kind {
+		case LengthBigUint16:
+			v, _ := b.ReadBigUint16()
+			n = uint64(v)
+		case LengthLitUint16:
+			v, _ := b.ReadLitUint16()
+			n = uint64(v)
+		case LengthBigUint32:
+			v, _ := b.ReadBigUint32()
+			n = uint64(v)
+		case LengthLitUint32:
+			v, _ := b.ReadLitUint32()
+			n = uint64(v)
+		case LengthUvarint:
+			v, _ := b.ReadUvarint32()
+			n = uint64(v)
+		case LengthVarint:
+			s, err := b.ReadString()
+			if err != nil || s != "hello" {
+				t.Fatalf("LengthVarint: ReadString %q, %v", s, err)
+			}
+			continue
+		}
+		if n != 5 || b.Readable() != 5 {
+			t.Fatalf("kind %d: length %d, readable %d", kind, n, b.Readable())
+		}
+	}
+
+	b := NewBuffer(nil)
+	h, _ := b.ReserveLength(LengthBigUint16)
+	_, _ = b.Write(make([]byte, 1<<16))
+	if err := h.Finish(); err != buffer.ErrExceedBufferLimit {
+		t.Fatalf("uint16 overflow: expected ErrExceedBufferLimit, got %v", err)
+	}
+}
+
+func TestBuffer_ReserveLength_Slide(t *testing.T) {
+	b := NewBuffer(make([]byte, 0, 16))
+	_, _ = b.Write([]byte("0123456789"))
+	_, _ = b.Skip(8)
+	h, _ := b.ReserveLength(LengthLitUint16)
+	// 写入更多数据使 grow 滑动已读数据
+	_, _ = b.Write(make([]byte, 6))
+	if err := h.Finish(); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = b.Skip(2)
+	if n, _ := b.ReadLitUint16(); n != 6 {
+		t.Fatalf("length after slide: %d", n)
+	}
+}
+
+func TestFixedBuffer_ReserveLength(t *testing.T) {
+	want := NewBuffer(nil)
+	h, _ := want.ReserveLength(LengthUvarint)
+	_ = want.WriteString("payload")
+	_ = h.Finish()
+
+	b := NewFixedBuffer(want.Readable())
+	_, _ = b.Write([]byte("xx"))
+	_, _ = b.Skip(2)
+	h, err := b.ReserveLength(LengthUvarint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.WriteString("payload"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Finish(); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b.UnreadData(), want.Data()) {
+		t.Fatalf("data mismatch\n got: %v\nwant: %v", b.UnreadData(), want.Data())
+	}
+
+	if _, err := b.ReserveLength(LengthBigUint16); err != buffer.ErrBufferFull {
+		t.Fatalf("full: expected ErrBufferFull, got %v", err)
+	}
+	b = NewFixedBuffer(3)
+	if _, err := b.ReserveLength(LengthBigUint32); err != buffer.ErrExceedBufferLimit {
+		t.Fatalf("short: expected ErrExceedBufferLimit, got %v", err)
+	}
+	if b.Readable() != 0 {
+		t.Fatalf("failed ReserveLength must not write")
+	}
+}
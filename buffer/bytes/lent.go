@@ -0,0 +1,12 @@
+//go:build !gutils_bufdebug
+
+package bytes
+
+// lentTracker 记录借出的数据视图, 仅在 gutils_bufdebug 构建标签下生效
+type lentTracker struct{}
+
+// lend 记录借出的数据视图
+func (*lentTracker) lend([]byte) {}
+
+// release 使借出的数据视图失效, buf[r:w] 为仍然有效的未读数据
+func (*lentTracker) release([]byte, int, int) {}
@@ -0,0 +1,45 @@
+//go:build gutils_bufdebug
+
+package bytes
+
+import "unsafe"
+
+// poisonByte 填充失效数据视图的字节
+const poisonByte = 0xdd
+
+// lentTracker 记录借出的数据视图
+// 数据视图失效时, 其中不再属于未读数据的部分被填充为 poisonByte, 以便在测试中
+// 发现对失效视图的误用.
+type lentTracker struct {
+	views [][]byte
+}
+
+// lend 记录借出的数据视图
+func (t *lentTracker) lend(p []byte) {
+	if len(p) > 0 {
+		t.views = append(t.views, p)
+	}
+}
+
+// release 使借出的数据视图失效, buf[r:w] 为仍然有效的未读数据
+func (t *lentTracker) release(buf []byte, r, w int) {
+	if len(t.views) == 0 {
+		return
+	}
+
+	var lo, hi uintptr
+	if r < w {
+		lo = uintptr(unsafe.Pointer(&buf[r]))
+		hi = lo + uintptr(w-r)
+	}
+	for _, v := range t.views {
+		base := uintptr(unsafe.Pointer(&v[0]))
+		for i := range v {
+			if p := base + uintptr(i); p < lo || p >= hi {
+				v[i] = poisonByte
+			}
+		}
+	}
+	clear(t.views)
+	t.views = t.views[:0]
+}
@@ -0,0 +1,132 @@
+package bytes
+
+import "github.com/godyy/gutils/buffer"
+
+// limits 解码长度限制, 用于防止恶意长度前缀导致的过量分配
+// 各项值 <= 0 表示不限制. nil 表示未配置任何限制.
+type limits struct {
+	maxStringLen   int // 字符串最大长度
+	maxBytesLen    int // 字节切片最大长度
+	maxDecodedSize int // 字符串及字节切片解码总长度上限
+	decoded        int // 已解码的字符串及字节切片总长度
+}
+
+// Option 用于配置 Buffer 及 FixedBuffer 的解码限制
+type Option interface {
+	apply(*limits)
+}
+
+// buildLimits 根据选项创建解码限制, 无选项时返回 nil
+func buildLimits(opts []Option) *limits {
+	if len(opts) == 0 {
+		return nil
+	}
+	l := &limits{}
+	for _, opt := range opts {
+		opt.apply(l)
+	}
+	return l
+}
+
+type maxStringLengthOption int
+
+func (o maxStringLengthOption) apply(l *limits) {
+	l.maxStringLen = int(o)
+}
+
+// WithMaxStringLength 限制读写字符串的最大长度, 超过时返回 buffer.ErrStringLenExceedLimit
+func WithMaxStringLength(n int) Option {
+	return maxStringLengthOption(n)
+}
+
+type maxBytesLengthOption int
+
+func (o maxBytesLengthOption) apply(l *limits) {
+	l.maxBytesLen = int(o)
+}
+
+// WithMaxBytesLength 限制 ReadBytes/WriteBytes 字节切片的最大长度, 超过时返回
+// buffer.ErrExceedBufferLimit
+func WithMaxBytesLength(n int) Option {
+	return maxBytesLengthOption(n)
+}
+
+type maxDecodedSizeOption int
+
+func (o maxDecodedSizeOption) apply(l *limits) {
+	l.maxDecodedSize = int(o)
+}
+
+// WithMaxDecodedSize 限制自创建或上次 Reset/SetBuf 起, 解码的字符串及字节切片的
+// 总长度, 超过时返回 buffer.ErrExceedBufferLimit
+func WithMaxDecodedSize(n int) Option {
+	return maxDecodedSizeOption(n)
+}
+
+// lenKind 长度前缀数据的类型
+type lenKind int8
+
+const (
+	lenString lenKind = iota // 字符串
+	lenBytes                 // 字节切片
+)
+
+// errExceed 获取长度超过限制时返回的错误
+func (k lenKind) errExceed() error {
+	if k == lenString {
+		return buffer.ErrStringLenExceedLimit
+	}
+	return buffer.ErrExceedBufferLimit
+}
+
+// checkWrite 检查待写入的数据长度 n 是否超过限制
+func (l *limits) checkWrite(k lenKind, n int) error {
+	if n > MaxStringLength {
+		return k.errExceed()
+	}
+	if l == nil {
+		return nil
+	}
+	if m := l.maxLen(k); m > 0 && n > m {
+		return k.errExceed()
+	}
+	return nil
+}
+
+// checkRead 检查待解码的数据长度 n 是否超过限制, 须在分配内存前调用
+func (l *limits) checkRead(k lenKind, n int) error {
+	if n < 0 {
+		return k.errExceed()
+	}
+	if l == nil {
+		return nil
+	}
+	if m := l.maxLen(k); m > 0 && n > m {
+		return k.errExceed()
+	}
+	if l.maxDecodedSize > 0 && n > l.maxDecodedSize-l.decoded {
+		return buffer.ErrExceedBufferLimit
+	}
+	return nil
+}
+
+// addDecoded 累计已解码的数据长度
+func (l *limits) addDecoded(n int) {
+	if l != nil {
+		l.decoded += n
+	}
+}
+
+// reset 重置已解码的数据长度
+func (l *limits) reset() {
+	if l != nil {
+		l.decoded = 0
+	}
+}
+
+func (l *limits) maxLen(k lenKind) int {
+	if k == lenString {
+		return l.maxStringLen
+	}
+	return l.maxBytesLen
+}
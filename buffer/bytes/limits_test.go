@@ -0,0 +1,138 @@
+package bytes
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"testing"
+
+	"github.com/godyy/gutils/buffer"
+)
+
+func TestBuffer_ReadWriteBytes(t *testing.T) {
+	b := NewBuffer(nil)
+	if err := b.WriteBytes([]byte("hello")); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.WriteBytes(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	want := NewBuffer(nil)
+	_ = want.WriteString("hello")
+	_ = want.WriteString("")
+	if !bytes.Equal(b.Data(), want.Data()) {
+		t.Fatalf("WriteBytes encoding mismatch: %v", b.Data())
+	}
+
+	p, err := b.ReadBytes()
+	if err != nil || string(p) != "hello" {
+		t.Fatalf("ReadBytes: %q, %v", p, err)
+	}
+	p[0] = 'H'
+	if b.Data()[1] != 'h' {
+		t.Fatalf("ReadBytes must return a copy")
+	}
+	if p, err = b.ReadBytes(); err != nil || p != nil {
+		t.Fatalf("ReadBytes empty: %v, %v", p, err)
+	}
+	if _, err = b.ReadBytes(); err != io.EOF {
+		t.Fatalf("ReadBytes at end: expected io.EOF, got %v", err)
+	}
+}
+
+func TestBuffer_Limits(t *testing.T) {
+	src := NewBuffer(nil)
+	_ = src.WriteString("0123456789")
+	_ = src.WriteBytes([]byte("0123456789"))
+	data := src.Data()
+
+	b := NewBuffer(data, WithMaxStringLength(5))
+	if _, err := b.ReadString(); err != buffer.ErrStringLenExceedLimit {
+		t.Fatalf("ReadString: expected ErrStringLenExceedLimit, got %v", err)
+	}
+	if b.Readable() != len(data) {
+		t.Fatalf("ReadString must not consume data on limit error")
+	}
+	if err := b.WriteString("0123456789"); err != buffer.ErrStringLenExceedLimit {
+		t.Fatalf("WriteString: expected ErrStringLenExceedLimit, got %v", err)
+	}
+
+	b = NewBuffer(data, WithMaxBytesLength(5))
+	if _, err := b.ReadString(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ReadBytes(); err != buffer.ErrExceedBufferLimit {
+		t.Fatalf("ReadBytes: expected ErrExceedBufferLimit, got %v", err)
+	}
+	if err := b.WriteBytes(make([]byte, 6)); err != buffer.ErrExceedBufferLimit {
+		t.Fatalf("WriteBytes: expected ErrExceedBufferLimit, got %v", err)
+	}
+
+	b = NewBuffer(data, WithMaxDecodedSize(15))
+	if _, err := b.ReadString(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.ReadBytes(); err != buffer.ErrExceedBufferLimit {
+		t.Fatalf("ReadBytes: expected ErrExceedBufferLimit, got %v", err)
+	}
+	b.SetBuf(data)
+	if _, err := b.ReadString(); err != nil {
+		t.Fatalf("decoded size must be reset by SetBuf: %v", err)
+	}
+
+	fb := NewFixedBuffer(len(data), WithMaxStringLength(5), WithMaxDecodedSize(15))
+	_, _ = fb.Write(data)
+	if _, err := fb.ReadString(); err != buffer.ErrStringLenExceedLimit {
+		t.Fatalf("FixedBuffer.ReadString: expected ErrStringLenExceedLimit, got %v", err)
+	}
+	if fb.Readable() != len(data) {
+		t.Fatalf("FixedBuffer.ReadString must not consume data on limit error")
+	}
+	fb = NewFixedBuffer(len(data), WithMaxDecodedSize(15))
+	_, _ = fb.Write(data)
+	if _, err := fb.ReadString(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fb.ReadBytes(); err != buffer.ErrExceedBufferLimit {
+		t.Fatalf("FixedBuffer.ReadBytes: expected ErrExceedBufferLimit, got %v", err)
+	}
+}
+
+func TestIOReader_Limits(t *testing.T) {
+	// 声明 1 GiB 长度, 但实际数据不足
+	src := NewBuffer(nil)
+	_, _ = src.WriteVarint32(1 << 30)
+	_, _ = src.Write([]byte("short"))
+
+	r := NewIOReader(bytes.NewReader(src.Data()), WithMaxStringLength(1<<20))
+	if _, err := r.ReadString(); err != buffer.ErrStringLenExceedLimit {
+		t.Fatalf("ReadString: expected ErrStringLenExceedLimit, got %v", err)
+	}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	r = NewIOReader(bytes.NewReader(src.Data()))
+	if _, err := r.ReadBytes(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("ReadBytes: expected io.ErrUnexpectedEOF, got %v", err)
+	}
+	runtime.ReadMemStats(&after)
+	if n := after.TotalAlloc - before.TotalAlloc; n > 1<<20 {
+		t.Fatalf("ReadBytes allocated %d bytes for truncated data", n)
+	}
+}
+
+func TestDecodeValue_Limits(t *testing.T) {
+	type msg struct {
+		Data []byte
+	}
+	data, _ := Marshal(msg{Data: make([]byte, 10)})
+
+	var got msg
+	if err := NewBuffer(data, WithMaxBytesLength(5)).ReadValue(&got); err != buffer.ErrExceedBufferLimit {
+		t.Fatalf("ReadValue: expected ErrExceedBufferLimit, got %v", err)
+	}
+	if err := DecodeValue(NewIOReader(bytes.NewReader(data), WithMaxBytesLength(5)), &got); err != buffer.ErrExceedBufferLimit {
+		t.Fatalf("DecodeValue: expected ErrExceedBufferLimit, got %v", err)
+	}
+}
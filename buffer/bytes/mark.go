@@ -0,0 +1,87 @@
+package bytes
+
+// Mark 读取位置标记, 由 Buffer.Mark 或 FixedBuffer.Mark 创建
+// 标记存在期间, 缓冲区不会丢弃标记位置之后的已读数据, 因此可通过 Rollback 回退至
+// 标记位置重新读取. 标记按照栈的方式嵌套, 须通过 Commit 或 Rollback 释放.
+type Mark struct {
+	pos     int // 逻辑读取位置
+	depth   int // 在标记栈中的位置
+	decoded int // 已解码数据长度, 用于回退解码限制的计数
+}
+
+// Mark 标记当前读取位置
+// 用于解码可能尚未完整到达的消息: 读取失败(如 io.ErrUnexpectedEOF)时调用
+// Rollback 回退至标记位置, 成功时调用 Commit.
+func (b *Buffer) Mark() Mark {
+	m := Mark{pos: b.shifted + b.off, depth: len(b.marks)}
+	if b.lim != nil {
+		m.decoded = b.lim.decoded
+	}
+	b.marks = append(b.marks, m.pos)
+	return m
+}
+
+// Rollback 将读取位置回退至 m, 同时释放 m 及其之后创建的标记
+// m 已被释放时 panic.
+func (b *Buffer) Rollback(m Mark) {
+	if m.depth >= len(b.marks) || b.marks[m.depth] != m.pos {
+		panic("bytes.Buffer.Rollback: invalid mark")
+	}
+	b.off = m.pos - b.shifted
+	b.marks = b.marks[:m.depth]
+	if b.lim != nil {
+		b.lim.decoded = m.decoded
+	}
+}
+
+// Commit 释放最近创建的标记, 保留标记之后的读取
+// 不存在标记时 panic.
+func (b *Buffer) Commit() {
+	if len(b.marks) == 0 {
+		panic("bytes.Buffer.Commit: no mark")
+	}
+	b.marks = b.marks[:len(b.marks)-1]
+}
+
+// Marks 获取未释放的标记数量
+func (b *Buffer) Marks() int {
+	return len(b.marks)
+}
+
+// Mark 标记当前读取位置, 参见 Buffer.Mark
+// 标记存在期间, 标记位置之后的已读数据仍然占用buf空间, 可写入的空间相应减少.
+func (b *FixedBuffer) Mark() Mark {
+	m := Mark{pos: b.shifted + b.r, depth: len(b.marks)}
+	if b.lim != nil {
+		m.decoded = b.lim.decoded
+	}
+	b.marks = append(b.marks, m.pos)
+	return m
+}
+
+// Rollback 将读取位置回退至 m, 同时释放 m 及其之后创建的标记
+// m 已被释放时 panic.
+func (b *FixedBuffer) Rollback(m Mark) {
+	if m.depth >= len(b.marks) || b.marks[m.depth] != m.pos {
+		panic("bytes.FixedBuffer.Rollback: invalid mark")
+	}
+	b.r = m.pos - b.shifted
+	b.marks = b.marks[:m.depth]
+	if b.lim != nil {
+		b.lim.decoded = m.decoded
+	}
+}
+
+// Commit 释放最近创建的标记, 保留标记之后的读取
+// 不存在标记时 panic.
+func (b *FixedBuffer) Commit() {
+	if len(b.marks) == 0 {
+		panic("bytes.FixedBuffer.Commit: no mark")
+	}
+	b.marks = b.marks[:len(b.marks)-1]
+}
+
+// Marks 获取未释放的标记数量
+func (b *FixedBuffer) Marks() int {
+	return len(b.marks)
+}
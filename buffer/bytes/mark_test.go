@@ -0,0 +1,178 @@
+package bytes
+
+import (
+	"io"
+	"testing"
+
+	"github.com/godyy/gutils/buffer"
+)
+
+// decodeMessage 解码消息, 数据不完整时回退读取位置
+func decodeMessage(b interface {
+	buffer.Reader
+	Mark() Mark
+	Rollback(Mark)
+	Commit()
+}) (id int32, name string, err error) {
+	m := b.Mark()
+	defer func() {
+		if err != nil {
+			b.Rollback(m)
+		} else {
+			b.Commit()
+		}
+	}()
+
+	if id, err = b.ReadBigInt32(); err != nil {
+		return
+	}
+	name, err = b.ReadString()
+	return
+}
+
+func TestBuffer_MarkRollback(t *testing.T) {
+	msg := NewBuffer(nil)
+	_ = msg.WriteBigInt32(7)
+	_ = msg.WriteString("a fairly long name to force buffer growth beyond the initial capacity")
+	data := msg.Data()
+
+	b := NewBufferWithCap(smallBufferSize)
+	// 已读数据, 扩容时可被丢弃
+	_ = b.WriteUint64(1)
+	_, _ = b.ReadUint64()
+
+	_, _ = b.Write(data[:6])
+	if _, _, err := decodeMessage(b); err != io.ErrUnexpectedEOF {
+		t.Fatalf("partial message: expected io.ErrUnexpectedEOF, got %v", err)
+	}
+	if b.Readable() != 6 || b.Marks() != 0 {
+		t.Fatalf("rollback: readable %d, marks %d", b.Readable(), b.Marks())
+	}
+
+	// 标记存在时写入剩余数据, 触发扩容
+	m := b.Mark()
+	if _, err := b.ReadBigInt32(); err != nil {
+		t.Fatal(err)
+	}
+	_, _ = b.Write(data[6:])
+	b.Rollback(m)
+
+	id, name, err := decodeMessage(b)
+	if err != nil || id != 7 || name != "a fairly long name to force buffer growth beyond the initial capacity" {
+		t.Fatalf("decode: %d, %q, %v", id, name, err)
+	}
+	if b.Readable() != 0 {
+		t.Fatalf("decode must consume message, %d bytes left", b.Readable())
+	}
+}
+
+func TestBuffer_MarkNested(t *testing.T) {
+	b := NewBuffer(nil)
+	for i := 0; i < 4; i++ {
+		_ = b.WriteUint8(uint8(i))
+	}
+
+	outer := b.Mark()
+	_, _ = b.ReadUint8()
+	inner := b.Mark()
+	_, _ = b.ReadUint8()
+	b.Rollback(inner)
+	if v, _ := b.ReadUint8(); v != 1 {
+		t.Fatalf("rollback inner: got %d", v)
+	}
+	b.Mark()
+	_, _ = b.ReadUint8()
+	b.Commit()
+	b.Rollback(outer)
+	if b.Marks() != 0 {
+		t.Fatalf("rollback outer must release nested marks, %d left", b.Marks())
+	}
+	if v, _ := b.ReadUint8(); v != 0 {
+		t.Fatalf("rollback outer: got %d", v)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("rollback released mark must panic")
+		}
+	}()
+	b.Rollback(inner)
+}
+
+func TestBuffer_MarkSlide(t *testing.T) {
+	b := NewBufferWithCap(smallBufferSize)
+	for i := 0; i < 8; i++ {
+		_ = b.WriteUint32(uint32(i))
+	}
+	for i := 0; i < 4; i++ {
+		_, _ = b.ReadUint32()
+	}
+	m := b.Mark()
+	for i := 4; i < 8; i++ {
+		_, _ = b.ReadUint32()
+	}
+
+	// 写入少量数据触发滑动, 大量数据触发重新分配
+	_ = b.WriteUint64(8)
+	_, _ = b.Write(make([]byte, 4*smallBufferSize))
+	b.Rollback(m)
+	for i := 4; i < 8; i++ {
+		if v, err := b.ReadUint32(); err != nil || v != uint32(i) {
+			t.Fatalf("read after rollback: %d, %v", v, err)
+		}
+	}
+	if v, _ := b.ReadUint64(); v != 8 {
+		t.Fatalf("read after rollback: %d", v)
+	}
+}
+
+func TestFixedBuffer_MarkRollback(t *testing.T) {
+	b := NewFixedBuffer(8)
+	_ = b.WriteUint32(1)
+	_ = b.WriteUint16(2)
+
+	_, _ = b.ReadUint32()
+	m := b.Mark()
+	_, _ = b.ReadUint16()
+	// 标记之后的已读数据仍然占用空间
+	if b.Writable() != 6 {
+		t.Fatalf("writable with mark: %d", b.Writable())
+	}
+	if err := b.WriteUint64(3); err != buffer.ErrExceedBufferLimit {
+		t.Fatalf("write with mark: expected ErrExceedBufferLimit, got %v", err)
+	}
+	if err := b.WriteUint32(3); err != nil {
+		t.Fatal(err)
+	}
+
+	b.Rollback(m)
+	if v, _ := b.ReadUint16(); v != 2 {
+		t.Fatalf("read after rollback: %d", v)
+	}
+	if v, _ := b.ReadUint32(); v != 3 {
+		t.Fatalf("read after rollback: %d", v)
+	}
+
+	m = b.Mark()
+	if _, _, err := decodeMessage(b); err != io.EOF {
+		t.Fatalf("empty message: expected io.EOF, got %v", err)
+	}
+	b.Commit()
+	if b.Marks() != 0 || b.Writable() != 8 {
+		t.Fatalf("commit: marks %d, writable %d", b.Marks(), b.Writable())
+	}
+}
+
+func TestBuffer_MarkLimits(t *testing.T) {
+	b := NewBuffer(nil, WithMaxDecodedSize(5))
+	_ = b.WriteString("hello")
+
+	m := b.Mark()
+	if _, err := b.ReadString(); err != nil {
+		t.Fatal(err)
+	}
+	b.Rollback(m)
+	if _, err := b.ReadString(); err != nil {
+		t.Fatalf("rollback must restore decoded size: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+//go:build gutils_bufdebug
+
+package bytes
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBuffer_NoCopyPoison(t *testing.T) {
+	b := NewBuffer(nil)
+	_ = b.WriteString("hello")
+	_ = b.WriteString("world")
+
+	s, _ := b.ReadStringNoCopy()
+	peek, _ := b.PeekString()
+	_ = b.WriteUint8(1)
+	if s != strings.Repeat("\xdd", len(s)) {
+		t.Fatalf("released view must be poisoned: %q", s)
+	}
+	if peek != "world" {
+		t.Fatalf("unread view must not be poisoned: %q", peek)
+	}
+	if got, _ := b.ReadString(); got != "world" {
+		t.Fatalf("unread data corrupted: %q", got)
+	}
+
+	_ = b.WriteString("again")
+	p, _ := b.PeekString()
+	b.Reset()
+	if p != strings.Repeat("\xdd", len(p)) {
+		t.Fatalf("Reset must poison views: %q", p)
+	}
+}
+
+func TestFixedBuffer_NoCopyPoison(t *testing.T) {
+	b := NewFixedBuffer(32)
+	_ = b.WriteString("hello")
+	_ = b.WriteString("world")
+
+	s, _ := b.ReadStringNoCopy()
+	_ = b.WriteUint8(1)
+	if s == "hello" {
+		t.Fatalf("released view must be invalidated: %q", s)
+	}
+	if got, _ := b.ReadString(); got != "world" {
+		t.Fatalf("unread data corrupted: %q", got)
+	}
+}
@@ -0,0 +1,87 @@
+package bytes
+
+import (
+	"io"
+	"testing"
+)
+
+func TestBuffer_ReadNoCopy(t *testing.T) {
+	b := NewBuffer(nil)
+	_ = b.WriteString("hello")
+	_ = b.WriteBytes([]byte("world"))
+	_ = b.WriteString("")
+
+	s, err := b.PeekString()
+	if err != nil || s != "hello" {
+		t.Fatalf("PeekString: %q, %v", s, err)
+	}
+	if s, err = b.ReadStringNoCopy(); err != nil || s != "hello" {
+		t.Fatalf("ReadStringNoCopy: %q, %v", s, err)
+	}
+	p, err := b.ReadBytesNoCopy()
+	if err != nil || string(p) != "world" {
+		t.Fatalf("ReadBytesNoCopy: %q, %v", p, err)
+	}
+	if cap(p) != len(p) {
+		t.Fatalf("ReadBytesNoCopy: cap %d must equal len %d", cap(p), len(p))
+	}
+	if s, err = b.ReadStringNoCopy(); err != nil || s != "" {
+		t.Fatalf("ReadStringNoCopy empty: %q, %v", s, err)
+	}
+	if _, err = b.PeekString(); err != io.EOF {
+		t.Fatalf("PeekString at end: expected io.EOF, got %v", err)
+	}
+
+	b.Reset()
+	_ = b.WriteString("hello")
+	allocs := testing.AllocsPerRun(100, func() {
+		b.off = 0
+		if _, err := b.ReadStringNoCopy(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("ReadStringNoCopy allocated %v times", allocs)
+	}
+}
+
+func TestFixedBuffer_ReadNoCopy(t *testing.T) {
+	b := NewFixedBuffer(32)
+	_ = b.WriteString("hello")
+	_ = b.WriteBytes([]byte("world"))
+
+	s, err := b.PeekString()
+	if err != nil || s != "hello" {
+		t.Fatalf("PeekString: %q, %v", s, err)
+	}
+	if s, err = b.ReadStringNoCopy(); err != nil || s != "hello" {
+		t.Fatalf("ReadStringNoCopy: %q, %v", s, err)
+	}
+	p, err := b.ReadBytesNoCopy()
+	if err != nil || string(p) != "world" {
+		t.Fatalf("ReadBytesNoCopy: %q, %v", p, err)
+	}
+	if _, err = b.ReadStringNoCopy(); err != io.EOF {
+		t.Fatalf("ReadStringNoCopy at end: expected io.EOF, got %v", err)
+	}
+}
+
+func BenchmarkBuffer_ReadString(b *testing.B) {
+	buf := NewBuffer(nil)
+	_ = buf.WriteString("a moderately sized string value")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.off = 0
+		_, _ = buf.ReadString()
+	}
+}
+
+func BenchmarkBuffer_ReadStringNoCopy(b *testing.B) {
+	buf := NewBuffer(nil)
+	_ = buf.WriteString("a moderately sized string value")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.off = 0
+		_, _ = buf.ReadStringNoCopy()
+	}
+}
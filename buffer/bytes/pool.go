@@ -0,0 +1,211 @@
+package bytes
+
+import (
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultMaxRetained Pool 默认缓存的最大缓冲区容量
+const defaultMaxRetained = 64 << 10
+
+// minPoolShift 最小分级容量的位数, 对应 smallBufferSize
+var minPoolShift = bits.Len(uint(smallBufferSize)) - 1
+
+// poolConfig 用于配置 Pool
+type poolConfig struct {
+	maxRetained int
+	stats       bool
+}
+
+// PoolOption 用于配置 Pool
+type PoolOption interface {
+	apply(*poolConfig)
+}
+
+type maxRetainedOption int
+
+func (o maxRetainedOption) apply(c *poolConfig) {
+	c.maxRetained = int(o)
+}
+
+// WithMaxRetained 配置 Pool 缓存的最大缓冲区容量, 向上取整为2的幂.
+// 容量超过该值的缓冲区在 Put 时直接丢弃, 避免个别大消息长期占用内存.
+func WithMaxRetained(n int) PoolOption {
+	return maxRetainedOption(n)
+}
+
+type statsOption struct{}
+
+func (statsOption) apply(c *poolConfig) {
+	c.stats = true
+}
+
+// WithStats 开启 Pool 的统计, 统计数据可通过 Pool.Stats 获取.
+var WithStats PoolOption = statsOption{}
+
+// PoolStats Pool 统计数据
+type PoolStats struct {
+	Hits     int64 // 自缓存中获取成功的次数
+	Misses   int64 // 需要新分配缓冲区的次数
+	Retained int64 // 归还至缓存且尚未被取出的缓冲区容量之和, 不感知 GC 的回收, 仅供参考
+}
+
+// Pool 按2的幂划分容量等级, 基于 sync.Pool 缓存 Buffer 与 FixedBuffer
+type Pool struct {
+	maxShift     int
+	buffers      []sync.Pool // 按容量等级缓存的 *Buffer
+	fixedBuffers []sync.Pool // 按容量等级缓存的 *FixedBuffer
+	stats        bool
+	hits         atomic.Int64
+	misses       atomic.Int64
+	retained     atomic.Int64
+}
+
+// NewPool 创建 Pool
+func NewPool(opts ...PoolOption) *Pool {
+	c := poolConfig{
+		maxRetained: defaultMaxRetained,
+	}
+	for _, opt := range opts {
+		opt.apply(&c)
+	}
+	if c.maxRetained < smallBufferSize {
+		c.maxRetained = smallBufferSize
+	}
+
+	maxShift := bits.Len(uint(c.maxRetained - 1))
+	classes := maxShift - minPoolShift + 1
+	return &Pool{
+		maxShift:     maxShift,
+		buffers:      make([]sync.Pool, classes),
+		fixedBuffers: make([]sync.Pool, classes),
+		stats:        c.stats,
+	}
+}
+
+// ceilClass 返回容量不小于 n 的最小等级, ok 为 false 表示超出缓存范围
+func (p *Pool) ceilClass(n int) (class int, ok bool) {
+	if n <= smallBufferSize {
+		return 0, true
+	}
+	shift := bits.Len(uint(n - 1))
+	if shift > p.maxShift {
+		return 0, false
+	}
+	return shift - minPoolShift, true
+}
+
+// floorClass 返回容量不大于 c 的最大等级, ok 为 false 表示超出缓存范围
+func (p *Pool) floorClass(c int) (class int, ok bool) {
+	if c < smallBufferSize {
+		return 0, false
+	}
+	shift := bits.Len(uint(c)) - 1
+	if shift > p.maxShift {
+		return 0, false
+	}
+	return shift - minPoolShift, true
+}
+
+// Get 获取容量不小于 minCap 的 Buffer
+func (p *Pool) Get(minCap int) *Buffer {
+	class, ok := p.ceilClass(minCap)
+	if !ok {
+		p.miss()
+		return NewBufferWithCap(minCap)
+	}
+
+	if v := p.buffers[class].Get(); v != nil {
+		b := v.(*Buffer)
+		p.hit(b.Cap())
+		return b
+	}
+
+	p.miss()
+	return NewBufferWithCap(smallBufferSize << class)
+}
+
+// Put 将 Buffer 归还至 Pool, 归还后不可再使用 b
+func (p *Pool) Put(b *Buffer) {
+	if b == nil {
+		return
+	}
+	c := b.Cap()
+	class, ok := p.floorClass(c)
+	if !ok {
+		return
+	}
+
+	b.Reset()
+	if p.stats {
+		p.retained.Add(int64(c))
+	}
+	p.buffers[class].Put(b)
+}
+
+// GetFixed 获取大小为 size 的 FixedBuffer
+func (p *Pool) GetFixed(size int) *FixedBuffer {
+	if size <= 0 {
+		panic("bytes.Pool.GetFixed: size <= 0")
+	}
+
+	class, ok := p.ceilClass(size)
+	if !ok {
+		p.miss()
+		return NewFixedBuffer(size)
+	}
+
+	if v := p.fixedBuffers[class].Get(); v != nil {
+		b := v.(*FixedBuffer)
+		p.hit(cap(b.buf))
+		b.buf = b.buf[:size]
+		b.Reset()
+		return b
+	}
+
+	p.miss()
+	b := NewFixedBuffer(smallBufferSize << class)
+	b.buf = b.buf[:size]
+	return b
+}
+
+// PutFixed 将 FixedBuffer 归还至 Pool, 归还后不可再使用 b
+func (p *Pool) PutFixed(b *FixedBuffer) {
+	if b == nil {
+		return
+	}
+	c := cap(b.buf)
+	class, ok := p.floorClass(c)
+	if !ok {
+		return
+	}
+
+	b.Reset()
+	if p.stats {
+		p.retained.Add(int64(c))
+	}
+	p.fixedBuffers[class].Put(b)
+}
+
+// Stats 获取统计数据, 需通过 WithStats 开启
+func (p *Pool) Stats() PoolStats {
+	return PoolStats{
+		Hits:     p.hits.Load(),
+		Misses:   p.misses.Load(),
+		Retained: p.retained.Load(),
+	}
+}
+
+func (p *Pool) hit(c int) {
+	if p.stats {
+		p.hits.Add(1)
+		p.retained.Add(-int64(c))
+	}
+}
+
+func (p *Pool) miss() {
+	if p.stats {
+		p.misses.Add(1)
+	}
+}
@@ -0,0 +1,80 @@
+package bytes
+
+import "testing"
+
+func TestPool(t *testing.T) {
+	p := NewPool(WithMaxRetained(1024), WithStats)
+
+	b := p.Get(100)
+	if b.Cap() < 100 {
+		t.Fatalf("expected cap >= 100, got %d", b.Cap())
+	}
+	_ = b.WriteString("pooled")
+	p.Put(b)
+
+	b2 := p.Get(128)
+	if b2.Readable() != 0 {
+		t.Fatalf("expected reset buffer, got %d readable bytes", b2.Readable())
+	}
+	if b2.Cap() < 128 {
+		t.Fatalf("expected cap >= 128, got %d", b2.Cap())
+	}
+	p.Put(b2)
+
+	// 超出缓存上限的缓冲区不会被缓存
+	big := p.Get(4096)
+	if big.Cap() < 4096 {
+		t.Fatalf("expected cap >= 4096, got %d", big.Cap())
+	}
+	p.Put(big)
+
+	fb := p.GetFixed(200)
+	if fb.Size() != 200 {
+		t.Fatalf("expected fixed size 200, got %d", fb.Size())
+	}
+	_ = fb.WriteUint32(1)
+	p.PutFixed(fb)
+	fb = p.GetFixed(150)
+	if fb.Size() != 150 || fb.Readable() != 0 {
+		t.Fatalf("expected empty fixed buffer of size 150, got size %d readable %d", fb.Size(), fb.Readable())
+	}
+
+	stats := p.Stats()
+	if stats.Hits+stats.Misses != 5 {
+		t.Fatalf("expected 5 gets, got %+v", stats)
+	}
+	if stats.Misses < 2 {
+		t.Fatalf("expected at least 2 misses, got %+v", stats)
+	}
+	t.Logf("stats: %+v", stats)
+}
+
+func TestPool_Classes(t *testing.T) {
+	p := NewPool()
+	for _, tc := range []struct{ n, class int }{
+		{1, 0}, {64, 0}, {65, 1}, {128, 1}, {129, 2}, {defaultMaxRetained, 10},
+	} {
+		if class, ok := p.ceilClass(tc.n); !ok || class != tc.class {
+			t.Errorf("ceilClass(%d) = %d, %v; expected %d", tc.n, class, ok, tc.class)
+		}
+	}
+	if _, ok := p.ceilClass(defaultMaxRetained + 1); ok {
+		t.Errorf("ceilClass(%d) expected out of range", defaultMaxRetained+1)
+	}
+	if class, ok := p.floorClass(127); !ok || class != 0 {
+		t.Errorf("floorClass(127) = %d, %v; expected 0", class, ok)
+	}
+	if _, ok := p.floorClass(63); ok {
+		t.Errorf("floorClass(63) expected out of range")
+	}
+}
+
+func BenchmarkPool_GetPut(b *testing.B) {
+	p := NewPool()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf := p.Get(512)
+		_ = buf.WriteString("benchmark")
+		p.Put(buf)
+	}
+}
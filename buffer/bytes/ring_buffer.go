@@ -0,0 +1,508 @@
+package bytes
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/godyy/gutils/buffer"
+	pkg_errors "github.com/pkg/errors"
+)
+
+// RingBuffer 定长环形字节缓冲区
+// 与 FixedBuffer 提供相同的读写方法, 但写入时环绕至缓冲区头部, 而不是将未读数据
+// 滑动至最前端, 因此单次写入的开销与未读数据量无关.
+type RingBuffer struct {
+	buf []byte
+	r   int       // 读取位置
+	n   int       // 可读数据长度
+	vec [2][]byte // WriteTo 使用的向量缓存, 避免分配
+}
+
+var _ buffer.ReadWriter = (*RingBuffer)(nil)
+
+// NewRingBuffer 使用指定size创建RingBuffer
+func NewRingBuffer(size int) *RingBuffer {
+	if size <= 0 {
+		panic("bytes.NewRingBuffer: size <= 0")
+	}
+	return &RingBuffer{
+		buf: make([]byte, size),
+	}
+}
+
+// Size 获取buf的大小
+func (b *RingBuffer) Size() int {
+	return len(b.buf)
+}
+
+// Reset 重置读写状态
+func (b *RingBuffer) Reset() {
+	b.r = 0
+	b.n = 0
+}
+
+// Readable 获取可读取数据长度
+func (b *RingBuffer) Readable() int {
+	return b.n
+}
+
+// Writable 获取可写入数据长度
+func (b *RingBuffer) Writable() int {
+	return len(b.buf) - b.n
+}
+
+// Segments 获取未读数据所在的两段连续内存, 可用于 writev 等向量化 I/O
+// 未读数据未环绕时 tail 为 nil. 返回的切片在下一次修改缓冲区前有效.
+func (b *RingBuffer) Segments() (head, tail []byte) {
+	if b.n == 0 {
+		return nil, nil
+	}
+	end := b.r + b.n
+	if end <= len(b.buf) {
+		return b.buf[b.r:end], nil
+	}
+	return b.buf[b.r:], b.buf[:end-len(b.buf)]
+}
+
+// WritableSegments 获取可写入空间所在的两段连续内存
+// 直接写入后需调用 CommitWrite 提交写入的字节数.
+func (b *RingBuffer) WritableSegments() (head, tail []byte) {
+	free := len(b.buf) - b.n
+	if free == 0 {
+		return nil, nil
+	}
+	w := b.r + b.n
+	if w >= len(b.buf) {
+		w -= len(b.buf)
+	}
+	if w+free <= len(b.buf) {
+		return b.buf[w : w+free], nil
+	}
+	return b.buf[w:], b.buf[:free-(len(b.buf)-w)]
+}
+
+// CommitWrite 提交通过 WritableSegments 直接写入的 n 个字节
+func (b *RingBuffer) CommitWrite(n int) {
+	if n < 0 || n > b.Writable() {
+		panic("bytes.RingBuffer.CommitWrite: invalid n")
+	}
+	b.n += n
+}
+
+// peek 复制 len(p) 字节的未读数据至 p, 不更新读取位置
+func (b *RingBuffer) peek(p []byte) int {
+	head, tail := b.Segments()
+	n := copy(p, head)
+	n += copy(p[n:], tail)
+	return n
+}
+
+// discard 丢弃 n 字节的未读数据
+func (b *RingBuffer) discard(n int) {
+	b.n -= n
+	if b.n == 0 {
+		b.r = 0
+		return
+	}
+	b.r += n
+	if b.r >= len(b.buf) {
+		b.r -= len(b.buf)
+	}
+}
+
+// ringPut 将 p 写入可写空间, 返回写入的字节数
+func ringPut[T ~[]byte | ~string](b *RingBuffer, p T) int {
+	head, tail := b.WritableSegments()
+	n := copy(head, p)
+	n += copy(tail, p[n:])
+	b.n += n
+	return n
+}
+
+func (b *RingBuffer) ReadByte() (c byte, err error) {
+	if b.n == 0 {
+		return 0, io.EOF
+	}
+
+	c = b.buf[b.r]
+	b.discard(1)
+	return
+}
+
+func (b *RingBuffer) WriteByte(c byte) error {
+	if b.Writable() < 1 {
+		return buffer.ErrBufferFull
+	}
+
+	w := b.r + b.n
+	if w >= len(b.buf) {
+		w -= len(b.buf)
+	}
+	b.buf[w] = c
+	b.n++
+	return nil
+}
+
+func (b *RingBuffer) ReadInt8() (int8, error) {
+	n, err := b.ReadUint8()
+	return int8(n), err
+}
+
+func (b *RingBuffer) WriteInt8(i int8) error {
+	return b.WriteUint8(uint8(i))
+}
+
+func (b *RingBuffer) ReadUint8() (i uint8, err error) {
+	return b.ReadByte()
+}
+
+func (b *RingBuffer) WriteUint8(i uint8) error {
+	return b.WriteByte(i)
+}
+
+func (b *RingBuffer) ReadInt16() (int16, error) {
+	return b.readInt16(nativeEndian)
+}
+
+func (b *RingBuffer) WriteInt16(i int16) error {
+	return b.writeInt16(i, nativeEndian)
+}
+
+func (b *RingBuffer) ReadUint16() (uint16, error) {
+	return b.readUint16(nativeEndian)
+}
+
+func (b *RingBuffer) WriteUint16(i uint16) error {
+	return b.writeUint16(i, nativeEndian)
+}
+
+func (b *RingBuffer) ReadInt32() (int32, error) {
+	return b.readInt32(nativeEndian)
+}
+
+func (b *RingBuffer) WriteInt32(i int32) error {
+	return b.writeInt32(i, nativeEndian)
+}
+
+func (b *RingBuffer) ReadUint32() (uint32, error) {
+	return b.readUint32(nativeEndian)
+}
+
+func (b *RingBuffer) WriteUint32(i uint32) error {
+	return b.writeUint32(i, nativeEndian)
+}
+
+func (b *RingBuffer) ReadInt64() (int64, error) {
+	return b.readInt64(nativeEndian)
+}
+
+func (b *RingBuffer) WriteInt64(i int64) error {
+	return b.writeInt64(i, nativeEndian)
+}
+
+func (b *RingBuffer) ReadUint64() (uint64, error) {
+	return b.readUint64(nativeEndian)
+}
+
+func (b *RingBuffer) WriteUint64(i uint64) error {
+	return b.writeUint64(i, nativeEndian)
+}
+
+func (b *RingBuffer) ReadFloat32() (float32, error) {
+	return b.readFloat32(nativeEndian)
+}
+
+func (b *RingBuffer) WriteFloat32(f float32) error {
+	return b.writeFloat32(f, nativeEndian)
+}
+
+func (b *RingBuffer) ReadFloat64() (float64, error) {
+	return b.readFloat64(nativeEndian)
+}
+
+func (b *RingBuffer) WriteFloat64(f float64) error {
+	return b.writeFloat64(f, nativeEndian)
+}
+
+func (b *RingBuffer) ReadBool() (bool, error) {
+	c, err := b.ReadByte()
+	if err != nil {
+		return false, err
+	}
+	return c == 1, nil
+}
+
+func (b *RingBuffer) WriteBool(v bool) error {
+	if v {
+		return b.WriteByte(1)
+	} else {
+		return b.WriteByte(0)
+	}
+}
+
+// peekVarint 解析未读数据中的 varint, 不更新读取位置
+func (b *RingBuffer) peekVarint() (int64, int) {
+	var buf [MaxVarintLen64]byte
+	return binary.Varint(buf[:b.peek(buf[:])])
+}
+
+// peekUvarint 解析未读数据中的 uvarint, 不更新读取位置
+func (b *RingBuffer) peekUvarint() (uint64, int) {
+	var buf [MaxVarintLen64]byte
+	return binary.Uvarint(buf[:b.peek(buf[:])])
+}
+
+// writeVarintBytes 写入已编码的 varint
+func (b *RingBuffer) writeVarintBytes(p []byte) (int, error) {
+	if b.Writable() < len(p) {
+		return 0, buffer.ErrExceedBufferLimit
+	}
+	return ringPut(b, p), nil
+}
+
+func (b *RingBuffer) ReadVarint16() (int16, error) {
+	i, n := b.peekVarint()
+	if n == 0 {
+		return 0, io.EOF
+	}
+	if n < 0 || n > MaxVarintLen16 {
+		return 0, ErrVarintOverflow
+	}
+	b.discard(n)
+	return int16(i), nil
+}
+
+func (b *RingBuffer) WriteVarint16(i int16) (int, error) {
+	var buf [MaxVarintLen16]byte
+	n := binary.PutVarint(buf[:], int64(i))
+	return b.writeVarintBytes(buf[:n])
+}
+
+func (b *RingBuffer) ReadUvarint16() (uint16, error) {
+	i, n := b.peekUvarint()
+	if n == 0 {
+		return 0, io.EOF
+	}
+	if n < 0 || n > MaxVarintLen16 {
+		return 0, ErrVarintOverflow
+	}
+	b.discard(n)
+	return uint16(i), nil
+}
+
+func (b *RingBuffer) WriteUvarint16(i uint16) (int, error) {
+	var buf [MaxVarintLen16]byte
+	n := binary.PutUvarint(buf[:], uint64(i))
+	return b.writeVarintBytes(buf[:n])
+}
+
+func (b *RingBuffer) ReadVarint32() (int32, error) {
+	i, n := b.peekVarint()
+	if n == 0 {
+		return 0, io.EOF
+	}
+	if n < 0 || n > MaxVarintLen32 {
+		return 0, ErrVarintOverflow
+	}
+	b.discard(n)
+	return int32(i), nil
+}
+
+func (b *RingBuffer) WriteVarint32(i int32) (int, error) {
+	var buf [MaxVarintLen32]byte
+	n := binary.PutVarint(buf[:], int64(i))
+	return b.writeVarintBytes(buf[:n])
+}
+
+func (b *RingBuffer) ReadUvarint32() (uint32, error) {
+	i, n := b.peekUvarint()
+	if n == 0 {
+		return 0, io.EOF
+	}
+	if n < 0 || n > MaxVarintLen32 {
+		return 0, ErrVarintOverflow
+	}
+	b.discard(n)
+	return uint32(i), nil
+}
+
+func (b *RingBuffer) WriteUvarint32(i uint32) (int, error) {
+	var buf [MaxVarintLen32]byte
+	n := binary.PutUvarint(buf[:], uint64(i))
+	return b.writeVarintBytes(buf[:n])
+}
+
+func (b *RingBuffer) ReadVarint64() (int64, error) {
+	i, n := b.peekVarint()
+	if n == 0 {
+		return 0, io.EOF
+	}
+	if n < 0 {
+		return 0, ErrVarintOverflow
+	}
+	b.discard(n)
+	return i, nil
+}
+
+func (b *RingBuffer) WriteVarint64(i int64) (int, error) {
+	var buf [MaxVarintLen64]byte
+	n := binary.PutVarint(buf[:], i)
+	return b.writeVarintBytes(buf[:n])
+}
+
+func (b *RingBuffer) ReadUvarint64() (uint64, error) {
+	i, n := b.peekUvarint()
+	if n == 0 {
+		return 0, io.EOF
+	}
+	if n < 0 {
+		return 0, ErrVarintOverflow
+	}
+	b.discard(n)
+	return i, nil
+}
+
+func (b *RingBuffer) WriteUvarint64(i uint64) (int, error) {
+	var buf [MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], i)
+	return b.writeVarintBytes(buf[:n])
+}
+
+func (b *RingBuffer) Read(p []byte) (int, error) {
+	if len(p) <= 0 {
+		return 0, io.ErrShortBuffer
+	}
+
+	if b.Readable() == 0 {
+		return 0, io.EOF
+	}
+
+	n := b.peek(p)
+	b.discard(n)
+	return n, nil
+}
+
+func (b *RingBuffer) Write(p []byte) (n int, err error) {
+	if len(p) <= 0 {
+		return 0, nil
+	}
+
+	if b.Writable() == 0 {
+		return 0, buffer.ErrBufferFull
+	}
+
+	return ringPut(b, p), nil
+}
+
+func (b *RingBuffer) ReadString() (string, error) {
+	i, n := b.peekVarint()
+	if n == 0 {
+		return "", io.EOF
+	}
+	if n < 0 || n > MaxStringLenLen {
+		return "", pkg_errors.WithMessage(ErrVarintOverflow, "read length")
+	}
+
+	l := int(i)
+	if l < 0 {
+		return "", buffer.ErrStringLenExceedLimit
+	}
+
+	if l+n > b.Readable() {
+		return "", io.ErrUnexpectedEOF
+	}
+
+	b.discard(n)
+	if l == 0 {
+		return "", nil
+	}
+
+	var s string
+	if head, tail := b.Segments(); len(head) >= l {
+		s = string(head[:l])
+	} else {
+		var sb strings.Builder
+		sb.Grow(l)
+		sb.Write(head)
+		sb.Write(tail[:l-len(head)])
+		s = sb.String()
+	}
+	b.discard(l)
+	return s, nil
+}
+
+func (b *RingBuffer) WriteString(s string) error {
+	l := len(s)
+	if l > MaxStringLength {
+		return buffer.ErrStringLenExceedLimit
+	}
+
+	var buf [MaxStringLenLen]byte
+	ll := binary.PutVarint(buf[:], int64(l))
+
+	if l+ll > b.Writable() {
+		return buffer.ErrExceedBufferLimit
+	}
+
+	ringPut(b, buf[:ll])
+	ringPut(b, s)
+	return nil
+}
+
+func (b *RingBuffer) ReadFrom(r io.Reader) (int64, error) {
+	if b.Writable() == 0 {
+		return 0, buffer.ErrBufferFull
+	}
+
+	head, _ := b.WritableSegments()
+	n, err := r.Read(head)
+	if n < 0 {
+		panic("bytes.RingBuffer.ReadFrom: reader returned negative count from Read")
+	}
+
+	b.n += n
+	return int64(n), err
+}
+
+// WriteTo 将未读数据写入 w
+// 未读数据环绕时, 通过 net.Buffers 以一次向量化写入(若 w 支持)写出两段数据.
+func (b *RingBuffer) WriteTo(w io.Writer) (int64, error) {
+	l := b.Readable()
+	if l == 0 {
+		return 0, nil
+	}
+
+	head, tail := b.Segments()
+	b.vec[0], b.vec[1] = head, tail
+	vec := net.Buffers(b.vec[:1])
+	if tail != nil {
+		vec = b.vec[:2]
+	}
+	n, err := vec.WriteTo(w)
+	b.vec[0], b.vec[1] = nil, nil
+	if n > int64(l) {
+		panic("bytes.RingBuffer.WriteTo: invalid Write count")
+	}
+
+	b.discard(int(n))
+	return n, err
+}
+
+// Skip 自buf中跳过n个字节
+func (b *RingBuffer) Skip(n int) (skipped int, err error) {
+	if n > len(b.buf) {
+		return 0, buffer.ErrExceedBufferLimit
+	}
+
+	if l := b.Readable(); n > l {
+		skipped = l
+		err = io.ErrUnexpectedEOF
+	} else {
+		skipped = n
+	}
+
+	b.discard(skipped)
+	return
+}
@@ -0,0 +1,277 @@
+package bytes
+
+import (
+	"io"
+	"math"
+
+	"github.com/godyy/gutils/buffer"
+)
+
+func (b *RingBuffer) readInt16(bo byteOrder) (int16, error) {
+	n, err := b.readUint16(bo)
+	return int16(n), err
+}
+
+func (b *RingBuffer) writeInt16(i int16, bo byteOrder) error {
+	return b.writeUint16(uint16(i), bo)
+}
+
+func (b *RingBuffer) readUint16(bo byteOrder) (i uint16, err error) {
+	l := b.Readable()
+	if l == 0 {
+		return 0, io.EOF
+	}
+	if l < 2 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	var buf [2]byte
+	b.peek(buf[:])
+	b.discard(2)
+	return bo.Uint16(buf[:]), nil
+}
+
+func (b *RingBuffer) writeUint16(i uint16, bo byteOrder) error {
+	l := b.Writable()
+	if l == 0 {
+		return buffer.ErrBufferFull
+	}
+	if l < 2 {
+		return buffer.ErrExceedBufferLimit
+	}
+
+	var buf [2]byte
+	bo.PutUint16(buf[:], i)
+	ringPut(b, buf[:])
+	return nil
+}
+
+func (b *RingBuffer) readInt32(bo byteOrder) (int32, error) {
+	n, err := b.readUint32(bo)
+	return int32(n), err
+}
+
+func (b *RingBuffer) writeInt32(i int32, bo byteOrder) error {
+	return b.writeUint32(uint32(i), bo)
+}
+
+func (b *RingBuffer) readUint32(bo byteOrder) (i uint32, err error) {
+	l := b.Readable()
+	if l == 0 {
+		return 0, io.EOF
+	}
+	if l < 4 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	var buf [4]byte
+	b.peek(buf[:])
+	b.discard(4)
+	return bo.Uint32(buf[:]), nil
+}
+
+func (b *RingBuffer) writeUint32(i uint32, bo byteOrder) error {
+	l := b.Writable()
+	if l == 0 {
+		return buffer.ErrBufferFull
+	}
+	if l < 4 {
+		return buffer.ErrExceedBufferLimit
+	}
+
+	var buf [4]byte
+	bo.PutUint32(buf[:], i)
+	ringPut(b, buf[:])
+	return nil
+}
+
+func (b *RingBuffer) readInt64(bo byteOrder) (int64, error) {
+	n, err := b.readUint64(bo)
+	return int64(n), err
+}
+
+func (b *RingBuffer) writeInt64(i int64, bo byteOrder) error {
+	return b.writeUint64(uint64(i), bo)
+}
+
+func (b *RingBuffer) readUint64(bo byteOrder) (i uint64, err error) {
+	l := b.Readable()
+	if l == 0 {
+		return 0, io.EOF
+	}
+	if l < 8 {
+		return 0, io.ErrUnexpectedEOF
+	}
+
+	var buf [8]byte
+	b.peek(buf[:])
+	b.discard(8)
+	return bo.Uint64(buf[:]), nil
+}
+
+func (b *RingBuffer) writeUint64(i uint64, bo byteOrder) error {
+	l := b.Writable()
+	if l == 0 {
+		return buffer.ErrBufferFull
+	}
+	if l < 8 {
+		return buffer.ErrExceedBufferLimit
+	}
+
+	var buf [8]byte
+	bo.PutUint64(buf[:], i)
+	ringPut(b, buf[:])
+	return nil
+}
+
+func (b *RingBuffer) writeFloat32(f float32, bo byteOrder) error {
+	return b.writeUint32(math.Float32bits(f), bo)
+}
+
+func (b *RingBuffer) readFloat32(bo byteOrder) (float32, error) {
+	i, err := b.readUint32(bo)
+	if err != nil {
+		return 0, err
+	}
+	return math.Float32frombits(i), nil
+}
+
+func (b *RingBuffer) writeFloat64(f float64, bo byteOrder) error {
+	return b.writeUint64(math.Float64bits(f), bo)
+}
+
+func (b *RingBuffer) readFloat64(bo byteOrder) (float64, error) {
+	i, err := b.readUint64(bo)
+	if err != nil {
+		return 0, err
+	}
+	return math.Float64frombits(i), nil
+}
+
+func (b *RingBuffer) ReadBigInt16() (int16, error) {
+	return b.readInt16(bigEndian)
+}
+
+func (b *RingBuffer) WriteBigInt16(i int16) error {
+	return b.writeInt16(i, bigEndian)
+}
+
+func (b *RingBuffer) ReadBigUint16() (i uint16, err error) {
+	return b.readUint16(bigEndian)
+}
+
+func (b *RingBuffer) WriteBigUint16(i uint16) error {
+	return b.writeUint16(i, bigEndian)
+}
+
+func (b *RingBuffer) ReadBigInt32() (int32, error) {
+	return b.readInt32(bigEndian)
+}
+
+func (b *RingBuffer) WriteBigInt32(i int32) error {
+	return b.writeInt32(i, bigEndian)
+}
+
+func (b *RingBuffer) ReadBigUint32() (i uint32, err error) {
+	return b.readUint32(bigEndian)
+}
+
+func (b *RingBuffer) WriteBigUint32(i uint32) error {
+	return b.writeUint32(i, bigEndian)
+}
+
+func (b *RingBuffer) ReadBigInt64() (int64, error) {
+	return b.readInt64(bigEndian)
+}
+
+func (b *RingBuffer) WriteBigInt64(i int64) error {
+	return b.writeInt64(i, bigEndian)
+}
+
+func (b *RingBuffer) ReadBigUint64() (i uint64, err error) {
+	return b.readUint64(bigEndian)
+}
+
+func (b *RingBuffer) WriteBigUint64(i uint64) error {
+	return b.writeUint64(i, bigEndian)
+}
+
+func (b *RingBuffer) WriteBigFloat32(f float32) error {
+	return b.writeFloat32(f, bigEndian)
+}
+
+func (b *RingBuffer) ReadBigFloat32() (f float32, err error) {
+	return b.readFloat32(bigEndian)
+}
+
+func (b *RingBuffer) WriteBigFloat64(f float64) error {
+	return b.writeFloat64(f, bigEndian)
+}
+
+func (b *RingBuffer) ReadBigFloat64() (f float64, err error) {
+	return b.readFloat64(bigEndian)
+}
+
+func (b *RingBuffer) ReadLitInt16() (int16, error) {
+	return b.readInt16(littleEndian)
+}
+
+func (b *RingBuffer) WriteLitInt16(i int16) error {
+	return b.writeInt16(i, littleEndian)
+}
+
+func (b *RingBuffer) ReadLitUint16() (i uint16, err error) {
+	return b.readUint16(littleEndian)
+}
+
+func (b *RingBuffer) WriteLitUint16(i uint16) error {
+	return b.writeUint16(i, littleEndian)
+}
+
+func (b *RingBuffer) ReadLitInt32() (int32, error) {
+	return b.readInt32(littleEndian)
+}
+
+func (b *RingBuffer) WriteLitInt32(i int32) error {
+	return b.writeInt32(i, littleEndian)
+}
+
+func (b *RingBuffer) ReadLitUint32() (i uint32, err error) {
+	return b.readUint32(littleEndian)
+}
+
+func (b *RingBuffer) WriteLitUint32(i uint32) error {
+	return b.writeUint32(i, littleEndian)
+}
+
+func (b *RingBuffer) ReadLitInt64() (int64, error) {
+	return b.readInt64(littleEndian)
+}
+
+func (b *RingBuffer) WriteLitInt64(i int64) error {
+	return b.writeInt64(i, littleEndian)
+}
+
+func (b *RingBuffer) ReadLitUint64() (i uint64, err error) {
+	return b.readUint64(littleEndian)
+}
+
+func (b *RingBuffer) WriteLitUint64(i uint64) error {
+	return b.writeUint64(i, littleEndian)
+}
+
+func (b *RingBuffer) WriteLitFloat32(f float32) error {
+	return b.writeFloat32(f, littleEndian)
+}
+
+func (b *RingBuffer) ReadLitFloat32() (f float32, err error) {
+	return b.readFloat32(littleEndian)
+}
+
+func (b *RingBuffer) WriteLitFloat64(f float64) error {
+	return b.writeFloat64(f, littleEndian)
+}
+
+func (b *RingBuffer) ReadLitFloat64() (f float64, err error) {
+	return b.readFloat64(littleEndian)
+}
@@ -0,0 +1,122 @@
+package bytes
+
+import (
+	"bytes"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/godyy/gutils/buffer"
+)
+
+func TestRingBuffer(t *testing.T) {
+	b := NewRingBuffer(16)
+
+	// 推进读写位置, 使后续写入环绕
+	if _, err := b.Write(make([]byte, 13)); err != nil {
+		t.Fatalf("write padding: %v", err)
+	}
+	if n, err := b.Skip(12); err != nil || n != 12 {
+		t.Fatalf("skip padding: %d, %v", n, err)
+	}
+
+	if err := b.WriteBigUint32(0xdeadbeef); err != nil {
+		t.Fatalf("write big uint32: %v", err)
+	}
+	if head, tail := b.Segments(); len(head) != 4 || len(tail) != 1 {
+		t.Fatalf("expected wrapped segments 4+1, got %d+%d", len(head), len(tail))
+	}
+	if _, err := b.WriteVarint32(math.MinInt32); err != nil {
+		t.Fatalf("write varint32: %v", err)
+	}
+	if err := b.WriteString("abcde"); err != nil {
+		t.Fatalf("write string: %v", err)
+	}
+	if err := b.WriteByte(1); err != buffer.ErrBufferFull {
+		t.Fatalf("write byte to full buffer: expected ErrBufferFull, got %v", err)
+	}
+
+	if _, err := b.ReadByte(); err != nil {
+		t.Fatalf("read padding: %v", err)
+	}
+	if i, err := b.ReadBigUint32(); err != nil || i != 0xdeadbeef {
+		t.Fatalf("read big uint32: %x, %v", i, err)
+	}
+	if i, err := b.ReadVarint32(); err != nil || i != math.MinInt32 {
+		t.Fatalf("read varint32: %d, %v", i, err)
+	}
+	if s, err := b.ReadString(); err != nil || s != "abcde" {
+		t.Fatalf("read string: %q, %v", s, err)
+	}
+	if _, err := b.ReadByte(); err != io.EOF {
+		t.Fatalf("read empty buffer: expected io.EOF, got %v", err)
+	}
+}
+
+func TestRingBuffer_WrappedString(t *testing.T) {
+	b := NewRingBuffer(32)
+	s := strings.Repeat("ring", 5)
+	for i := 0; i < 10; i++ {
+		if err := b.WriteString(s); err != nil {
+			t.Fatalf("round %d: write string: %v", i, err)
+		}
+		if err := b.WriteLitFloat64(float64(i)); err != nil {
+			t.Fatalf("round %d: write float64: %v", i, err)
+		}
+		if ss, err := b.ReadString(); err != nil || ss != s {
+			t.Fatalf("round %d: read string: %q, %v", i, ss, err)
+		}
+		if f, err := b.ReadLitFloat64(); err != nil || f != float64(i) {
+			t.Fatalf("round %d: read float64: %v, %v", i, f, err)
+		}
+	}
+}
+
+func TestRingBuffer_IO(t *testing.T) {
+	b := NewRingBuffer(8)
+	_, _ = b.Write([]byte("xxxxxx"))
+	_, _ = b.Skip(5)
+
+	src := bytes.NewReader([]byte("0123456789"))
+	if n, err := b.ReadFrom(src); err != nil || n != 2 {
+		t.Fatalf("read from tail segment: %d, %v", n, err)
+	}
+	if n, err := b.ReadFrom(src); err != nil || n != 5 {
+		t.Fatalf("read from head segment: %d, %v", n, err)
+	}
+	if _, err := b.ReadFrom(src); err != buffer.ErrBufferFull {
+		t.Fatalf("read from into full buffer: expected ErrBufferFull, got %v", err)
+	}
+
+	var dst bytes.Buffer
+	if n, err := b.WriteTo(&dst); err != nil || n != 8 {
+		t.Fatalf("write to: %d, %v", n, err)
+	}
+	if dst.String() != "x0123456" {
+		t.Fatalf("write to: unexpected data %q", dst.String())
+	}
+	if b.Readable() != 0 {
+		t.Fatalf("expected empty buffer after write to, got %d", b.Readable())
+	}
+}
+
+func BenchmarkRingBuffer_SmallWrites(b *testing.B) {
+	rb := NewRingBuffer(64 << 10)
+	_, _ = rb.Write(make([]byte, 32<<10))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = rb.WriteUint32(uint32(i))
+		_, _ = rb.ReadUint32()
+	}
+}
+
+func BenchmarkFixedBuffer_SmallWrites(b *testing.B) {
+	fb := NewFixedBuffer(64 << 10)
+	_, _ = fb.Write(make([]byte, 32<<10))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = fb.WriteUint32(uint32(i))
+		_, _ = fb.ReadUint32()
+	}
+}
@@ -0,0 +1,350 @@
+package bytes
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/godyy/gutils/buffer"
+	pkg_errors "github.com/pkg/errors"
+)
+
+// StreamReader 自 io.Reader 中按需读取数据至内部的 FixedBuffer 并解码, 用于逐条
+// 解码大文件等无法一次性读入内存的数据
+// 编码格式与 Buffer 一致. 内部缓冲区的大小即最大缓冲数据量, 长度超过缓冲区的字符串
+// 及字节切片直接自 io.Reader 中读取. 数据在定长数据、varint 或字符串中间结束时返回
+// io.ErrUnexpectedEOF, 底层 io.Reader 返回的其它错误原样返回, 之后可继续读取.
+type StreamReader struct {
+	r      io.Reader
+	buf    *FixedBuffer
+	err    error // 底层 io.Reader 返回的尚未处理的错误
+	direct int64 // 绕过缓冲区直接读取的字节数
+}
+
+var _ buffer.Reader = (*StreamReader)(nil)
+
+// NewStreamReader 创建读取 r 的 StreamReader, size 为最大缓冲数据量, opts 用于配置
+// 解码限制
+// size 小于 MaxVarintLen64 时 panic.
+func NewStreamReader(r io.Reader, size int, opts ...Option) *StreamReader {
+	if size < MaxVarintLen64 {
+		panic("bytes.NewStreamReader: size < MaxVarintLen64")
+	}
+	return &StreamReader{r: r, buf: NewFixedBuffer(size, opts...)}
+}
+
+// Reset 丢弃缓冲的数据及错误, 改为读取 r
+func (s *StreamReader) Reset(r io.Reader) {
+	s.r = r
+	s.buf.Reset()
+	s.err = nil
+	s.direct = 0
+}
+
+// Buffered 获取已缓冲未读取的字节数
+func (s *StreamReader) Buffered() int {
+	return s.buf.Readable()
+}
+
+// Offset 获取自创建或上次 Reset 起已解码的字节数, 可用于定位出错的记录
+func (s *StreamReader) Offset() int64 {
+	return int64(s.buf.shifted+s.buf.r) + s.direct
+}
+
+// maxConsecutiveEmptyReads 连续读取到0字节的最大次数
+const maxConsecutiveEmptyReads = 100
+
+// fill 自 r 中读取数据, 直至缓冲的数据不少于 n 个字节, n 不得超过缓冲区大小
+func (s *StreamReader) fill(n int) error {
+	for empty := 0; s.buf.Readable() < n; {
+		if s.err != nil {
+			return s.readErr()
+		}
+		m, err := s.buf.ReadFrom(s.r)
+		if err != nil {
+			s.err = err
+		} else if m > 0 {
+			empty = 0
+		} else if empty++; empty >= maxConsecutiveEmptyReads {
+			return io.ErrNoProgress
+		}
+	}
+	return nil
+}
+
+// readErr 返回并清除底层 io.Reader 返回的错误
+// 已缓冲部分数据时, io.EOF 转换为 io.ErrUnexpectedEOF.
+func (s *StreamReader) readErr() error {
+	err := s.err
+	s.err = nil
+	if err == io.EOF && s.buf.Readable() > 0 {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// fillVarint 缓冲一个完整的 varint, 前 maxLen 个字节中不包含 varint 的结尾时返回
+// ErrVarintOverflow
+func (s *StreamReader) fillVarint(maxLen int) error {
+	for {
+		p := s.buf.UnreadData()
+		for _, c := range p[:min(len(p), maxLen)] {
+			if c < 0x80 {
+				return nil
+			}
+		}
+		if len(p) >= maxLen {
+			return ErrVarintOverflow
+		}
+		if err := s.fill(len(p) + 1); err != nil {
+			return err
+		}
+	}
+}
+
+// streamRead 缓冲 n 个字节后调用 read 读取
+func streamRead[T any](s *StreamReader, n int, read func() (T, error)) (T, error) {
+	if err := s.fill(n); err != nil {
+		var zero T
+		return zero, err
+	}
+	return read()
+}
+
+// streamReadVarint 缓冲一个完整的 varint 后调用 read 读取
+func streamReadVarint[T any](s *StreamReader, maxLen int, read func() (T, error)) (T, error) {
+	if err := s.fillVarint(maxLen); err != nil {
+		var zero T
+		return zero, err
+	}
+	return read()
+}
+
+// Read 读取缓冲的数据, 无缓冲数据时自 r 中读取一次
+// len(p) 不小于缓冲区大小时直接读取至 p.
+func (s *StreamReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if s.buf.Readable() == 0 {
+		if s.err != nil {
+			return 0, s.readErr()
+		}
+		if len(p) >= s.buf.Size() {
+			n, err := s.r.Read(p)
+			s.direct += int64(n)
+			return n, err
+		}
+		if err := s.fill(1); err != nil {
+			return 0, err
+		}
+	}
+	return s.buf.Read(p)
+}
+
+func (s *StreamReader) ReadByte() (byte, error) {
+	return streamRead(s, 1, s.buf.ReadByte)
+}
+
+func (s *StreamReader) ReadInt8() (int8, error) {
+	return streamRead(s, 1, s.buf.ReadInt8)
+}
+
+func (s *StreamReader) ReadUint8() (uint8, error) {
+	return streamRead(s, 1, s.buf.ReadUint8)
+}
+
+func (s *StreamReader) ReadBool() (bool, error) {
+	return streamRead(s, 1, s.buf.ReadBool)
+}
+
+func (s *StreamReader) ReadInt16() (int16, error) {
+	return streamRead(s, 2, s.buf.ReadInt16)
+}
+
+func (s *StreamReader) ReadUint16() (uint16, error) {
+	return streamRead(s, 2, s.buf.ReadUint16)
+}
+
+func (s *StreamReader) ReadInt32() (int32, error) {
+	return streamRead(s, 4, s.buf.ReadInt32)
+}
+
+func (s *StreamReader) ReadUint32() (uint32, error) {
+	return streamRead(s, 4, s.buf.ReadUint32)
+}
+
+func (s *StreamReader) ReadInt64() (int64, error) {
+	return streamRead(s, 8, s.buf.ReadInt64)
+}
+
+func (s *StreamReader) ReadUint64() (uint64, error) {
+	return streamRead(s, 8, s.buf.ReadUint64)
+}
+
+func (s *StreamReader) ReadFloat32() (float32, error) {
+	return streamRead(s, 4, s.buf.ReadFloat32)
+}
+
+func (s *StreamReader) ReadFloat64() (float64, error) {
+	return streamRead(s, 8, s.buf.ReadFloat64)
+}
+
+func (s *StreamReader) ReadBigInt16() (int16, error) {
+	return streamRead(s, 2, s.buf.ReadBigInt16)
+}
+
+func (s *StreamReader) ReadBigUint16() (uint16, error) {
+	return streamRead(s, 2, s.buf.ReadBigUint16)
+}
+
+func (s *StreamReader) ReadBigInt32() (int32, error) {
+	return streamRead(s, 4, s.buf.ReadBigInt32)
+}
+
+func (s *StreamReader) ReadBigUint32() (uint32, error) {
+	return streamRead(s, 4, s.buf.ReadBigUint32)
+}
+
+func (s *StreamReader) ReadBigInt64() (int64, error) {
+	return streamRead(s, 8, s.buf.ReadBigInt64)
+}
+
+func (s *StreamReader) ReadBigUint64() (uint64, error) {
+	return streamRead(s, 8, s.buf.ReadBigUint64)
+}
+
+func (s *StreamReader) ReadBigFloat32() (float32, error) {
+	return streamRead(s, 4, s.buf.ReadBigFloat32)
+}
+
+func (s *StreamReader) ReadBigFloat64() (float64, error) {
+	return streamRead(s, 8, s.buf.ReadBigFloat64)
+}
+
+func (s *StreamReader) ReadLitInt16() (int16, error) {
+	return streamRead(s, 2, s.buf.ReadLitInt16)
+}
+
+func (s *StreamReader) ReadLitUint16() (uint16, error) {
+	return streamRead(s, 2, s.buf.ReadLitUint16)
+}
+
+func (s *StreamReader) ReadLitInt32() (int32, error) {
+	return streamRead(s, 4, s.buf.ReadLitInt32)
+}
+
+func (s *StreamReader) ReadLitUint32() (uint32, error) {
+	return streamRead(s, 4, s.buf.ReadLitUint32)
+}
+
+func (s *StreamReader) ReadLitInt64() (int64, error) {
+	return streamRead(s, 8, s.buf.ReadLitInt64)
+}
+
+func (s *StreamReader) ReadLitUint64() (uint64, error) {
+	return streamRead(s, 8, s.buf.ReadLitUint64)
+}
+
+func (s *StreamReader) ReadLitFloat32() (float32, error) {
+	return streamRead(s, 4, s.buf.ReadLitFloat32)
+}
+
+func (s *StreamReader) ReadLitFloat64() (float64, error) {
+	return streamRead(s, 8, s.buf.ReadLitFloat64)
+}
+
+func (s *StreamReader) ReadVarint16() (int16, error) {
+	return streamReadVarint(s, MaxVarintLen16, s.buf.ReadVarint16)
+}
+
+func (s *StreamReader) ReadUvarint16() (uint16, error) {
+	return streamReadVarint(s, MaxVarintLen16, s.buf.ReadUvarint16)
+}
+
+func (s *StreamReader) ReadVarint32() (int32, error) {
+	return streamReadVarint(s, MaxVarintLen32, s.buf.ReadVarint32)
+}
+
+func (s *StreamReader) ReadUvarint32() (uint32, error) {
+	return streamReadVarint(s, MaxVarintLen32, s.buf.ReadUvarint32)
+}
+
+func (s *StreamReader) ReadVarint64() (int64, error) {
+	return streamReadVarint(s, MaxVarintLen64, s.buf.ReadVarint64)
+}
+
+func (s *StreamReader) ReadUvarint64() (uint64, error) {
+	return streamReadVarint(s, MaxVarintLen64, s.buf.ReadUvarint64)
+}
+
+// readLenPrefixed 读取以 varint 长度为前缀的数据
+// 数据可完整缓冲时返回缓冲区中数据的视图, fresh 为 false; 否则直接自 r 中读取剩余
+// 数据至新分配的切片, fresh 为 true, 此时若读取出错, 已读取的数据无法恢复.
+func (s *StreamReader) readLenPrefixed(k lenKind) (p []byte, fresh bool, err error) {
+	if err := s.fillVarint(MaxStringLenLen); err != nil {
+		if err == ErrVarintOverflow {
+			err = pkg_errors.WithMessage(err, "read length")
+		}
+		return nil, false, err
+	}
+	i, n := binary.Varint(s.buf.UnreadData())
+	if n <= 0 || n > MaxStringLenLen {
+		return nil, false, pkg_errors.WithMessage(ErrVarintOverflow, "read length")
+	}
+	if i > MaxStringLength {
+		return nil, false, k.errExceed()
+	}
+	l := int(i)
+	if err := s.buf.lim.checkRead(k, l); err != nil {
+		return nil, false, err
+	}
+
+	if n+l <= s.buf.Size() {
+		if err := s.fill(n + l); err != nil {
+			return nil, false, err
+		}
+		p, err := s.buf.readLenPrefixed(k)
+		return p, false, err
+	}
+
+	// 超过缓冲区大小, 取出已缓冲的部分后直接自 r 中读取剩余数据
+	_, _ = s.buf.Skip(n)
+	buffered := s.buf.UnreadData()
+	p = make([]byte, 0, max(len(buffered), min(l, ioReadChunk)))
+	p = append(p, buffered...)
+	_, _ = s.buf.Skip(len(buffered))
+	if s.err != nil {
+		if err = s.readErr(); err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, true, err
+	}
+
+	p, m, err := readGrowing(s.r, p, l)
+	s.direct += int64(m)
+	if err != nil {
+		return nil, true, err
+	}
+	s.buf.lim.addDecoded(l)
+	return p, true, nil
+}
+
+func (s *StreamReader) ReadString() (string, error) {
+	p, _, err := s.readLenPrefixed(lenString)
+	if err != nil || len(p) == 0 {
+		return "", err
+	}
+	return string(p), nil
+}
+
+// ReadBytes 读取 WriteBytes 写入的字节切片, 长度为 0 时返回 nil
+func (s *StreamReader) ReadBytes() ([]byte, error) {
+	p, fresh, err := s.readLenPrefixed(lenBytes)
+	if err != nil || len(p) == 0 {
+		return nil, err
+	}
+	if fresh {
+		return p, nil
+	}
+	return append([]byte(nil), p...), nil
+}
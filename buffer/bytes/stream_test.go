@@ -0,0 +1,128 @@
+package bytes
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/godyy/gutils/buffer"
+	"github.com/godyy/gutils/buffer/buffertest"
+)
+
+func TestStreamReader_Compatible(t *testing.T) {
+	w := NewBuffer(nil)
+	if err := writeIOSample(w); err != nil {
+		t.Fatal(err)
+	}
+	readers := map[string]io.Reader{
+		"OneByte": iotest.OneByteReader(bytes.NewReader(w.Data())),
+		"Half":    iotest.HalfReader(bytes.NewReader(w.Data())),
+		"DataErr": iotest.DataErrReader(bytes.NewReader(w.Data())),
+	}
+	for name, r := range readers {
+		t.Run(name, func(t *testing.T) {
+			readIOSample(t, NewStreamReader(r, MaxVarintLen64))
+		})
+	}
+}
+
+func TestStreamReader_Property(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		ops := buffertest.Generate(rnd, 1+rnd.Intn(30))
+		w := NewBuffer(nil)
+		_ = buffertest.Write(w, ops)
+		data := w.Data()
+
+		s := NewStreamReader(iotest.HalfReader(bytes.NewReader(data)), 32)
+		if err := buffertest.Read(s, ops); err != nil {
+			t.Fatal(err)
+		}
+		if s.Offset() != int64(len(data)) {
+			t.Fatalf("Offset %d, want %d", s.Offset(), len(data))
+		}
+		if _, err := s.ReadByte(); err != io.EOF {
+			t.Fatalf("ReadByte at end: expected io.EOF, got %v", err)
+		}
+
+		for _, cut := range truncateCuts(ops) {
+			s.Reset(iotest.OneByteReader(bytes.NewReader(data[:cut])))
+			if err := buffertest.ReadTruncated(s, ops, cut); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestStreamReader_LargeString(t *testing.T) {
+	long := strings.Repeat("0123456789", 100)
+	w := NewBuffer(nil)
+	_ = w.WriteString(long)
+	_ = w.WriteBytes([]byte(long))
+	_ = w.WriteUint32(7)
+	data := w.Data()
+
+	s := NewStreamReader(iotest.OneByteReader(bytes.NewReader(data)), 64)
+	if got, err := s.ReadString(); err != nil || got != long {
+		t.Fatalf("ReadString: %d, %v", len(got), err)
+	}
+	if got, err := s.ReadBytes(); err != nil || string(got) != long {
+		t.Fatalf("ReadBytes: %d, %v", len(got), err)
+	}
+	if got, err := s.ReadUint32(); err != nil || got != 7 {
+		t.Fatalf("ReadUint32: %d, %v", got, err)
+	}
+	if s.Offset() != int64(len(data)) {
+		t.Fatalf("Offset %d, want %d", s.Offset(), len(data))
+	}
+
+	s = NewStreamReader(bytes.NewReader(data), 64, WithMaxStringLength(100))
+	if _, err := s.ReadString(); err != buffer.ErrStringLenExceedLimit {
+		t.Fatalf("ReadString: expected ErrStringLenExceedLimit, got %v", err)
+	}
+
+	s = NewStreamReader(bytes.NewReader(data[:500]), 64)
+	if _, err := s.ReadString(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("truncated ReadString: expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestStreamReader_Errors(t *testing.T) {
+	w := NewBuffer(nil)
+	_ = w.WriteBigUint64(1)
+	_ = w.WriteBigUint64(2)
+
+	// 第二次读取返回 iotest.ErrTimeout, 之后的读取恢复正常
+	s := NewStreamReader(iotest.TimeoutReader(iotest.OneByteReader(bytes.NewReader(w.Data()))), 16)
+	if _, err := s.ReadBigUint64(); err != iotest.ErrTimeout {
+		t.Fatalf("expected ErrTimeout, got %v", err)
+	}
+	if v, err := s.ReadBigUint64(); err != nil || v != 1 {
+		t.Fatalf("ReadBigUint64 after timeout: %d, %v", v, err)
+	}
+	if v, err := s.ReadBigUint64(); err != nil || v != 2 {
+		t.Fatalf("ReadBigUint64: %d, %v", v, err)
+	}
+
+	s = NewStreamReader(bytes.NewReader([]byte{0x80, 0x80, 0x80, 0x80}), 16)
+	if _, err := s.ReadVarint16(); err != ErrVarintOverflow {
+		t.Fatalf("overflow varint: expected ErrVarintOverflow, got %v", err)
+	}
+	s = NewStreamReader(bytes.NewReader([]byte{0x80}), 16)
+	if _, err := s.ReadVarint64(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("truncated varint: expected io.ErrUnexpectedEOF, got %v", err)
+	}
+
+	s = NewStreamReader(emptyReader{}, 16)
+	if _, err := s.ReadByte(); err != io.ErrNoProgress {
+		t.Fatalf("empty reads: expected io.ErrNoProgress, got %v", err)
+	}
+}
+
+// emptyReader 始终读取到 0 字节且不返回错误
+type emptyReader struct{}
+
+func (emptyReader) Read([]byte) (int, error) { return 0, nil }
@@ -0,0 +1,485 @@
+package bytes
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/godyy/gutils/buffer"
+)
+
+// TraceOp 跟踪记录的操作类型
+type TraceOp int8
+
+const (
+	TraceRead  TraceOp = iota // 读取
+	TraceWrite                // 写入
+)
+
+func (op TraceOp) String() string {
+	if op == TraceRead {
+		return "read"
+	}
+	return "write"
+}
+
+// TraceRecord 一次有类型读写的记录
+type TraceRecord struct {
+	Op     TraceOp
+	Method string // 调用的方法名
+	Offset int    // 读写的起始逻辑位置, 与 Buffer.Dump 的偏移量一致
+	Len    int    // 读写的字节数
+	Value  any    // 读取或写入的值
+	Data   []byte // 读写的原始数据的副本
+	Err    error  // 方法返回的错误
+}
+
+func (r TraceRecord) String() string {
+	verb := "%v"
+	if _, ok := r.Value.(string); ok {
+		verb = "%q"
+	}
+	s := fmt.Sprintf("%-5s %08x +%-3d %-18s "+verb+" [% x]", r.Op, r.Offset, r.Len, r.Method, r.Value, r.Data)
+	if r.Err != nil {
+		s += " error: " + r.Err.Error()
+	}
+	return s
+}
+
+// Tracer 包装 Buffer, 记录每次有类型读写的方法、位置、长度及值, 用于将错误解码的
+// 消息逐字段输出
+// buffer.Reader/buffer.Writer 的方法, 以及 Bytes、Float16、Quantized、NoCopy、Value
+// 系列方法会被记录; 其余方法(如 ReadFrom、Mark)直接作用于内嵌的 Buffer, 不被记录.
+type Tracer struct {
+	*Buffer
+	records []TraceRecord
+}
+
+var _ buffer.ReadWriter = (*Tracer)(nil)
+
+// NewTracer 创建跟踪 b 的 Tracer
+func NewTracer(b *Buffer) *Tracer {
+	if b == nil {
+		panic("bytes.NewTracer: b nil")
+	}
+	return &Tracer{Buffer: b}
+}
+
+// Records 获取已记录的读写
+func (t *Tracer) Records() []TraceRecord {
+	return t.records
+}
+
+// ClearRecords 清空已记录的读写
+func (t *Tracer) ClearRecords() {
+	clear(t.records)
+	t.records = t.records[:0]
+}
+
+// Trace 逐行输出已记录的读写
+func (t *Tracer) Trace() string {
+	var sb strings.Builder
+	for _, r := range t.records {
+		sb.WriteString(r.String())
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// traceRead 调用 read 并记录读取的位置、数据及值
+func traceRead[T any](t *Tracer, method string, read func() (T, error)) (T, error) {
+	start := t.off
+	v, err := read()
+	r := TraceRecord{Op: TraceRead, Method: method, Offset: t.shifted + start, Value: v, Err: err}
+	if n := t.off - start; n > 0 {
+		r.Len = n
+		r.Data = append([]byte(nil), t.buf[start:t.off]...)
+	}
+	t.records = append(t.records, r)
+	return v, err
+}
+
+// traceWrite 调用 write 写入 v 并记录写入的位置及数据
+func traceWrite[T any](t *Tracer, method string, v T, write func(T) error) error {
+	pos := t.writePos()
+	err := write(v)
+	t.recordWrite(method, pos, v, err)
+	return err
+}
+
+// traceWriteN 同 traceWrite, 用于返回写入字节数的方法
+func traceWriteN[T any](t *Tracer, method string, v T, write func(T) (int, error)) (int, error) {
+	pos := t.writePos()
+	n, err := write(v)
+	t.recordWrite(method, pos, v, err)
+	return n, err
+}
+
+func (t *Tracer) recordWrite(method string, pos int, v any, err error) {
+	r := TraceRecord{Op: TraceWrite, Method: method, Offset: pos, Value: v, Err: err}
+	if n := t.writePos() - pos; n > 0 {
+		r.Len = n
+		r.Data = append([]byte(nil), t.buf[len(t.buf)-n:]...)
+	}
+	t.records = append(t.records, r)
+}
+
+func (t *Tracer) Read(p []byte) (int, error) {
+	return traceRead(t, "Read", func() (int, error) { return t.Buffer.Read(p) })
+}
+
+func (t *Tracer) Write(p []byte) (int, error) {
+	return traceWriteN(t, "Write", p, t.Buffer.Write)
+}
+
+func (t *Tracer) ReadByte() (byte, error) {
+	return traceRead(t, "ReadByte", t.Buffer.ReadByte)
+}
+
+func (t *Tracer) WriteByte(c byte) error {
+	return traceWrite(t, "WriteByte", c, t.Buffer.WriteByte)
+}
+
+func (t *Tracer) ReadInt8() (int8, error) {
+	return traceRead(t, "ReadInt8", t.Buffer.ReadInt8)
+}
+
+func (t *Tracer) WriteInt8(i int8) error {
+	return traceWrite(t, "WriteInt8", i, t.Buffer.WriteInt8)
+}
+
+func (t *Tracer) ReadUint8() (uint8, error) {
+	return traceRead(t, "ReadUint8", t.Buffer.ReadUint8)
+}
+
+func (t *Tracer) WriteUint8(i uint8) error {
+	return traceWrite(t, "WriteUint8", i, t.Buffer.WriteUint8)
+}
+
+func (t *Tracer) ReadBool() (bool, error) {
+	return traceRead(t, "ReadBool", t.Buffer.ReadBool)
+}
+
+func (t *Tracer) WriteBool(v bool) error {
+	return traceWrite(t, "WriteBool", v, t.Buffer.WriteBool)
+}
+
+func (t *Tracer) ReadInt16() (int16, error) {
+	return traceRead(t, "ReadInt16", t.Buffer.ReadInt16)
+}
+
+func (t *Tracer) WriteInt16(i int16) error {
+	return traceWrite(t, "WriteInt16", i, t.Buffer.WriteInt16)
+}
+
+func (t *Tracer) ReadUint16() (uint16, error) {
+	return traceRead(t, "ReadUint16", t.Buffer.ReadUint16)
+}
+
+func (t *Tracer) WriteUint16(i uint16) error {
+	return traceWrite(t, "WriteUint16", i, t.Buffer.WriteUint16)
+}
+
+func (t *Tracer) ReadInt32() (int32, error) {
+	return traceRead(t, "ReadInt32", t.Buffer.ReadInt32)
+}
+
+func (t *Tracer) WriteInt32(i int32) error {
+	return traceWrite(t, "WriteInt32", i, t.Buffer.WriteInt32)
+}
+
+func (t *Tracer) ReadUint32() (uint32, error) {
+	return traceRead(t, "ReadUint32", t.Buffer.ReadUint32)
+}
+
+func (t *Tracer) WriteUint32(i uint32) error {
+	return traceWrite(t, "WriteUint32", i, t.Buffer.WriteUint32)
+}
+
+func (t *Tracer) ReadInt64() (int64, error) {
+	return traceRead(t, "ReadInt64", t.Buffer.ReadInt64)
+}
+
+func (t *Tracer) WriteInt64(i int64) error {
+	return traceWrite(t, "WriteInt64", i, t.Buffer.WriteInt64)
+}
+
+func (t *Tracer) ReadUint64() (uint64, error) {
+	return traceRead(t, "ReadUint64", t.Buffer.ReadUint64)
+}
+
+func (t *Tracer) WriteUint64(i uint64) error {
+	return traceWrite(t, "WriteUint64", i, t.Buffer.WriteUint64)
+}
+
+func (t *Tracer) ReadFloat32() (float32, error) {
+	return traceRead(t, "ReadFloat32", t.Buffer.ReadFloat32)
+}
+
+func (t *Tracer) WriteFloat32(f float32) error {
+	return traceWrite(t, "WriteFloat32", f, t.Buffer.WriteFloat32)
+}
+
+func (t *Tracer) ReadFloat64() (float64, error) {
+	return traceRead(t, "ReadFloat64", t.Buffer.ReadFloat64)
+}
+
+func (t *Tracer) WriteFloat64(f float64) error {
+	return traceWrite(t, "WriteFloat64", f, t.Buffer.WriteFloat64)
+}
+
+func (t *Tracer) ReadBigInt16() (int16, error) {
+	return traceRead(t, "ReadBigInt16", t.Buffer.ReadBigInt16)
+}
+
+func (t *Tracer) WriteBigInt16(i int16) error {
+	return traceWrite(t, "WriteBigInt16", i, t.Buffer.WriteBigInt16)
+}
+
+func (t *Tracer) ReadBigUint16() (uint16, error) {
+	return traceRead(t, "ReadBigUint16", t.Buffer.ReadBigUint16)
+}
+
+func (t *Tracer) WriteBigUint16(i uint16) error {
+	return traceWrite(t, "WriteBigUint16", i, t.Buffer.WriteBigUint16)
+}
+
+func (t *Tracer) ReadBigInt32() (int32, error) {
+	return traceRead(t, "ReadBigInt32", t.Buffer.ReadBigInt32)
+}
+
+func (t *Tracer) WriteBigInt32(i int32) error {
+	return traceWrite(t, "WriteBigInt32", i, t.Buffer.WriteBigInt32)
+}
+
+func (t *Tracer) ReadBigUint32() (uint32, error) {
+	return traceRead(t, "ReadBigUint32", t.Buffer.ReadBigUint32)
+}
+
+func (t *Tracer) WriteBigUint32(i uint32) error {
+	return traceWrite(t, "WriteBigUint32", i, t.Buffer.WriteBigUint32)
+}
+
+func (t *Tracer) ReadBigInt64() (int64, error) {
+	return traceRead(t, "ReadBigInt64", t.Buffer.ReadBigInt64)
+}
+
+func (t *Tracer) WriteBigInt64(i int64) error {
+	return traceWrite(t, "WriteBigInt64", i, t.Buffer.WriteBigInt64)
+}
+
+func (t *Tracer) ReadBigUint64() (uint64, error) {
+	return traceRead(t, "ReadBigUint64", t.Buffer.ReadBigUint64)
+}
+
+func (t *Tracer) WriteBigUint64(i uint64) error {
+	return traceWrite(t, "WriteBigUint64", i, t.Buffer.WriteBigUint64)
+}
+
+func (t *Tracer) ReadBigFloat32() (float32, error) {
+	return traceRead(t, "ReadBigFloat32", t.Buffer.ReadBigFloat32)
+}
+
+func (t *Tracer) WriteBigFloat32(f float32) error {
+	return traceWrite(t, "WriteBigFloat32", f, t.Buffer.WriteBigFloat32)
+}
+
+func (t *Tracer) ReadBigFloat64() (float64, error) {
+	return traceRead(t, "ReadBigFloat64", t.Buffer.ReadBigFloat64)
+}
+
+func (t *Tracer) WriteBigFloat64(f float64) error {
+	return traceWrite(t, "WriteBigFloat64", f, t.Buffer.WriteBigFloat64)
+}
+
+func (t *Tracer) ReadLitInt16() (int16, error) {
+	return traceRead(t, "ReadLitInt16", t.Buffer.ReadLitInt16)
+}
+
+func (t *Tracer) WriteLitInt16(i int16) error {
+	return traceWrite(t, "WriteLitInt16", i, t.Buffer.WriteLitInt16)
+}
+
+func (t *Tracer) ReadLitUint16() (uint16, error) {
+	return traceRead(t, "ReadLitUint16", t.Buffer.ReadLitUint16)
+}
+
+func (t *Tracer) WriteLitUint16(i uint16) error {
+	return traceWrite(t, "WriteLitUint16", i, t.Buffer.WriteLitUint16)
+}
+
+func (t *Tracer) ReadLitInt32() (int32, error) {
+	return traceRead(t, "ReadLitInt32", t.Buffer.ReadLitInt32)
+}
+
+func (t *Tracer) WriteLitInt32(i int32) error {
+	return traceWrite(t, "WriteLitInt32", i, t.Buffer.WriteLitInt32)
+}
+
+func (t *Tracer) ReadLitUint32() (uint32, error) {
+	return traceRead(t, "ReadLitUint32", t.Buffer.ReadLitUint32)
+}
+
+func (t *Tracer) WriteLitUint32(i uint32) error {
+	return traceWrite(t, "WriteLitUint32", i, t.Buffer.WriteLitUint32)
+}
+
+func (t *Tracer) ReadLitInt64() (int64, error) {
+	return traceRead(t, "ReadLitInt64", t.Buffer.ReadLitInt64)
+}
+
+func (t *Tracer) WriteLitInt64(i int64) error {
+	return traceWrite(t, "WriteLitInt64", i, t.Buffer.WriteLitInt64)
+}
+
+func (t *Tracer) ReadLitUint64() (uint64, error) {
+	return traceRead(t, "ReadLitUint64", t.Buffer.ReadLitUint64)
+}
+
+func (t *Tracer) WriteLitUint64(i uint64) error {
+	return traceWrite(t, "WriteLitUint64", i, t.Buffer.WriteLitUint64)
+}
+
+func (t *Tracer) ReadLitFloat32() (float32, error) {
+	return traceRead(t, "ReadLitFloat32", t.Buffer.ReadLitFloat32)
+}
+
+func (t *Tracer) WriteLitFloat32(f float32) error {
+	return traceWrite(t, "WriteLitFloat32", f, t.Buffer.WriteLitFloat32)
+}
+
+func (t *Tracer) ReadLitFloat64() (float64, error) {
+	return traceRead(t, "ReadLitFloat64", t.Buffer.ReadLitFloat64)
+}
+
+func (t *Tracer) WriteLitFloat64(f float64) error {
+	return traceWrite(t, "WriteLitFloat64", f, t.Buffer.WriteLitFloat64)
+}
+
+func (t *Tracer) ReadVarint16() (int16, error) {
+	return traceRead(t, "ReadVarint16", t.Buffer.ReadVarint16)
+}
+
+func (t *Tracer) WriteVarint16(i int16) (int, error) {
+	return traceWriteN(t, "WriteVarint16", i, t.Buffer.WriteVarint16)
+}
+
+func (t *Tracer) ReadUvarint16() (uint16, error) {
+	return traceRead(t, "ReadUvarint16", t.Buffer.ReadUvarint16)
+}
+
+func (t *Tracer) WriteUvarint16(i uint16) (int, error) {
+	return traceWriteN(t, "WriteUvarint16", i, t.Buffer.WriteUvarint16)
+}
+
+func (t *Tracer) ReadVarint32() (int32, error) {
+	return traceRead(t, "ReadVarint32", t.Buffer.ReadVarint32)
+}
+
+func (t *Tracer) WriteVarint32(i int32) (int, error) {
+	return traceWriteN(t, "WriteVarint32", i, t.Buffer.WriteVarint32)
+}
+
+func (t *Tracer) ReadUvarint32() (uint32, error) {
+	return traceRead(t, "ReadUvarint32", t.Buffer.ReadUvarint32)
+}
+
+func (t *Tracer) WriteUvarint32(i uint32) (int, error) {
+	return traceWriteN(t, "WriteUvarint32", i, t.Buffer.WriteUvarint32)
+}
+
+func (t *Tracer) ReadVarint64() (int64, error) {
+	return traceRead(t, "ReadVarint64", t.Buffer.ReadVarint64)
+}
+
+func (t *Tracer) WriteVarint64(i int64) (int, error) {
+	return traceWriteN(t, "WriteVarint64", i, t.Buffer.WriteVarint64)
+}
+
+func (t *Tracer) ReadUvarint64() (uint64, error) {
+	return traceRead(t, "ReadUvarint64", t.Buffer.ReadUvarint64)
+}
+
+func (t *Tracer) WriteUvarint64(i uint64) (int, error) {
+	return traceWriteN(t, "WriteUvarint64", i, t.Buffer.WriteUvarint64)
+}
+
+func (t *Tracer) ReadFloat16() (float32, error) {
+	return traceRead(t, "ReadFloat16", t.Buffer.ReadFloat16)
+}
+
+func (t *Tracer) WriteFloat16(f float32) error {
+	return traceWrite(t, "WriteFloat16", f, t.Buffer.WriteFloat16)
+}
+
+func (t *Tracer) ReadBigFloat16() (float32, error) {
+	return traceRead(t, "ReadBigFloat16", t.Buffer.ReadBigFloat16)
+}
+
+func (t *Tracer) WriteBigFloat16(f float32) error {
+	return traceWrite(t, "WriteBigFloat16", f, t.Buffer.WriteBigFloat16)
+}
+
+func (t *Tracer) ReadLitFloat16() (float32, error) {
+	return traceRead(t, "ReadLitFloat16", t.Buffer.ReadLitFloat16)
+}
+
+func (t *Tracer) WriteLitFloat16(f float32) error {
+	return traceWrite(t, "WriteLitFloat16", f, t.Buffer.WriteLitFloat16)
+}
+
+func (t *Tracer) ReadString() (string, error) {
+	return traceRead(t, "ReadString", t.Buffer.ReadString)
+}
+
+func (t *Tracer) WriteString(s string) error {
+	return traceWrite(t, "WriteString", s, t.Buffer.WriteString)
+}
+
+func (t *Tracer) ReadBytes() ([]byte, error) {
+	return traceRead(t, "ReadBytes", t.Buffer.ReadBytes)
+}
+
+func (t *Tracer) WriteBytes(p []byte) error {
+	return traceWrite(t, "WriteBytes", p, t.Buffer.WriteBytes)
+}
+
+// ReadStringNoCopy 记录的值为复制, 返回值仍引用 buf 的内存
+func (t *Tracer) ReadStringNoCopy() (string, error) {
+	v, err := traceRead(t, "ReadStringNoCopy", t.Buffer.ReadStringNoCopy)
+	t.records[len(t.records)-1].Value = strings.Clone(v)
+	return v, err
+}
+
+// ReadBytesNoCopy 记录的值为复制, 返回值仍引用 buf 的内存
+func (t *Tracer) ReadBytesNoCopy() ([]byte, error) {
+	v, err := traceRead(t, "ReadBytesNoCopy", t.Buffer.ReadBytesNoCopy)
+	t.records[len(t.records)-1].Value = slices.Clone(v)
+	return v, err
+}
+
+func (t *Tracer) ReadQuantized32(scale float32) (float32, error) {
+	return traceRead(t, "ReadQuantized32", func() (float32, error) { return t.Buffer.ReadQuantized32(scale) })
+}
+
+func (t *Tracer) WriteQuantized32(f float32, scale float32) (int, error) {
+	return traceWriteN(t, "WriteQuantized32", f, func(f float32) (int, error) { return t.Buffer.WriteQuantized32(f, scale) })
+}
+
+func (t *Tracer) ReadQuantized64(scale float64) (float64, error) {
+	return traceRead(t, "ReadQuantized64", func() (float64, error) { return t.Buffer.ReadQuantized64(scale) })
+}
+
+func (t *Tracer) WriteQuantized64(f float64, scale float64) (int, error) {
+	return traceWriteN(t, "WriteQuantized64", f, func(f float64) (int, error) { return t.Buffer.WriteQuantized64(f, scale) })
+}
+
+// WriteValue 通过反射将 v 编码写入buf, 逐字段记录写入
+func (t *Tracer) WriteValue(v any) error {
+	return EncodeValue(t, v)
+}
+
+// ReadValue 通过反射自buf中解码数据至 v, 逐字段记录读取
+func (t *Tracer) ReadValue(v any) error {
+	return DecodeValue(t, v)
+}
@@ -0,0 +1,333 @@
+// Package envelope 为 bytes.Buffer 中的数据添加带校验和的信封, 并可选地压缩数据,
+// 用于持久化或跨进程传递.
+//
+// 信封格式(多字节字段均为大端字节序):
+//
+//	+---------+-------+----------------+----------------------+---------+
+//	| version | flags | length(uint32) | checksum(4 或 8 字节) | payload |
+//	+---------+-------+----------------+----------------------+---------+
+//
+// flags 的低 2 位为压缩方式, 第 3 位为校验和算法. length 为 payload 的长度, 即压缩
+// 后的长度. checksum 覆盖 version、flags、length 以及 payload, 打开信封时先校验再
+// 解压.
+package envelope
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"math"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/godyy/gutils/buffer/bytes"
+	pkg_errors "github.com/pkg/errors"
+)
+
+// Version 当前的信封格式版本
+const Version = 1
+
+// ErrChecksumMismatch 校验和不匹配, 数据已损坏
+var ErrChecksumMismatch = errors.New("envelope: checksum mismatch")
+
+// ErrTruncated 信封数据不完整
+var ErrTruncated = errors.New("envelope: truncated")
+
+// ErrUnknownVersion 未知的信封格式版本
+var ErrUnknownVersion = errors.New("envelope: unknown version")
+
+// ErrInvalidFlags 无效的压缩方式或校验和算法
+var ErrInvalidFlags = errors.New("envelope: invalid flags")
+
+// ErrPayloadTooLarge 数据长度超过限制
+var ErrPayloadTooLarge = errors.New("envelope: payload too large")
+
+// Compression 压缩方式
+type Compression uint8
+
+const (
+	CompressionNone  Compression = iota // 不压缩
+	CompressionFlate                    // compress/flate
+	CompressionGzip                     // compress/gzip
+)
+
+// Checksum 校验和算法
+type Checksum uint8
+
+const (
+	ChecksumCRC32C   Checksum = iota // CRC-32 Castagnoli, 4 字节
+	ChecksumXXHash64                 // xxHash64, 8 字节
+)
+
+// size 校验和的字节数
+func (c Checksum) size() int {
+	if c == ChecksumXXHash64 {
+		return 8
+	}
+	return 4
+}
+
+// sum 计算 head 与 payload 的校验和
+func (c Checksum) sum(head, payload []byte) uint64 {
+	if c == ChecksumXXHash64 {
+		d := xxhash.New()
+		_, _ = d.Write(head)
+		_, _ = d.Write(payload)
+		return d.Sum64()
+	}
+	crc := crc32.Update(0, castagnoli, head)
+	return uint64(crc32.Update(crc, castagnoli, payload))
+}
+
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+const (
+	flagCompressionMask = 0x03 // 压缩方式
+	flagChecksumXXHash  = 0x04 // 使用 xxHash64 校验和
+	flagKnown           = flagCompressionMask | flagChecksumXXHash
+
+	// prefixLen 校验和之前的头部长度
+	prefixLen = 6
+)
+
+// DefaultMaxPayloadSize 打开信封时默认的数据长度上限, 同时限制压缩前后的长度
+const DefaultMaxPayloadSize = 64 << 20
+
+type config struct {
+	compression    Compression
+	level          int
+	checksum       Checksum
+	maxPayloadSize int
+}
+
+// Option 用于配置 Seal 及 Open
+type Option interface {
+	apply(*config)
+}
+
+// buildConfig 将默认配置与选项合并
+func buildConfig(opts []Option) config {
+	c := config{
+		compression:    CompressionNone,
+		level:          flate.DefaultCompression,
+		checksum:       ChecksumCRC32C,
+		maxPayloadSize: DefaultMaxPayloadSize,
+	}
+	for _, opt := range opts {
+		opt.apply(&c)
+	}
+	return c
+}
+
+type compressionOption struct {
+	compression Compression
+	level       int
+}
+
+func (o compressionOption) apply(c *config) {
+	c.compression = o.compression
+	c.level = o.level
+}
+
+// WithCompression 指定 Seal 使用的压缩方式及压缩级别, level 取值同 compress/flate
+func WithCompression(compression Compression, level int) Option {
+	return compressionOption{compression: compression, level: level}
+}
+
+type checksumOption Checksum
+
+func (o checksumOption) apply(c *config) {
+	c.checksum = Checksum(o)
+}
+
+// WithChecksum 指定 Seal 使用的校验和算法, 默认为 ChecksumCRC32C
+func WithChecksum(checksum Checksum) Option {
+	return checksumOption(checksum)
+}
+
+type maxPayloadSizeOption int
+
+func (o maxPayloadSizeOption) apply(c *config) {
+	c.maxPayloadSize = int(o)
+}
+
+// WithMaxPayloadSize 指定 Open 允许的最大数据长度, 同时限制压缩前后的长度,
+// 用于防止损坏或恶意的数据导致过量分配
+func WithMaxPayloadSize(n int) Option {
+	return maxPayloadSizeOption(n)
+}
+
+// Seal 将 src 中的未读数据封装为信封并写入 dst, 不改变 src 的读取位置
+// dst 与 src 不能是同一个 Buffer.
+func Seal(dst, src *bytes.Buffer, opts ...Option) error {
+	c := buildConfig(opts)
+	if c.compression > CompressionGzip || c.checksum > ChecksumXXHash64 {
+		return ErrInvalidFlags
+	}
+
+	flags := byte(c.compression)
+	if c.checksum == ChecksumXXHash64 {
+		flags |= flagChecksumXXHash
+	}
+
+	// 先写入头部占位, 数据写入后回填长度及校验和. dst 扩容时可能滑动未读数据,
+	// 因此使用相对于未读数据的偏移. 出错时丢弃已写入的数据.
+	start := dst.Readable()
+	headLen := prefixLen + c.checksum.size()
+	var head [prefixLen + 8]byte
+	if _, err := dst.Write(head[:headLen]); err != nil {
+		dst.Truncate(start)
+		return err
+	}
+
+	if err := compress(dst, src.UnreadData(), c); err != nil {
+		dst.Truncate(start)
+		return err
+	}
+
+	data := dst.UnreadData()[start:]
+	payload := data[headLen:]
+	if uint64(len(payload)) > math.MaxUint32 {
+		dst.Truncate(start)
+		return ErrPayloadTooLarge
+	}
+
+	data[0] = Version
+	data[1] = flags
+	binary.BigEndian.PutUint32(data[2:prefixLen], uint32(len(payload)))
+	sum := c.checksum.sum(data[:prefixLen], payload)
+	if c.checksum == ChecksumXXHash64 {
+		binary.BigEndian.PutUint64(data[prefixLen:headLen], sum)
+	} else {
+		binary.BigEndian.PutUint32(data[prefixLen:headLen], uint32(sum))
+	}
+	return nil
+}
+
+// compress 将 p 以 c 指定的方式压缩后写入 dst
+func compress(dst *bytes.Buffer, p []byte, c config) error {
+	var w io.WriteCloser
+	var err error
+	switch c.compression {
+	case CompressionFlate:
+		w, err = flate.NewWriter(dst, c.level)
+	case CompressionGzip:
+		w, err = gzip.NewWriterLevel(dst, c.level)
+	default:
+		_, err = dst.Write(p)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(p); err != nil {
+		return err
+	}
+	return w.Close()
+}
+
+// Open 自 src 中读取一个信封, 校验并解压后将数据写入 dst, dst 与 src 不能是同一个
+// Buffer.
+// 信封不完整时返回 ErrTruncated, 且不改变 src 的读取位置, 调用方可在读取更多数据后
+// 重试. 其它错误发生时, src 的读取位置同样保持不变, 且丢弃已解压至 dst 的数据.
+func Open(dst, src *bytes.Buffer, opts ...Option) error {
+	c := buildConfig(opts)
+
+	data := src.UnreadData()
+	if len(data) < prefixLen {
+		return ErrTruncated
+	}
+	if data[0] != Version {
+		return pkg_errors.WithMessagef(ErrUnknownVersion, "%d", data[0])
+	}
+
+	flags := data[1]
+	if flags&^flagKnown != 0 || Compression(flags&flagCompressionMask) > CompressionGzip {
+		return pkg_errors.WithMessagef(ErrInvalidFlags, "%#02x", flags)
+	}
+	checksum := ChecksumCRC32C
+	if flags&flagChecksumXXHash != 0 {
+		checksum = ChecksumXXHash64
+	}
+
+	headLen := prefixLen + checksum.size()
+	if len(data) < headLen {
+		return ErrTruncated
+	}
+	length := uint64(binary.BigEndian.Uint32(data[2:prefixLen]))
+	if c.maxPayloadSize > 0 && length > uint64(c.maxPayloadSize) {
+		return ErrPayloadTooLarge
+	}
+	if uint64(len(data)-headLen) < length {
+		return ErrTruncated
+	}
+
+	payload := data[headLen : headLen+int(length)]
+	var want uint64
+	if checksum == ChecksumXXHash64 {
+		want = binary.BigEndian.Uint64(data[prefixLen:headLen])
+	} else {
+		want = uint64(binary.BigEndian.Uint32(data[prefixLen:headLen]))
+	}
+	if checksum.sum(data[:prefixLen], payload) != want {
+		return ErrChecksumMismatch
+	}
+
+	start := dst.Readable()
+	if err := decompress(dst, payload, Compression(flags&flagCompressionMask), c.maxPayloadSize); err != nil {
+		dst.Truncate(start)
+		return err
+	}
+	_, _ = src.Skip(headLen + len(payload))
+	return nil
+}
+
+// decompressChunk 解压时每次读取的字节数
+const decompressChunk = 4 << 10
+
+// maxConsecutiveEmptyReads 解压时允许连续读取到 0 字节的最大次数
+const maxConsecutiveEmptyReads = 100
+
+// decompress 将 p 以 compression 指定的方式解压后写入 dst, 解压后的长度不得超过
+// maxSize
+func decompress(dst *bytes.Buffer, p []byte, compression Compression, maxSize int) error {
+	var r io.Reader
+	switch compression {
+	case CompressionFlate:
+		fr := flate.NewReader(bytes.NewBuffer(p))
+		defer fr.Close()
+		r = fr
+	case CompressionGzip:
+		gr, err := gzip.NewReader(bytes.NewBuffer(p))
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		r = gr
+	default:
+		_, err := dst.Write(p)
+		return err
+	}
+
+	start, empty := dst.Readable(), 0
+	for {
+		n, err := dst.ReadFromN(r, decompressChunk)
+		if maxSize > 0 && dst.Readable()-start > maxSize {
+			return ErrPayloadTooLarge
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			empty = 0
+		} else if empty++; empty >= maxConsecutiveEmptyReads {
+			return io.ErrNoProgress
+		}
+	}
+}
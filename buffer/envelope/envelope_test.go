@@ -0,0 +1,194 @@
+package envelope
+
+import (
+	std_bytes "bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/godyy/gutils/buffer/bytes"
+)
+
+func samplePayload() *bytes.Buffer {
+	b := bytes.NewBuffer(nil)
+	for i := 0; i < 100; i++ {
+		_ = b.WriteString(fmt.Sprintf("record-%d", i))
+		_, _ = b.WriteVarint32(int32(i))
+	}
+	return b
+}
+
+func TestSealOpen(t *testing.T) {
+	for _, compression := range []Compression{CompressionNone, CompressionFlate, CompressionGzip} {
+		for _, checksum := range []Checksum{ChecksumCRC32C, ChecksumXXHash64} {
+			t.Run(fmt.Sprintf("%d-%d", compression, checksum), func(t *testing.T) {
+				src := samplePayload()
+				sealed := bytes.NewBuffer(nil)
+				// 连续封装两个信封
+				for i := 0; i < 2; i++ {
+					err := Seal(sealed, src, WithCompression(compression, flate.BestSpeed), WithChecksum(checksum))
+					if err != nil {
+						t.Fatalf("seal: %v", err)
+					}
+				}
+				if src.Readable() != len(src.Data()) {
+					t.Fatalf("seal must not consume src")
+				}
+				if compression != CompressionNone && sealed.Readable() >= 2*src.Readable() {
+					t.Fatalf("payload not compressed: %d >= %d", sealed.Readable(), 2*src.Readable())
+				}
+
+				for i := 0; i < 2; i++ {
+					dst := bytes.NewBuffer(nil)
+					if err := Open(dst, sealed); err != nil {
+						t.Fatalf("open %d: %v", i, err)
+					}
+					if !std_bytes.Equal(dst.Data(), src.Data()) {
+						t.Fatalf("open %d: payload mismatch", i)
+					}
+				}
+				if sealed.Readable() != 0 {
+					t.Fatalf("open must consume envelope, %d bytes left", sealed.Readable())
+				}
+			})
+		}
+	}
+}
+
+func TestOpen_Errors(t *testing.T) {
+	src := samplePayload()
+	sealed := bytes.NewBuffer(nil)
+	if err := Seal(sealed, src, WithCompression(CompressionFlate, flate.DefaultCompression)); err != nil {
+		t.Fatal(err)
+	}
+	data := sealed.Data()
+
+	for n := 0; n < len(data); n++ {
+		b := bytes.NewBuffer(append([]byte(nil), data[:n]...))
+		if err := Open(bytes.NewBuffer(nil), b); err != ErrTruncated {
+			t.Fatalf("truncated at %d: expected ErrTruncated, got %v", n, err)
+		}
+		if b.Readable() != n {
+			t.Fatalf("truncated at %d: src consumed", n)
+		}
+	}
+
+	for _, i := range []int{2, 7, len(data) - 1} {
+		corrupt := append([]byte(nil), data...)
+		corrupt[i] ^= 0x10
+		err := Open(bytes.NewBuffer(nil), bytes.NewBuffer(corrupt))
+		if err != ErrChecksumMismatch && err != ErrTruncated && err != ErrPayloadTooLarge {
+			t.Fatalf("corrupt byte %d: got %v", i, err)
+		}
+	}
+	corrupt := append([]byte(nil), data...)
+	corrupt[len(corrupt)-1] ^= 0x10
+	if err := Open(bytes.NewBuffer(nil), bytes.NewBuffer(corrupt)); err != ErrChecksumMismatch {
+		t.Fatalf("corrupt payload: expected ErrChecksumMismatch, got %v", err)
+	}
+
+	corrupt = append([]byte(nil), data...)
+	corrupt[0] = Version + 1
+	if err := Open(bytes.NewBuffer(nil), bytes.NewBuffer(corrupt)); !errors.Is(err, ErrUnknownVersion) {
+		t.Fatalf("unknown version: expected ErrUnknownVersion, got %v", err)
+	}
+
+	corrupt = append([]byte(nil), data...)
+	corrupt[1] = 0x80
+	if err := Open(bytes.NewBuffer(nil), bytes.NewBuffer(corrupt)); !errors.Is(err, ErrInvalidFlags) {
+		t.Fatalf("invalid flags: expected ErrInvalidFlags, got %v", err)
+	}
+}
+
+func TestOpen_MaxPayloadSize(t *testing.T) {
+	// 高压缩率的数据, 压缩后远小于限制, 解压后超过限制
+	src := bytes.NewBuffer(make([]byte, 1<<20))
+	sealed := bytes.NewBuffer(nil)
+	if err := Seal(sealed, src, WithCompression(CompressionGzip, flate.BestCompression)); err != nil {
+		t.Fatal(err)
+	}
+	if sealed.Readable() > 64<<10 {
+		t.Fatalf("unexpected sealed size %d", sealed.Readable())
+	}
+
+	if err := Open(bytes.NewBuffer(nil), sealed, WithMaxPayloadSize(64<<10)); err != ErrPayloadTooLarge {
+		t.Fatalf("expected ErrPayloadTooLarge, got %v", err)
+	}
+	if err := Open(bytes.NewBuffer(nil), sealed, WithMaxPayloadSize(16)); err != ErrPayloadTooLarge {
+		t.Fatalf("expected ErrPayloadTooLarge, got %v", err)
+	}
+	dst := bytes.NewBuffer(nil)
+	if err := Open(dst, sealed); err != nil || dst.Readable() != 1<<20 {
+		t.Fatalf("open: %d, %v", dst.Readable(), err)
+	}
+}
+
+// Seal 出错时 dst 须保持不变, 不得残留头部占位或部分压缩数据
+func TestSeal_ErrorKeepsDst(t *testing.T) {
+	src := samplePayload()
+	dst := bytes.NewBuffer(nil)
+	if err := Seal(dst, src); err != nil {
+		t.Fatal(err)
+	}
+	sealed := append([]byte(nil), dst.UnreadData()...)
+
+	for _, compression := range []Compression{CompressionFlate, CompressionGzip} {
+		if err := Seal(dst, src, WithCompression(compression, 42)); err == nil {
+			t.Fatalf("compression %d: expected error for invalid level", compression)
+		}
+		if !std_bytes.Equal(dst.UnreadData(), sealed) {
+			t.Fatalf("compression %d: dst changed on error", compression)
+		}
+	}
+
+	out := bytes.NewBuffer(nil)
+	if err := Open(out, dst); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Readable() != 0 {
+		t.Fatalf("expected no data after the envelope, %d bytes left", dst.Readable())
+	}
+}
+
+// Open 出错时 dst 须保持不变, 不得残留部分解压的数据
+func TestOpen_ErrorKeepsDst(t *testing.T) {
+	// 截断的压缩流, 校验和有效, 解压时先输出部分数据再出错
+	var stream std_bytes.Buffer
+	fw, _ := flate.NewWriter(&stream, flate.BestSpeed)
+	_, _ = fw.Write(make([]byte, 1<<20))
+	_ = fw.Close()
+	payload := stream.Bytes()[:stream.Len()/2]
+	head := []byte{Version, byte(CompressionFlate), 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(head[2:], uint32(len(payload)))
+	corrupt := bytes.NewBuffer(nil)
+	_, _ = corrupt.Write(head)
+	_ = corrupt.WriteBigUint32(uint32(ChecksumCRC32C.sum(head, payload)))
+	_, _ = corrupt.Write(payload)
+
+	bomb := bytes.NewBuffer(nil)
+	if err := Seal(bomb, bytes.NewBuffer(make([]byte, 1<<20)), WithCompression(CompressionGzip, flate.BestCompression)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		src  *bytes.Buffer
+		opts []Option
+	}{
+		{"corrupt stream", corrupt, nil},
+		{"payload too large", bomb, []Option{WithMaxPayloadSize(64 << 10)}},
+	}
+	for _, tt := range tests {
+		dst := bytes.NewBuffer(nil)
+		_ = dst.WriteString("keep")
+		want := append([]byte(nil), dst.UnreadData()...)
+		if err := Open(dst, tt.src, tt.opts...); err == nil {
+			t.Fatalf("%s: expected error", tt.name)
+		}
+		if !std_bytes.Equal(dst.UnreadData(), want) {
+			t.Fatalf("%s: dst changed on error, %d bytes", tt.name, dst.Readable())
+		}
+	}
+}
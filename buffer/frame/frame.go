@@ -0,0 +1,245 @@
+// Package frame 实现基于长度前缀的帧编解码, 用于在 net.Conn 等字节流上收发消息.
+package frame
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/godyy/gutils/buffer"
+	"github.com/godyy/gutils/buffer/bytes"
+)
+
+// Header 帧头部格式, 即帧长度前缀的编码方式
+type Header int8
+
+const (
+	HeaderUvarint   Header = iota // uvarint 编码的长度
+	HeaderBigUint16               // 大端 uint16 长度
+	HeaderLitUint16               // 小端 uint16 长度
+	HeaderBigUint32               // 大端 uint32 长度
+	HeaderLitUint32               // 小端 uint32 长度
+)
+
+// maxLen 头部的最大字节数
+func (h Header) maxLen() int {
+	switch h {
+	case HeaderUvarint:
+		return bytes.MaxVarintLen64
+	case HeaderBigUint16, HeaderLitUint16:
+		return 2
+	case HeaderBigUint32, HeaderLitUint32:
+		return 4
+	default:
+		panic("frame: invalid header")
+	}
+}
+
+// maxFrameSize 头部可表示的最大帧长度
+func (h Header) maxFrameSize() int {
+	switch h {
+	case HeaderBigUint16, HeaderLitUint16:
+		return math.MaxUint16
+	case HeaderBigUint32, HeaderLitUint32:
+		if m := uint64(math.MaxUint32); m <= uint64(maxInt) {
+			return int(m)
+		}
+		return maxInt
+	default:
+		return maxInt
+	}
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+// write 将帧长度 n 写入 b
+func (h Header) write(b *bytes.Buffer, n int) error {
+	switch h {
+	case HeaderUvarint:
+		_, err := b.WriteUvarint64(uint64(n))
+		return err
+	case HeaderBigUint16:
+		return b.WriteBigUint16(uint16(n))
+	case HeaderLitUint16:
+		return b.WriteLitUint16(uint16(n))
+	case HeaderBigUint32:
+		return b.WriteBigUint32(uint32(n))
+	default:
+		return b.WriteLitUint32(uint32(n))
+	}
+}
+
+// read 自 p 中解析帧长度, 返回帧长度 size 及头部长度 hl, hl 为 0 表示数据不足
+func (h Header) read(p []byte) (size uint64, hl int, err error) {
+	switch h {
+	case HeaderUvarint:
+		size, hl = binary.Uvarint(p)
+		if hl < 0 {
+			return 0, 0, bytes.ErrVarintOverflow
+		}
+		return size, hl, nil
+	case HeaderBigUint16, HeaderLitUint16:
+		if len(p) < 2 {
+			return 0, 0, nil
+		}
+		if h == HeaderBigUint16 {
+			return uint64(binary.BigEndian.Uint16(p)), 2, nil
+		}
+		return uint64(binary.LittleEndian.Uint16(p)), 2, nil
+	default:
+		if len(p) < 4 {
+			return 0, 0, nil
+		}
+		if h == HeaderBigUint32 {
+			return uint64(binary.BigEndian.Uint32(p)), 4, nil
+		}
+		return uint64(binary.LittleEndian.Uint32(p)), 4, nil
+	}
+}
+
+// checkMaxFrameSize 检查最大帧长度参数
+func checkMaxFrameSize(h Header, maxFrameSize int) {
+	if maxFrameSize <= 0 {
+		panic("frame: maxFrameSize <= 0")
+	}
+	if maxFrameSize > h.maxFrameSize() {
+		panic("frame: maxFrameSize exceeds header limit")
+	}
+}
+
+// maxConsecutiveEmptyReads 连续读取到0字节的最大次数
+const maxConsecutiveEmptyReads = 100
+
+// Reader 自 io.Reader 中按帧读取数据
+// 内部复用同一个 FixedBuffer, 读取过程不会产生额外的内存分配.
+type Reader struct {
+	r            io.Reader
+	header       Header
+	maxFrameSize int
+	buf          *bytes.FixedBuffer
+	pending      int   // 上一帧尚未丢弃的字节数
+	err          error // 底层 Reader 返回的错误
+}
+
+// NewReader 创建 Reader, maxFrameSize 为允许的最大帧长度(不含头部)
+// 内部缓冲区的大小为 maxFrameSize 与头部最大长度之和, 在创建时一次性分配.
+func NewReader(r io.Reader, header Header, maxFrameSize int) *Reader {
+	checkMaxFrameSize(header, maxFrameSize)
+	return &Reader{
+		r:            r,
+		header:       header,
+		maxFrameSize: maxFrameSize,
+		buf:          bytes.NewFixedBuffer(header.maxLen() + maxFrameSize),
+	}
+}
+
+// ReadFrame 读取下一帧的数据
+// 返回的切片引用内部缓冲区, 仅在下一次调用 ReadFrame 前有效.
+// 帧长度超过 maxFrameSize 时返回 buffer.ErrExceedBufferLimit; 数据流在帧边界处
+// 结束时返回 io.EOF, 在帧中间结束时返回 io.ErrUnexpectedEOF.
+func (r *Reader) ReadFrame() ([]byte, error) {
+	if r.pending > 0 {
+		_, _ = r.buf.Skip(r.pending)
+		r.pending = 0
+	}
+
+	for {
+		size, hl, err := r.header.read(r.buf.UnreadData())
+		if err != nil {
+			return nil, err
+		}
+		if hl > 0 {
+			if size > uint64(r.maxFrameSize) {
+				return nil, buffer.ErrExceedBufferLimit
+			}
+			if n := int(size); r.buf.Readable() >= hl+n {
+				_, _ = r.buf.Skip(hl)
+				p, _ := r.buf.Peek(n)
+				r.pending = n
+				return p, nil
+			}
+		}
+
+		if err := r.fill(); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// fill 自底层 Reader 读取更多数据
+func (r *Reader) fill() error {
+	for i := 0; i < maxConsecutiveEmptyReads; i++ {
+		if r.err != nil {
+			return r.readErr()
+		}
+		n, err := r.buf.ReadFrom(r.r)
+		if err != nil {
+			r.err = err
+		}
+		if n > 0 {
+			return nil
+		}
+	}
+	return io.ErrNoProgress
+}
+
+// readErr 返回底层读取错误, 若数据在帧中间结束, 将 io.EOF 转换为 io.ErrUnexpectedEOF
+func (r *Reader) readErr() error {
+	if r.err == io.EOF && r.buf.Readable() > 0 {
+		return io.ErrUnexpectedEOF
+	}
+	return r.err
+}
+
+// Writer 将数据按帧写入 io.Writer
+// 写入的帧首先缓存在内部缓冲区中, 调用 Flush 时通过一次 WriteTo 写出.
+type Writer struct {
+	w            io.Writer
+	header       Header
+	maxFrameSize int
+	buf          *bytes.Buffer
+}
+
+// NewWriter 创建 Writer, maxFrameSize 为允许的最大帧长度(不含头部)
+func NewWriter(w io.Writer, header Header, maxFrameSize int) *Writer {
+	checkMaxFrameSize(header, maxFrameSize)
+	return &Writer{
+		w:            w,
+		header:       header,
+		maxFrameSize: maxFrameSize,
+		buf:          bytes.NewBuffer(nil),
+	}
+}
+
+// WriteFrame 将 p 作为一帧写入内部缓冲区
+// 帧长度超过 maxFrameSize 时返回 buffer.ErrExceedBufferLimit.
+func (w *Writer) WriteFrame(p []byte) error {
+	if len(p) > w.maxFrameSize {
+		return buffer.ErrExceedBufferLimit
+	}
+	if err := w.header.write(w.buf, len(p)); err != nil {
+		return err
+	}
+	_, err := w.buf.Write(p)
+	return err
+}
+
+// Buffered 获取缓冲区中尚未写出的字节数
+func (w *Writer) Buffered() int {
+	return w.buf.Readable()
+}
+
+// Flush 将缓冲区中的所有帧通过一次 WriteTo 写入底层 io.Writer
+func (w *Writer) Flush() error {
+	if w.buf.Readable() == 0 {
+		return nil
+	}
+	if _, err := w.buf.WriteTo(w.w); err != nil {
+		return err
+	}
+	if w.buf.Readable() > 0 {
+		return io.ErrShortWrite
+	}
+	w.buf.Reset()
+	return nil
+}
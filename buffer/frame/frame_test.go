@@ -0,0 +1,111 @@
+package frame
+
+import (
+	std_bytes "bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/godyy/gutils/buffer"
+)
+
+var headers = []Header{HeaderUvarint, HeaderBigUint16, HeaderLitUint16, HeaderBigUint32, HeaderLitUint32}
+
+func TestFrame_RoundTrip(t *testing.T) {
+	frames := [][]byte{
+		[]byte("hello"),
+		{},
+		[]byte(strings.Repeat("x", 300)),
+		[]byte("world"),
+	}
+
+	for _, h := range headers {
+		var conn countingWriter
+		w := NewWriter(&conn, h, 1024)
+		for _, f := range frames {
+			if err := w.WriteFrame(f); err != nil {
+				t.Fatalf("header %d: write frame: %v", h, err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatalf("header %d: flush: %v", h, err)
+		}
+		if conn.writes != 1 {
+			t.Fatalf("header %d: expected 1 write, got %d", h, conn.writes)
+		}
+
+		r := NewReader(iotest.OneByteReader(&conn.buf), h, 1024)
+		for i, f := range frames {
+			p, err := r.ReadFrame()
+			if err != nil {
+				t.Fatalf("header %d: read frame %d: %v", h, i, err)
+			}
+			if !std_bytes.Equal(p, f) {
+				t.Fatalf("header %d: frame %d mismatch", h, i)
+			}
+		}
+		if _, err := r.ReadFrame(); err != io.EOF {
+			t.Fatalf("header %d: expected io.EOF, got %v", h, err)
+		}
+	}
+}
+
+func TestFrame_Limit(t *testing.T) {
+	var conn countingWriter
+	w := NewWriter(&conn, HeaderBigUint16, 8)
+	if err := w.WriteFrame(make([]byte, 9)); err != buffer.ErrExceedBufferLimit {
+		t.Fatalf("write oversize frame: expected ErrExceedBufferLimit, got %v", err)
+	}
+
+	w = NewWriter(&conn, HeaderBigUint16, 16)
+	_ = w.WriteFrame(make([]byte, 16))
+	_ = w.Flush()
+	r := NewReader(&conn.buf, HeaderBigUint16, 8)
+	if _, err := r.ReadFrame(); err != buffer.ErrExceedBufferLimit {
+		t.Fatalf("read oversize frame: expected ErrExceedBufferLimit, got %v", err)
+	}
+}
+
+func TestFrame_Truncated(t *testing.T) {
+	var conn countingWriter
+	w := NewWriter(&conn, HeaderUvarint, 64)
+	_ = w.WriteFrame([]byte("truncated"))
+	_ = w.Flush()
+
+	data := conn.buf.Bytes()
+	r := NewReader(std_bytes.NewReader(data[:len(data)-1]), HeaderUvarint, 64)
+	if _, err := r.ReadFrame(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestReader_Allocs(t *testing.T) {
+	var conn countingWriter
+	w := NewWriter(&conn, HeaderLitUint32, 64)
+	_ = w.WriteFrame([]byte("allocation free"))
+	_ = w.Flush()
+
+	src := std_bytes.NewReader(conn.buf.Bytes())
+	r := NewReader(src, HeaderLitUint32, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		src.Seek(0, io.SeekStart)
+		if _, err := r.ReadFrame(); err != nil {
+			t.Fatalf("read frame: %v", err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("expected 0 allocs per frame, got %v", allocs)
+	}
+}
+
+// countingWriter 记录 Write 调用次数
+type countingWriter struct {
+	buf    std_bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.buf.Write(p)
+}
@@ -0,0 +1,113 @@
+package buffer
+
+import "io"
+
+// Reader 有类型数据读取接口
+// 固定长度数据不足时返回 io.EOF(未读取任何数据) 或 io.ErrUnexpectedEOF.
+type Reader interface {
+	io.Reader
+	io.ByteReader
+
+	ReadInt8() (int8, error)
+	ReadUint8() (uint8, error)
+	ReadBool() (bool, error)
+
+	// 本地字节序
+	ReadInt16() (int16, error)
+	ReadUint16() (uint16, error)
+	ReadInt32() (int32, error)
+	ReadUint32() (uint32, error)
+	ReadInt64() (int64, error)
+	ReadUint64() (uint64, error)
+	ReadFloat32() (float32, error)
+	ReadFloat64() (float64, error)
+
+	// 大端字节序
+	ReadBigInt16() (int16, error)
+	ReadBigUint16() (uint16, error)
+	ReadBigInt32() (int32, error)
+	ReadBigUint32() (uint32, error)
+	ReadBigInt64() (int64, error)
+	ReadBigUint64() (uint64, error)
+	ReadBigFloat32() (float32, error)
+	ReadBigFloat64() (float64, error)
+
+	// 小端字节序
+	ReadLitInt16() (int16, error)
+	ReadLitUint16() (uint16, error)
+	ReadLitInt32() (int32, error)
+	ReadLitUint32() (uint32, error)
+	ReadLitInt64() (int64, error)
+	ReadLitUint64() (uint64, error)
+	ReadLitFloat32() (float32, error)
+	ReadLitFloat64() (float64, error)
+
+	// varint
+	ReadVarint16() (int16, error)
+	ReadUvarint16() (uint16, error)
+	ReadVarint32() (int32, error)
+	ReadUvarint32() (uint32, error)
+	ReadVarint64() (int64, error)
+	ReadUvarint64() (uint64, error)
+
+	// ReadString 读取以 varint 长度为前缀的字符串
+	ReadString() (string, error)
+}
+
+// Writer 有类型数据写入接口
+// varint 写入方法返回写入的字节数.
+type Writer interface {
+	io.Writer
+	io.ByteWriter
+
+	WriteInt8(int8) error
+	WriteUint8(uint8) error
+	WriteBool(bool) error
+
+	// 本地字节序
+	WriteInt16(int16) error
+	WriteUint16(uint16) error
+	WriteInt32(int32) error
+	WriteUint32(uint32) error
+	WriteInt64(int64) error
+	WriteUint64(uint64) error
+	WriteFloat32(float32) error
+	WriteFloat64(float64) error
+
+	// 大端字节序
+	WriteBigInt16(int16) error
+	WriteBigUint16(uint16) error
+	WriteBigInt32(int32) error
+	WriteBigUint32(uint32) error
+	WriteBigInt64(int64) error
+	WriteBigUint64(uint64) error
+	WriteBigFloat32(float32) error
+	WriteBigFloat64(float64) error
+
+	// 小端字节序
+	WriteLitInt16(int16) error
+	WriteLitUint16(uint16) error
+	WriteLitInt32(int32) error
+	WriteLitUint32(uint32) error
+	WriteLitInt64(int64) error
+	WriteLitUint64(uint64) error
+	WriteLitFloat32(float32) error
+	WriteLitFloat64(float64) error
+
+	// varint
+	WriteVarint16(int16) (int, error)
+	WriteUvarint16(uint16) (int, error)
+	WriteVarint32(int32) (int, error)
+	WriteUvarint32(uint32) (int, error)
+	WriteVarint64(int64) (int, error)
+	WriteUvarint64(uint64) (int, error)
+
+	// WriteString 写入以 varint 长度为前缀的字符串
+	WriteString(string) error
+}
+
+// ReadWriter 组合 Reader 与 Writer
+type ReadWriter interface {
+	Reader
+	Writer
+}
@@ -0,0 +1,266 @@
+package wire
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+
+	"github.com/godyy/gutils/buffer/bytes"
+)
+
+// Reader 自 bytes.Buffer 中读取 protobuf 二进制格式的字段
+// 读取出错时不移动读取位置. 字段值不完整时返回 io.ErrUnexpectedEOF.
+type Reader struct {
+	b *bytes.Buffer
+}
+
+// NewReader 创建读取 b 的 Reader
+func NewReader(b *bytes.Buffer) *Reader {
+	if b == nil {
+		panic("wire.NewReader: b nil")
+	}
+	return &Reader{b: b}
+}
+
+// Buffer 获取底层的 bytes.Buffer
+func (r *Reader) Buffer() *bytes.Buffer {
+	return r.b
+}
+
+// ReadTag 读取字段 tag, 无剩余数据时返回 io.EOF
+func (r *Reader) ReadTag() (Number, Type, error) {
+	p := r.b.UnreadData()
+	if len(p) == 0 {
+		return 0, 0, io.EOF
+	}
+	tag, n, err := consumeVarint(p)
+	if err != nil {
+		return 0, 0, err
+	}
+	num, typ := DecodeTag(tag)
+	if !num.IsValid() {
+		return 0, 0, ErrInvalidNumber
+	}
+	if !typ.IsValid() {
+		return 0, 0, ErrInvalidType
+	}
+	_, _ = r.b.Skip(n)
+	return num, typ, nil
+}
+
+// ReadVarint 读取 varint 字段值
+func (r *Reader) ReadVarint() (uint64, error) {
+	v, n, err := consumeVarint(r.b.UnreadData())
+	if err != nil {
+		return 0, err
+	}
+	_, _ = r.b.Skip(n)
+	return v, nil
+}
+
+// ReadInt32 读取 int32 字段值
+func (r *Reader) ReadInt32() (int32, error) {
+	v, err := r.ReadVarint()
+	return int32(v), err
+}
+
+// ReadInt64 读取 int64 字段值
+func (r *Reader) ReadInt64() (int64, error) {
+	v, err := r.ReadVarint()
+	return int64(v), err
+}
+
+// ReadUint32 读取 uint32 字段值
+func (r *Reader) ReadUint32() (uint32, error) {
+	v, err := r.ReadVarint()
+	return uint32(v), err
+}
+
+// ReadUint64 读取 uint64 字段值
+func (r *Reader) ReadUint64() (uint64, error) {
+	return r.ReadVarint()
+}
+
+// ReadSint32 读取 zigzag 编码的 sint32 字段值
+func (r *Reader) ReadSint32() (int32, error) {
+	v, err := r.ReadVarint()
+	return int32(DecodeZigZag(v & math.MaxUint32)), err
+}
+
+// ReadSint64 读取 zigzag 编码的 sint64 字段值
+func (r *Reader) ReadSint64() (int64, error) {
+	v, err := r.ReadVarint()
+	return DecodeZigZag(v), err
+}
+
+// ReadBool 读取 bool 字段值
+func (r *Reader) ReadBool() (bool, error) {
+	v, err := r.ReadVarint()
+	return v != 0, err
+}
+
+// ReadFixed32 读取 fixed32 字段值
+func (r *Reader) ReadFixed32() (uint32, error) {
+	p := r.b.UnreadData()
+	if len(p) < 4 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	_, _ = r.b.Skip(4)
+	return binary.LittleEndian.Uint32(p), nil
+}
+
+// ReadFixed64 读取 fixed64 字段值
+func (r *Reader) ReadFixed64() (uint64, error) {
+	p := r.b.UnreadData()
+	if len(p) < 8 {
+		return 0, io.ErrUnexpectedEOF
+	}
+	_, _ = r.b.Skip(8)
+	return binary.LittleEndian.Uint64(p), nil
+}
+
+// ReadSfixed32 读取 sfixed32 字段值
+func (r *Reader) ReadSfixed32() (int32, error) {
+	v, err := r.ReadFixed32()
+	return int32(v), err
+}
+
+// ReadSfixed64 读取 sfixed64 字段值
+func (r *Reader) ReadSfixed64() (int64, error) {
+	v, err := r.ReadFixed64()
+	return int64(v), err
+}
+
+// ReadFloat 读取 float 字段值
+func (r *Reader) ReadFloat() (float32, error) {
+	v, err := r.ReadFixed32()
+	return math.Float32frombits(v), err
+}
+
+// ReadDouble 读取 double 字段值
+func (r *Reader) ReadDouble() (float64, error) {
+	v, err := r.ReadFixed64()
+	return math.Float64frombits(v), err
+}
+
+// ReadBytesNoCopy 读取 bytes 字段值, 返回的切片引用底层缓冲区, 仅在下一次写入前有效
+func (r *Reader) ReadBytesNoCopy() ([]byte, error) {
+	p := r.b.UnreadData()
+	l, n, err := consumeVarint(p)
+	if err != nil {
+		return nil, err
+	}
+	if l > uint64(len(p)-n) {
+		return nil, io.ErrUnexpectedEOF
+	}
+	end := n + int(l)
+	_, _ = r.b.Skip(end)
+	return p[n:end:end], nil
+}
+
+// ReadBytes 读取 bytes 字段值, 返回数据的副本, 长度为 0 时返回 nil
+func (r *Reader) ReadBytes() ([]byte, error) {
+	p, err := r.ReadBytesNoCopy()
+	if err != nil || len(p) == 0 {
+		return nil, err
+	}
+	return append([]byte(nil), p...), nil
+}
+
+// ReadString 读取 string 字段值
+func (r *Reader) ReadString() (string, error) {
+	p, err := r.ReadBytesNoCopy()
+	return string(p), err
+}
+
+// ReadMessage 读取嵌套消息或 packed repeated 字段, 返回读取其内容的 Reader
+// 返回的 Reader 引用底层缓冲区, 仅在下一次写入前有效.
+func (r *Reader) ReadMessage() (*Reader, error) {
+	p, err := r.ReadBytesNoCopy()
+	if err != nil {
+		return nil, err
+	}
+	return NewReader(bytes.NewBuffer(p)), nil
+}
+
+// SkipField 跳过 ReadTag 读取的字段 num 的值, 用于忽略未知字段
+// 对于 TypeStartGroup, 跳过直至匹配的 TypeEndGroup.
+func (r *Reader) SkipField(num Number, typ Type) error {
+	n, err := consumeFieldValue(r.b.UnreadData(), num, typ, 0)
+	if err != nil {
+		return err
+	}
+	_, _ = r.b.Skip(n)
+	return nil
+}
+
+// consumeVarint 自 p 中解析 varint, 返回值及占用的字节数
+func consumeVarint(p []byte) (uint64, int, error) {
+	v, n := binary.Uvarint(p)
+	if n == 0 {
+		return 0, 0, io.ErrUnexpectedEOF
+	}
+	if n < 0 {
+		return 0, 0, bytes.ErrVarintOverflow
+	}
+	return v, n, nil
+}
+
+// consumeFieldValue 获取 p 起始处类型为 typ 的字段值占用的字节数
+func consumeFieldValue(p []byte, num Number, typ Type, depth int) (int, error) {
+	switch typ {
+	case TypeVarint:
+		_, n, err := consumeVarint(p)
+		return n, err
+	case TypeFixed32:
+		if len(p) < 4 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return 4, nil
+	case TypeFixed64:
+		if len(p) < 8 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return 8, nil
+	case TypeBytes:
+		l, n, err := consumeVarint(p)
+		if err != nil {
+			return 0, err
+		}
+		if l > uint64(len(p)-n) {
+			return 0, io.ErrUnexpectedEOF
+		}
+		return n + int(l), nil
+	case TypeStartGroup:
+		if depth >= MaxGroupDepth {
+			return 0, ErrGroupTooDeep
+		}
+		off := 0
+		for {
+			tag, n, err := consumeVarint(p[off:])
+			if err != nil {
+				return 0, err
+			}
+			off += n
+			fnum, ftyp := DecodeTag(tag)
+			if !fnum.IsValid() {
+				return 0, ErrInvalidNumber
+			}
+			if ftyp == TypeEndGroup {
+				if fnum != num {
+					return 0, ErrMismatchedGroup
+				}
+				return off, nil
+			}
+			n, err = consumeFieldValue(p[off:], fnum, ftyp, depth+1)
+			if err != nil {
+				return 0, err
+			}
+			off += n
+		}
+	case TypeEndGroup:
+		return 0, ErrMismatchedGroup
+	default:
+		return 0, ErrInvalidType
+	}
+}
@@ -0,0 +1,86 @@
+// Package wire 在 bytes.Buffer 之上实现与 protobuf 二进制格式(wire format)兼容的
+// 字段编解码, 用于在不引入 protobuf 运行时的情况下, 手工编写与 protobuf 对端互通
+// 的简单消息.
+//
+// 每个字段由 tag 及值组成, tag 为 uvarint 编码的 number<<3 | type:
+//
+//	TypeVarint     int32, int64, uint32, uint64, sint32, sint64, bool, enum
+//	TypeFixed64    fixed64, sfixed64, double
+//	TypeBytes      string, bytes, 嵌套消息, packed repeated 字段
+//	TypeStartGroup 已废弃的 group 起始
+//	TypeEndGroup   已废弃的 group 结束
+//	TypeFixed32    fixed32, sfixed32, float
+package wire
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrInvalidNumber 无效的字段编号
+var ErrInvalidNumber = errors.New("wire: invalid field number")
+
+// ErrInvalidType 无效的字段类型
+var ErrInvalidType = errors.New("wire: invalid wire type")
+
+// ErrMismatchedGroup group 的起始与结束不匹配
+var ErrMismatchedGroup = errors.New("wire: mismatched end group")
+
+// ErrGroupTooDeep group 嵌套层数超过 MaxGroupDepth
+var ErrGroupTooDeep = errors.New("wire: group nesting too deep")
+
+// Number 字段编号
+type Number int32
+
+const (
+	MinValidNumber Number = 1         // 最小的有效字段编号
+	MaxValidNumber Number = 1<<29 - 1 // 最大的有效字段编号
+)
+
+// IsValid 字段编号是否有效
+func (n Number) IsValid() bool {
+	return n >= MinValidNumber && n <= MaxValidNumber
+}
+
+// Type 字段类型
+type Type int8
+
+const (
+	TypeVarint     Type = 0 // varint
+	TypeFixed64    Type = 1 // 小端 8 字节
+	TypeBytes      Type = 2 // uvarint 长度前缀的数据
+	TypeStartGroup Type = 3 // group 起始
+	TypeEndGroup   Type = 4 // group 结束
+	TypeFixed32    Type = 5 // 小端 4 字节
+)
+
+// IsValid 字段类型是否有效
+func (t Type) IsValid() bool {
+	return t >= TypeVarint && t <= TypeFixed32
+}
+
+// MaxGroupDepth 跳过字段时 group 的最大嵌套层数
+const MaxGroupDepth = 100
+
+// EncodeTag 将字段编号及类型编码为 tag
+func EncodeTag(num Number, typ Type) uint64 {
+	return uint64(num)<<3 | uint64(typ&7)
+}
+
+// DecodeTag 将 tag 解码为字段编号及类型, 编号超出 int32 范围时返回 -1
+func DecodeTag(tag uint64) (Number, Type) {
+	if tag>>3 > math.MaxInt32 {
+		return -1, 0
+	}
+	return Number(tag >> 3), Type(tag & 7)
+}
+
+// EncodeZigZag 将有符号整数以 zigzag 方式编码, 用于 sint32/sint64
+func EncodeZigZag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+// DecodeZigZag 解码 zigzag 编码的有符号整数
+func DecodeZigZag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
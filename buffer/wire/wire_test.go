@@ -0,0 +1,207 @@
+package wire
+
+import (
+	std_bytes "bytes"
+	"encoding/hex"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/godyy/gutils/buffer/bytes"
+)
+
+// 期望的编码结果与 protoc 生成代码的输出一致
+func TestWriter_Encoding(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(w *Writer) error
+		want  string
+	}{
+		{"uint32", func(w *Writer) error { return w.WriteUint32(1, 150) }, "089601"},
+		{"int32 negative", func(w *Writer) error { return w.WriteInt32(1, -1) }, "08ffffffffffffffffff01"},
+		{"sint32", func(w *Writer) error { return w.WriteSint32(1, -1) }, "0801"},
+		{"sint64", func(w *Writer) error { return w.WriteSint64(2, 1) }, "1002"},
+		{"bool", func(w *Writer) error { return w.WriteBool(3, true) }, "1801"},
+		{"string", func(w *Writer) error { return w.WriteString(2, "testing") }, "120774657374696e67"},
+		{"bytes empty", func(w *Writer) error { return w.WriteBytes(2, nil) }, "1200"},
+		{"fixed32", func(w *Writer) error { return w.WriteFixed32(5, 1) }, "2d01000000"},
+		{"sfixed64", func(w *Writer) error { return w.WriteSfixed64(1, -2) }, "09feffffffffffffff"},
+		{"float", func(w *Writer) error { return w.WriteFloat(1, 1.5) }, "0d0000c03f"},
+		{"double", func(w *Writer) error { return w.WriteDouble(1, 1) }, "09000000000000f03f"},
+		{"large number", func(w *Writer) error { return w.WriteUint64(MaxValidNumber, 0) }, "f8ffffff0f00"},
+	}
+	for _, tt := range tests {
+		b := bytes.NewBuffer(nil)
+		if err := tt.write(NewWriter(b)); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := hex.EncodeToString(b.Data()); got != tt.want {
+			t.Fatalf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+
+	// 写入字符串时不复制
+	s := string(std_bytes.Repeat([]byte("x"), 1024))
+	b := bytes.NewBufferWithCap(64 << 10)
+	w := NewWriter(b)
+	if n := testing.AllocsPerRun(10, func() { _ = w.WriteString(1, s) }); n != 0 {
+		t.Fatalf("WriteString: %v allocs", n)
+	}
+
+	w = NewWriter(bytes.NewBuffer(nil))
+	if err := w.WriteTag(0, TypeVarint); err != ErrInvalidNumber {
+		t.Fatalf("WriteTag: expected ErrInvalidNumber, got %v", err)
+	}
+	if err := w.WriteTag(1, 6); err != ErrInvalidType {
+		t.Fatalf("WriteTag: expected ErrInvalidType, got %v", err)
+	}
+}
+
+func TestReadWrite_Message(t *testing.T) {
+	b := bytes.NewBuffer(nil)
+	w := NewWriter(b)
+	_ = w.WriteInt64(1, -7)
+	_ = w.WriteSint32(2, math.MinInt32)
+	h, err := w.BeginMessage(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = w.WriteString(1, "inner")
+	_ = w.WriteSfixed32(2, -3)
+	if err := h.Finish(); err != nil {
+		t.Fatal(err)
+	}
+	packed, _ := w.BeginMessage(4)
+	for _, v := range []uint64{1, 300, 1 << 40} {
+		_ = w.WriteRawVarint(v)
+	}
+	_ = packed.Finish()
+	_ = w.WriteDouble(5, -0.5)
+
+	r := NewReader(b)
+	expectTag := func(num Number, typ Type) {
+		t.Helper()
+		n, ty, err := r.ReadTag()
+		if err != nil || n != num || ty != typ {
+			t.Fatalf("ReadTag: %d, %d, %v, want %d, %d", n, ty, err, num, typ)
+		}
+	}
+
+	expectTag(1, TypeVarint)
+	if v, err := r.ReadInt64(); err != nil || v != -7 {
+		t.Fatalf("ReadInt64: %d, %v", v, err)
+	}
+	expectTag(2, TypeVarint)
+	if v, err := r.ReadSint32(); err != nil || v != math.MinInt32 {
+		t.Fatalf("ReadSint32: %d, %v", v, err)
+	}
+
+	expectTag(3, TypeBytes)
+	m, err := r.ReadMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _, _ := m.ReadTag(); n != 1 {
+		t.Fatalf("inner tag: %d", n)
+	}
+	if s, err := m.ReadString(); err != nil || s != "inner" {
+		t.Fatalf("inner ReadString: %q, %v", s, err)
+	}
+	_, _, _ = m.ReadTag()
+	if v, err := m.ReadSfixed32(); err != nil || v != -3 {
+		t.Fatalf("inner ReadSfixed32: %d, %v", v, err)
+	}
+	if _, _, err := m.ReadTag(); err != io.EOF {
+		t.Fatalf("inner end: expected io.EOF, got %v", err)
+	}
+
+	expectTag(4, TypeBytes)
+	pr, _ := r.ReadMessage()
+	var got []uint64
+	for pr.Buffer().Readable() > 0 {
+		v, err := pr.ReadVarint()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, v)
+	}
+	if len(got) != 3 || got[1] != 300 || got[2] != 1<<40 {
+		t.Fatalf("packed: %v", got)
+	}
+
+	expectTag(5, TypeFixed64)
+	if v, err := r.ReadDouble(); err != nil || v != -0.5 {
+		t.Fatalf("ReadDouble: %v, %v", v, err)
+	}
+	if _, _, err := r.ReadTag(); err != io.EOF {
+		t.Fatalf("end: expected io.EOF, got %v", err)
+	}
+}
+
+func TestReader_SkipField(t *testing.T) {
+	// 1: varint, 2: fixed32, 3: group{4: bytes, 5: group{}}, 6: fixed64, 7: string
+	data, _ := hex.DecodeString("08ac02" + "1501020304" + "1b" + "22026869" + "2b2c" + "1c" + "310102030405060708" + "3a026f6b")
+	r := NewReader(bytes.NewBuffer(data))
+	for {
+		num, typ, err := r.ReadTag()
+		if err != nil {
+			t.Fatalf("ReadTag: %v", err)
+		}
+		if num == 7 {
+			if s, err := r.ReadString(); err != nil || s != "ok" {
+				t.Fatalf("ReadString: %q, %v", s, err)
+			}
+			break
+		}
+		if err := r.SkipField(num, typ); err != nil {
+			t.Fatalf("SkipField %d: %v", num, err)
+		}
+	}
+
+	bad := map[string]struct {
+		data string
+		err  error
+	}{
+		"truncated varint": {"0880", io.ErrUnexpectedEOF},
+		"truncated bytes":  {"0a0568", io.ErrUnexpectedEOF},
+		"truncated fixed":  {"0d0102", io.ErrUnexpectedEOF},
+		"mismatched group": {"0b14", ErrMismatchedGroup},
+		"unclosed group":   {"0b0801", io.ErrUnexpectedEOF},
+		"overflow":         {"08ffffffffffffffffff7f", bytes.ErrVarintOverflow},
+	}
+	for name, tt := range bad {
+		data, _ := hex.DecodeString(tt.data)
+		b := bytes.NewBuffer(data)
+		r := NewReader(b)
+		num, typ, _ := r.ReadTag()
+		before := b.Readable()
+		if err := r.SkipField(num, typ); err != tt.err {
+			t.Fatalf("%s: expected %v, got %v", name, tt.err, err)
+		}
+		if b.Readable() != before {
+			t.Fatalf("%s: SkipField must not consume data on error", name)
+		}
+	}
+
+	deep := std_bytes.Repeat([]byte{0x0b}, MaxGroupDepth+2)
+	r = NewReader(bytes.NewBuffer(deep))
+	num, typ, _ := r.ReadTag()
+	if err := r.SkipField(num, typ); err != ErrGroupTooDeep {
+		t.Fatalf("deep group: expected ErrGroupTooDeep, got %v", err)
+	}
+}
+
+func TestReader_InvalidTag(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"zero number":  {0x00},
+		"invalid type": {0x0e},
+	} {
+		b := bytes.NewBuffer(data)
+		if _, _, err := NewReader(b).ReadTag(); err == nil {
+			t.Fatalf("%s: expected error", name)
+		}
+		if b.Readable() != len(data) {
+			t.Fatalf("%s: ReadTag must not consume data on error", name)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package wire
+
+import (
+	"math"
+	"unsafe"
+
+	"github.com/godyy/gutils/buffer/bytes"
+)
+
+// Writer 将字段以 protobuf 二进制格式写入 bytes.Buffer
+type Writer struct {
+	b *bytes.Buffer
+}
+
+// NewWriter 创建写入 b 的 Writer
+func NewWriter(b *bytes.Buffer) *Writer {
+	if b == nil {
+		panic("wire.NewWriter: b nil")
+	}
+	return &Writer{b: b}
+}
+
+// Buffer 获取底层的 bytes.Buffer
+func (w *Writer) Buffer() *bytes.Buffer {
+	return w.b
+}
+
+// WriteTag 写入字段 tag
+func (w *Writer) WriteTag(num Number, typ Type) error {
+	if !num.IsValid() {
+		return ErrInvalidNumber
+	}
+	if !typ.IsValid() {
+		return ErrInvalidType
+	}
+	_, err := w.b.WriteUvarint64(EncodeTag(num, typ))
+	return err
+}
+
+// WriteVarint 写入 varint 类型的字段
+func (w *Writer) WriteVarint(num Number, v uint64) error {
+	if err := w.WriteTag(num, TypeVarint); err != nil {
+		return err
+	}
+	return w.WriteRawVarint(v)
+}
+
+// WriteInt32 写入 int32 字段, 负数按 int64 符号扩展, 占用 10 字节
+func (w *Writer) WriteInt32(num Number, v int32) error {
+	return w.WriteVarint(num, uint64(int64(v)))
+}
+
+// WriteInt64 写入 int64 字段
+func (w *Writer) WriteInt64(num Number, v int64) error {
+	return w.WriteVarint(num, uint64(v))
+}
+
+// WriteUint32 写入 uint32 字段
+func (w *Writer) WriteUint32(num Number, v uint32) error {
+	return w.WriteVarint(num, uint64(v))
+}
+
+// WriteUint64 写入 uint64 字段
+func (w *Writer) WriteUint64(num Number, v uint64) error {
+	return w.WriteVarint(num, v)
+}
+
+// WriteSint32 写入 zigzag 编码的 sint32 字段
+func (w *Writer) WriteSint32(num Number, v int32) error {
+	return w.WriteVarint(num, EncodeZigZag(int64(v)))
+}
+
+// WriteSint64 写入 zigzag 编码的 sint64 字段
+func (w *Writer) WriteSint64(num Number, v int64) error {
+	return w.WriteVarint(num, EncodeZigZag(v))
+}
+
+// WriteBool 写入 bool 字段
+func (w *Writer) WriteBool(num Number, v bool) error {
+	var i uint64
+	if v {
+		i = 1
+	}
+	return w.WriteVarint(num, i)
+}
+
+// WriteFixed32 写入 fixed32 字段
+func (w *Writer) WriteFixed32(num Number, v uint32) error {
+	if err := w.WriteTag(num, TypeFixed32); err != nil {
+		return err
+	}
+	return w.b.WriteLitUint32(v)
+}
+
+// WriteFixed64 写入 fixed64 字段
+func (w *Writer) WriteFixed64(num Number, v uint64) error {
+	if err := w.WriteTag(num, TypeFixed64); err != nil {
+		return err
+	}
+	return w.b.WriteLitUint64(v)
+}
+
+// WriteSfixed32 写入 sfixed32 字段
+func (w *Writer) WriteSfixed32(num Number, v int32) error {
+	return w.WriteFixed32(num, uint32(v))
+}
+
+// WriteSfixed64 写入 sfixed64 字段
+func (w *Writer) WriteSfixed64(num Number, v int64) error {
+	return w.WriteFixed64(num, uint64(v))
+}
+
+// WriteFloat 写入 float 字段
+func (w *Writer) WriteFloat(num Number, v float32) error {
+	return w.WriteFixed32(num, math.Float32bits(v))
+}
+
+// WriteDouble 写入 double 字段
+func (w *Writer) WriteDouble(num Number, v float64) error {
+	return w.WriteFixed64(num, math.Float64bits(v))
+}
+
+// WriteBytes 写入 bytes 字段
+func (w *Writer) WriteBytes(num Number, p []byte) error {
+	if err := w.WriteTag(num, TypeBytes); err != nil {
+		return err
+	}
+	if err := w.WriteRawVarint(uint64(len(p))); err != nil {
+		return err
+	}
+	_, err := w.b.Write(p)
+	return err
+}
+
+// WriteString 写入 string 字段
+func (w *Writer) WriteString(num Number, s string) error {
+	if err := w.WriteTag(num, TypeBytes); err != nil {
+		return err
+	}
+	if err := w.WriteRawVarint(uint64(len(s))); err != nil {
+		return err
+	}
+	// Write 仅复制 p 的内容, 因此可直接引用字符串数据, 不依赖编译器的逃逸分析避免复制
+	_, err := w.b.Write(unsafe.Slice(unsafe.StringData(s), len(s)))
+	return err
+}
+
+// BeginMessage 写入嵌套消息或 packed repeated 字段的 tag, 并预留长度前缀
+// 写入字段内容后须调用返回句柄的 Finish 回填长度. 长度前缀以定宽 5 字节的 uvarint
+// 编码, protobuf 解码器可正确解析.
+func (w *Writer) BeginMessage(num Number) (bytes.LengthHandle, error) {
+	if err := w.WriteTag(num, TypeBytes); err != nil {
+		return bytes.LengthHandle{}, err
+	}
+	return w.b.ReserveLength(bytes.LengthUvarint)
+}
+
+// WriteRawVarint 写入不带 tag 的 varint, 用于 packed repeated 字段
+func (w *Writer) WriteRawVarint(v uint64) error {
+	_, err := w.b.WriteUvarint64(v)
+	return err
+}
+
+// WriteRawFixed32 写入不带 tag 的小端 4 字节, 用于 packed repeated 字段
+func (w *Writer) WriteRawFixed32(v uint32) error {
+	return w.b.WriteLitUint32(v)
+}
+
+// WriteRawFixed64 写入不带 tag 的小端 8 字节, 用于 packed repeated 字段
+func (w *Writer) WriteRawFixed64(v uint64) error {
+	return w.b.WriteLitUint64(v)
+}
@@ -0,0 +1,745 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// tagName 字段标签名, 与 bytes.TagName 一致
+const tagName = "buf"
+
+// encoding 标量编码方式
+type encoding int8
+
+const (
+	encNative encoding = iota // 本地字节序
+	encBig                    // 大端字节序
+	encLit                    // 小端字节序
+	encVarint                 // varint
+)
+
+// parseEncoding 解析 buf 标签, 规则与 bytes.TagName 一致
+func parseEncoding(tag string) (e encoding, skip bool, err error) {
+	switch strings.TrimSpace(tag) {
+	case "":
+		return encNative, false, nil
+	case "big":
+		return encBig, false, nil
+	case "lit":
+		return encLit, false, nil
+	case "varint":
+		return encVarint, false, nil
+	case "-":
+		return 0, true, nil
+	default:
+		return 0, false, fmt.Errorf("invalid buf tag %q", tag)
+	}
+}
+
+// kind 类型分类
+type kind int8
+
+const (
+	kindBasic kind = iota
+	kindBytes
+	kindSlice
+	kindArray
+	kindMap
+	kindPointer
+	kindStruct
+)
+
+// typeInfo 字段类型信息
+type typeInfo struct {
+	kind  kind
+	name  string    // 源码中的类型表达式
+	basic string    // kindBasic 的底层基础类型
+	elem  *typeInfo // 切片、数组、map、指针的元素类型
+	key   *typeInfo // map 的键类型
+}
+
+// fieldInfo 结构体字段信息
+type fieldInfo struct {
+	name string
+	typ  *typeInfo
+	enc  encoding
+}
+
+// structInfo 结构体信息
+type structInfo struct {
+	name   string
+	fields []fieldInfo
+}
+
+// basicTypes 支持的基础类型及其规范名称
+var basicTypes = map[string]string{
+	"bool":    "bool",
+	"int8":    "int8",
+	"uint8":   "uint8",
+	"byte":    "uint8",
+	"int16":   "int16",
+	"uint16":  "uint16",
+	"int32":   "int32",
+	"rune":    "int32",
+	"uint32":  "uint32",
+	"int64":   "int64",
+	"uint64":  "uint64",
+	"int":     "int",
+	"uint":    "uint",
+	"float32": "float32",
+	"float64": "float64",
+	"string":  "string",
+}
+
+// generator 代码生成器
+type generator struct {
+	pkgName string
+	specs   map[string]ast.Expr    // 包中声明的类型
+	structs []*structInfo          // 待生成的结构体
+	seen    map[string]*structInfo // 已解析的结构体
+}
+
+// Generate 解析 dir 中的包, 为 types 及其引用的结构体生成编解码代码与测试代码
+func Generate(dir string, types []string) (code, test []byte, err error) {
+	g, err := newGenerator(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, name := range types {
+		name = strings.TrimSpace(name)
+		expr, ok := g.specs[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("type %s not found in %s", name, dir)
+		}
+		if _, ok := expr.(*ast.StructType); !ok {
+			return nil, nil, fmt.Errorf("type %s is not a struct", name)
+		}
+		if _, err := g.addStruct(name); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if code, err = g.generateCode(); err != nil {
+		return nil, nil, err
+	}
+	if test, err = g.generateTest(); err != nil {
+		return nil, nil, err
+	}
+	return code, test, nil
+}
+
+// newGenerator 解析 dir 中的包并构造生成器
+func newGenerator(dir string) (*generator, error) {
+	fset := token.NewFileSet()
+	filter := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}
+	pkgs, err := parser.ParseDir(fset, dir, filter, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected exactly one package in %s, found %d", dir, len(pkgs))
+	}
+
+	g := &generator{
+		specs: make(map[string]ast.Expr),
+		seen:  make(map[string]*structInfo),
+	}
+	for name, pkg := range pkgs {
+		g.pkgName = name
+		for _, f := range pkg.Files {
+			for _, decl := range f.Decls {
+				gd, ok := decl.(*ast.GenDecl)
+				if !ok || gd.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range gd.Specs {
+					ts := spec.(*ast.TypeSpec)
+					if ts.TypeParams != nil {
+						continue
+					}
+					g.specs[ts.Name.Name] = ts.Type
+				}
+			}
+		}
+	}
+	return g, nil
+}
+
+// addStruct 解析结构体并加入待生成列表
+func (g *generator) addStruct(name string) (*structInfo, error) {
+	if si, ok := g.seen[name]; ok {
+		return si, nil
+	}
+
+	si := &structInfo{name: name}
+	g.seen[name] = si
+	g.structs = append(g.structs, si)
+
+	st := g.specs[name].(*ast.StructType)
+	for _, field := range st.Fields.List {
+		var names []string
+		if len(field.Names) == 0 {
+			// 嵌入字段以类型名作为字段名
+			te := field.Type
+			if star, ok := te.(*ast.StarExpr); ok {
+				te = star.X
+			}
+			ident, ok := te.(*ast.Ident)
+			if !ok {
+				return nil, fmt.Errorf("%s: unsupported embedded field", name)
+			}
+			names = []string{ident.Name}
+		} else {
+			for _, n := range field.Names {
+				names = append(names, n.Name)
+			}
+		}
+
+		var tag string
+		if field.Tag != nil {
+			raw, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid struct tag %s", name, field.Tag.Value)
+			}
+			tag = reflect.StructTag(raw).Get(tagName)
+		}
+
+		for _, fname := range names {
+			if !ast.IsExported(fname) {
+				continue
+			}
+
+			enc, skip, err := parseEncoding(tag)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", name, fname, err)
+			}
+			if skip {
+				continue
+			}
+
+			ti, err := g.resolve(field.Type)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", name, fname, err)
+			}
+			if err := checkEncoding(ti, enc); err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", name, fname, err)
+			}
+			si.fields = append(si.fields, fieldInfo{name: fname, typ: ti, enc: enc})
+		}
+	}
+	return si, nil
+}
+
+// resolve 解析类型表达式
+func (g *generator) resolve(expr ast.Expr) (*typeInfo, error) {
+	name := exprString(expr)
+	switch t := expr.(type) {
+	case *ast.Ident:
+		if basic, ok := basicTypes[t.Name]; ok {
+			return &typeInfo{kind: kindBasic, name: name, basic: basic}, nil
+		}
+		underlying, ok := g.specs[t.Name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported type %s", name)
+		}
+		if _, ok := underlying.(*ast.StructType); ok {
+			if _, err := g.addStruct(t.Name); err != nil {
+				return nil, err
+			}
+			return &typeInfo{kind: kindStruct, name: name}, nil
+		}
+		ti, err := g.resolve(underlying)
+		if err != nil {
+			return nil, err
+		}
+		named := *ti
+		named.name = name
+		return &named, nil
+
+	case *ast.ArrayType:
+		elem, err := g.resolve(t.Elt)
+		if err != nil {
+			return nil, err
+		}
+		if t.Len == nil {
+			if elem.name == "byte" || elem.name == "uint8" {
+				return &typeInfo{kind: kindBytes, name: name, elem: elem}, nil
+			}
+			return &typeInfo{kind: kindSlice, name: name, elem: elem}, nil
+		}
+		return &typeInfo{kind: kindArray, name: name, elem: elem}, nil
+
+	case *ast.MapType:
+		key, err := g.resolve(t.Key)
+		if err != nil {
+			return nil, err
+		}
+		if key.kind != kindBasic || key.basic == "bool" {
+			return nil, fmt.Errorf("unsupported map key type %s", key.name)
+		}
+		elem, err := g.resolve(t.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &typeInfo{kind: kindMap, name: name, key: key, elem: elem}, nil
+
+	case *ast.StarExpr:
+		elem, err := g.resolve(t.X)
+		if err != nil {
+			return nil, err
+		}
+		return &typeInfo{kind: kindPointer, name: name, elem: elem}, nil
+
+	case *ast.ParenExpr:
+		return g.resolve(t.X)
+
+	default:
+		return nil, fmt.Errorf("unsupported type %s", name)
+	}
+}
+
+// checkEncoding 检查编码方式是否适用于类型中的标量
+func checkEncoding(t *typeInfo, enc encoding) error {
+	switch t.kind {
+	case kindBasic:
+		if enc == encVarint && (t.basic == "float32" || t.basic == "float64") {
+			return fmt.Errorf("varint on %s", t.basic)
+		}
+		return nil
+	case kindSlice, kindArray, kindPointer:
+		return checkEncoding(t.elem, enc)
+	case kindMap:
+		if err := checkEncoding(t.key, enc); err != nil {
+			return err
+		}
+		return checkEncoding(t.elem, enc)
+	default:
+		return nil
+	}
+}
+
+// exprString 将类型表达式格式化为源码
+func exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	_ = format.Node(&buf, token.NewFileSet(), expr)
+	return buf.String()
+}
+
+// generateCode 生成编解码代码
+func (g *generator) generateCode() ([]byte, error) {
+	var body bytes.Buffer
+	usesSlices, usesBuffer := false, false
+	for _, si := range g.structs {
+		for _, variant := range []struct{ suffix, typ string }{
+			{"", "Buffer"},
+			{"Fixed", "FixedBuffer"},
+		} {
+			enc := &emitter{suffix: variant.suffix}
+			for _, f := range si.fields {
+				enc.encode("v."+f.name, f.typ, f.enc)
+			}
+			dec := &emitter{suffix: variant.suffix}
+			for _, f := range si.fields {
+				dec.decode("v."+f.name, f.typ, f.enc)
+			}
+			usesSlices = usesSlices || enc.usesSlices
+			usesBuffer = usesBuffer || enc.usesBuffer || dec.usesBuffer
+
+			fmt.Fprintf(&body, "\n// EncodeTo%s 将 %s 编码写入 b\n", variant.suffix, si.name)
+			fmt.Fprintf(&body, "func (v *%s) EncodeTo%s(b *bytes.%s) error {\n", si.name, variant.suffix, variant.typ)
+			body.WriteString(enc.String())
+			body.WriteString("return nil\n}\n")
+
+			fmt.Fprintf(&body, "\n// DecodeFrom%s 自 b 中解码 %s\n", variant.suffix, si.name)
+			fmt.Fprintf(&body, "func (v *%s) DecodeFrom%s(b *bytes.%s) (err error) {\n", si.name, variant.suffix, variant.typ)
+			body.WriteString("readable := b.Readable()\n")
+			body.WriteString("defer func() {\nif err == io.EOF && b.Readable() != readable {\nerr = io.ErrUnexpectedEOF\n}\n}()\n")
+			body.WriteString(dec.String())
+			body.WriteString("return nil\n}\n")
+		}
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by gutils-bufgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", g.pkgName)
+	out.WriteString("import (\n\"io\"\n")
+	if usesSlices {
+		out.WriteString("\"slices\"\n")
+	}
+	out.WriteString("\n")
+	if usesBuffer {
+		out.WriteString("\"github.com/godyy/gutils/buffer\"\n")
+	}
+	out.WriteString("\"github.com/godyy/gutils/buffer/bytes\"\n)\n")
+	out.Write(body.Bytes())
+
+	return formatSource(out.Bytes())
+}
+
+// generateTest 生成往返编解码测试代码
+func (g *generator) generateTest() ([]byte, error) {
+	var body bytes.Buffer
+	usesStrconv := false
+	for _, si := range g.structs {
+		fmt.Fprintf(&body, "\nfunc TestBufgen_%s(t *testing.T) {\n", si.name)
+		body.WriteString("r := rand.New(rand.NewSource(1))\n")
+		body.WriteString("for i := 0; i < 100; i++ {\n")
+		fmt.Fprintf(&body, "v := bufgenSample%s(r, 0)\n", si.name)
+		body.WriteString(`want, err := bytes.Marshal(&v)
+if err != nil {
+	t.Fatalf("marshal: %v", err)
+}
+
+b := bytes.NewBuffer(nil)
+if err := v.EncodeTo(b); err != nil {
+	t.Fatalf("encode: %v", err)
+}
+if string(b.UnreadData()) != string(want) {
+	t.Fatalf("encode data mismatch\n got: %v\nwant: %v", b.UnreadData(), want)
+}
+`)
+		fmt.Fprintf(&body, "var got %s\n", si.name)
+		body.WriteString(`if err := got.DecodeFrom(b); err != nil {
+	t.Fatalf("decode: %v", err)
+}
+if !reflect.DeepEqual(got, v) {
+	t.Fatalf("decode mismatch\n got: %+v\nwant: %+v", got, v)
+}
+
+fb := bytes.NewFixedBuffer(len(want) + 1)
+if err := v.EncodeToFixed(fb); err != nil {
+	t.Fatalf("encode fixed: %v", err)
+}
+if string(fb.UnreadData()) != string(want) {
+	t.Fatalf("encode fixed data mismatch\n got: %v\nwant: %v", fb.UnreadData(), want)
+}
+`)
+		fmt.Fprintf(&body, "var gotFixed %s\n", si.name)
+		body.WriteString(`if err := gotFixed.DecodeFromFixed(fb); err != nil {
+	t.Fatalf("decode fixed: %v", err)
+}
+if !reflect.DeepEqual(gotFixed, v) {
+	t.Fatalf("decode fixed mismatch\n got: %+v\nwant: %+v", gotFixed, v)
+}
+}
+}
+`)
+
+		s := &sampler{}
+		fmt.Fprintf(&body, "\nfunc bufgenSample%s(r *rand.Rand, depth int) (v %s) {\n", si.name, si.name)
+		for _, f := range si.fields {
+			fmt.Fprintf(&body, "v.%s = %s\n", f.name, s.sample(f.typ))
+		}
+		body.WriteString("return\n}\n")
+		usesStrconv = usesStrconv || s.usesStrconv
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by gutils-bufgen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&out, "package %s\n\n", g.pkgName)
+	out.WriteString("import (\n\"math/rand\"\n\"reflect\"\n")
+	if usesStrconv {
+		out.WriteString("\"strconv\"\n")
+	}
+	out.WriteString("\"testing\"\n\n\"github.com/godyy/gutils/buffer/bytes\"\n)\n")
+	out.Write(body.Bytes())
+
+	return formatSource(out.Bytes())
+}
+
+// formatSource 格式化生成的源码
+func formatSource(src []byte) ([]byte, error) {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("internal error: invalid generated code: %w", err), errors.New(string(src)))
+	}
+	return formatted, nil
+}
+
+// scalarMethod 返回基础类型以编码方式 enc 读写所用的方法名后缀, 写入时需转换的
+// 类型, 以及写入方法是否返回写入字节数
+func scalarMethod(basic string, enc encoding) (method, cast string, counted bool) {
+	switch basic {
+	case "bool":
+		return "Bool", "bool", false
+	case "int8":
+		return "Int8", "int8", false
+	case "uint8":
+		return "Uint8", "uint8", false
+	case "string":
+		return "String", "string", false
+	}
+
+	cast = basic
+	switch basic {
+	case "int":
+		cast = "int64"
+	case "uint":
+		cast = "uint64"
+	}
+	method = strings.ToUpper(cast[:1]) + cast[1:]
+
+	switch enc {
+	case encBig:
+		return "Big" + method, cast, false
+	case encLit:
+		return "Lit" + method, cast, false
+	case encVarint:
+		if strings.HasPrefix(cast, "uint") {
+			return "Uvarint" + strings.TrimPrefix(cast, "uint"), cast, true
+		}
+		return "Varint" + strings.TrimPrefix(cast, "int"), cast, true
+	default:
+		return method, cast, false
+	}
+}
+
+// emitter 生成编解码语句
+type emitter struct {
+	buf        bytes.Buffer
+	suffix     string // 结构体方法名后缀
+	n          int    // 临时变量计数
+	usesSlices bool
+	usesBuffer bool
+}
+
+func (e *emitter) String() string {
+	return e.buf.String()
+}
+
+func (e *emitter) p(format string, args ...any) {
+	fmt.Fprintf(&e.buf, format, args...)
+	e.buf.WriteByte('\n')
+}
+
+// tmp 生成唯一的临时变量名
+func (e *emitter) tmp(prefix string) string {
+	e.n++
+	return prefix + strconv.Itoa(e.n)
+}
+
+// writeLen 生成写入长度前缀的语句
+func (e *emitter) writeLen(expr string) {
+	e.usesBuffer = true
+	e.p("if len(%s) > bytes.MaxStringLength {\nreturn buffer.ErrExceedBufferLimit\n}", expr)
+	e.p("if _, err := b.WriteVarint32(int32(len(%s))); err != nil {\nreturn err\n}", expr)
+}
+
+// readLen 生成读取长度前缀至变量 n 的语句
+func (e *emitter) readLen(n string) {
+	e.usesBuffer = true
+	e.p("%s, err := b.ReadVarint32()\nif err != nil {\nreturn err\n}", n)
+	e.p("if %s < 0 {\nreturn buffer.ErrExceedBufferLimit\n}", n)
+}
+
+// encode 生成编码 expr 的语句
+func (e *emitter) encode(expr string, t *typeInfo, enc encoding) {
+	switch t.kind {
+	case kindBasic:
+		method, cast, counted := scalarMethod(t.basic, enc)
+		if t.name != cast {
+			expr = cast + "(" + expr + ")"
+		}
+		if counted {
+			e.p("if _, err := b.Write%s(%s); err != nil {\nreturn err\n}", method, expr)
+		} else {
+			e.p("if err := b.Write%s(%s); err != nil {\nreturn err\n}", method, expr)
+		}
+
+	case kindBytes:
+		e.writeLen(expr)
+		e.p("if n, err := b.Write(%s); err != nil {\nreturn err\n} else if n != len(%s) {\nreturn buffer.ErrExceedBufferLimit\n}", expr, expr)
+
+	case kindSlice:
+		e.writeLen(expr)
+		elem := e.tmp("e")
+		e.p("for _, %s := range %s {", elem, expr)
+		e.encode(elem, t.elem, enc)
+		e.p("}")
+
+	case kindArray:
+		i := e.tmp("i")
+		e.p("for %s := range %s {", i, expr)
+		e.encode(expr+"["+i+"]", t.elem, enc)
+		e.p("}")
+
+	case kindMap:
+		e.usesSlices = true
+		e.writeLen(expr)
+		keys, k, elem := e.tmp("keys"), e.tmp("k"), e.tmp("e")
+		e.p("%s := make([]%s, 0, len(%s))", keys, t.key.name, expr)
+		e.p("for %s := range %s {\n%s = append(%s, %s)\n}", k, expr, keys, keys, k)
+		e.p("slices.Sort(%s)", keys)
+		e.p("for _, %s := range %s {", k, keys)
+		e.encode(k, t.key, enc)
+		e.p("%s := %s[%s]", elem, expr, k)
+		e.encode(elem, t.elem, enc)
+		e.p("}")
+
+	case kindPointer:
+		e.p("if %s == nil {", expr)
+		e.p("if err := b.WriteBool(false); err != nil {\nreturn err\n}")
+		e.p("} else {")
+		e.p("if err := b.WriteBool(true); err != nil {\nreturn err\n}")
+		e.encode("(*"+expr+")", t.elem, enc)
+		e.p("}")
+
+	case kindStruct:
+		e.p("if err := %s.EncodeTo%s(b); err != nil {\nreturn err\n}", expr, e.suffix)
+	}
+}
+
+// decode 生成解码至 expr 的语句
+func (e *emitter) decode(expr string, t *typeInfo, enc encoding) {
+	switch t.kind {
+	case kindBasic:
+		method, cast, _ := scalarMethod(t.basic, enc)
+		x := e.tmp("x")
+		e.p("{\n%s, err := b.Read%s()\nif err != nil {\nreturn err\n}", x, method)
+		if t.name != cast {
+			e.p("%s = %s(%s)\n}", expr, t.name, x)
+		} else {
+			e.p("%s = %s\n}", expr, x)
+		}
+
+	case kindBytes:
+		n := e.tmp("n")
+		e.p("{")
+		e.readLen(n)
+		e.p("if int(%s) > b.Readable() {\nreturn io.ErrUnexpectedEOF\n}", n)
+		e.p("if %s == 0 {\n%s = nil\n} else {", n, expr)
+		e.p("%s = make(%s, %s)", expr, t.name, n)
+		e.p("copy(%s, b.UnreadData())", expr)
+		e.p("_, _ = b.Skip(int(%s))\n}\n}", n)
+
+	case kindSlice:
+		n, s, i, elem := e.tmp("n"), e.tmp("s"), e.tmp("i"), e.tmp("e")
+		e.p("{")
+		e.readLen(n)
+		e.p("if %s == 0 {\n%s = nil\n} else {", n, expr)
+		e.p("%s := make(%s, 0, min(int(%s), b.Readable()))", s, t.name, n)
+		e.p("for %s := 0; %s < int(%s); %s++ {", i, i, n, i)
+		e.p("var %s %s", elem, t.elem.name)
+		e.decode(elem, t.elem, enc)
+		e.p("%s = append(%s, %s)\n}", s, s, elem)
+		e.p("%s = %s\n}\n}", expr, s)
+
+	case kindArray:
+		i := e.tmp("i")
+		e.p("for %s := range %s {", i, expr)
+		e.decode(expr+"["+i+"]", t.elem, enc)
+		e.p("}")
+
+	case kindMap:
+		n, m, i, k, elem := e.tmp("n"), e.tmp("m"), e.tmp("i"), e.tmp("k"), e.tmp("e")
+		e.p("{")
+		e.readLen(n)
+		e.p("if %s == 0 {\n%s = nil\n} else {", n, expr)
+		e.p("%s := make(%s, min(int(%s), b.Readable()))", m, t.name, n)
+		e.p("for %s := 0; %s < int(%s); %s++ {", i, i, n, i)
+		e.p("var %s %s", k, t.key.name)
+		e.decode(k, t.key, enc)
+		e.p("var %s %s", elem, t.elem.name)
+		e.decode(elem, t.elem, enc)
+		e.p("%s[%s] = %s\n}", m, k, elem)
+		e.p("%s = %s\n}\n}", expr, m)
+
+	case kindPointer:
+		ok := e.tmp("ok")
+		e.p("{\n%s, err := b.ReadBool()\nif err != nil {\nreturn err\n}", ok)
+		e.p("if !%s {\n%s = nil\n} else {", ok, expr)
+		e.p("if %s == nil {\n%s = new(%s)\n}", expr, expr, t.elem.name)
+		e.decode("(*"+expr+")", t.elem, enc)
+		e.p("}\n}")
+
+	case kindStruct:
+		e.p("if err := %s.DecodeFrom%s(b); err != nil {\nreturn err\n}", expr, e.suffix)
+	}
+}
+
+// sampler 生成测试用随机值表达式
+type sampler struct {
+	usesStrconv bool
+}
+
+// maxSampleDepth 随机值的最大嵌套深度, 避免递归类型无限展开
+const maxSampleDepth = 3
+
+// sample 返回构造类型 t 的随机值的表达式
+func (s *sampler) sample(t *typeInfo) string {
+	switch t.kind {
+	case kindBasic:
+		switch t.basic {
+		case "bool":
+			return fmt.Sprintf("%s(r.Intn(2) == 1)", t.name)
+		case "string":
+			s.usesStrconv = true
+			return fmt.Sprintf("%s(strconv.FormatInt(r.Int63(), 36))", t.name)
+		case "float32", "float64":
+			return fmt.Sprintf("%s(r.NormFloat64())", t.name)
+		case "uint64", "uint":
+			return fmt.Sprintf("%s(r.Uint64())", t.name)
+		default:
+			return fmt.Sprintf("%s(r.Uint64())", t.name)
+		}
+
+	case kindBytes, kindSlice:
+		return fmt.Sprintf(`func() %s {
+n := r.Intn(4)
+if n == 0 || depth > %d {
+	return nil
+}
+s := make(%s, n)
+for i := range s {
+	s[i] = %s
+}
+return s
+}()`, t.name, maxSampleDepth, t.name, s.sample(t.elem))
+
+	case kindArray:
+		return fmt.Sprintf(`func() (a %s) {
+for i := range a {
+	a[i] = %s
+}
+return
+}()`, t.name, s.sample(t.elem))
+
+	case kindMap:
+		return fmt.Sprintf(`func() %s {
+n := r.Intn(4)
+if n == 0 || depth > %d {
+	return nil
+}
+m := make(%s, n)
+for i := 0; i < n; i++ {
+	m[%s] = %s
+}
+return m
+}()`, t.name, maxSampleDepth, t.name, s.sample(t.key), s.sample(t.elem))
+
+	case kindPointer:
+		return fmt.Sprintf(`func() %s {
+if r.Intn(2) == 0 || depth > %d {
+	return nil
+}
+x := %s
+return &x
+}()`, t.name, maxSampleDepth, s.sample(t.elem))
+
+	case kindStruct:
+		return fmt.Sprintf("bufgenSample%s(r, depth+1)", t.name)
+	}
+	return ""
+}
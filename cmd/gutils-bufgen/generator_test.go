@@ -0,0 +1,64 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate_Golden(t *testing.T) {
+	dir := filepath.Join("internal", "example")
+	code, test, err := Generate(dir, []string{"Message"})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+
+	for name, got := range map[string][]byte{
+		"message_bufgen.go":      code,
+		"message_bufgen_test.go": test,
+	} {
+		want, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if string(got) != string(want) {
+			t.Errorf("%s is out of date, run go generate ./%s", name, filepath.ToSlash(dir))
+		}
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	dir := t.TempDir()
+	src := `package p
+
+type Float struct {
+	F float64 ` + "`buf:\"varint\"`" + `
+}
+
+type Chan struct {
+	C chan int
+}
+
+type Key struct {
+	M map[bool]int
+}
+
+type NotStruct int
+`
+	if err := os.WriteFile(filepath.Join(dir, "p.go"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for typ, want := range map[string]string{
+		"Float":     "varint on float64",
+		"Chan":      "unsupported type chan int",
+		"Key":       "unsupported map key type bool",
+		"NotStruct": "is not a struct",
+		"Missing":   "not found",
+	} {
+		if _, _, err := Generate(dir, []string{typ}); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("generate %s: expected error containing %q, got %v", typ, want, err)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+// Package example 演示 gutils-bufgen 生成的编解码代码.
+package example
+
+//go:generate go run github.com/godyy/gutils/cmd/gutils-bufgen -type=Message
+
+// Level 等级
+type Level int32
+
+// Blob 二进制数据
+type Blob []byte
+
+// Inner 嵌套结构体
+type Inner struct {
+	ID   int32 `buf:"varint"`
+	Name string
+}
+
+// Message 示例消息
+type Message struct {
+	Flag    bool
+	Small   int8
+	Port    uint16 `buf:"big"`
+	Code    int32  `buf:"lit"`
+	Level   Level  `buf:"varint"`
+	Seq     uint64 `buf:"varint"`
+	Count   int
+	Ratio   float32 `buf:"big"`
+	Score   float64
+	Title   string
+	Payload []byte
+	Blob    Blob
+	Scores  []int16 `buf:"varint"`
+	Pair    [2]uint32
+	Attrs   map[string]int64 `buf:"varint"`
+	Inner   Inner
+	Items   []Inner
+	ByID    map[int32]*Inner
+	Parent  *Message
+	Opt     *uint32 `buf:"big"`
+	Ignored int     `buf:"-"`
+	private int
+}
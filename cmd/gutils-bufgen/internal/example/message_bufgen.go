@@ -0,0 +1,987 @@
+// Code generated by gutils-bufgen. DO NOT EDIT.
+
+package example
+
+import (
+	"io"
+	"slices"
+
+	"github.com/godyy/gutils/buffer"
+	"github.com/godyy/gutils/buffer/bytes"
+)
+
+// EncodeTo 将 Message 编码写入 b
+func (v *Message) EncodeTo(b *bytes.Buffer) error {
+	if err := b.WriteBool(v.Flag); err != nil {
+		return err
+	}
+	if err := b.WriteInt8(v.Small); err != nil {
+		return err
+	}
+	if err := b.WriteBigUint16(v.Port); err != nil {
+		return err
+	}
+	if err := b.WriteLitInt32(v.Code); err != nil {
+		return err
+	}
+	if _, err := b.WriteVarint32(int32(v.Level)); err != nil {
+		return err
+	}
+	if _, err := b.WriteUvarint64(v.Seq); err != nil {
+		return err
+	}
+	if err := b.WriteInt64(int64(v.Count)); err != nil {
+		return err
+	}
+	if err := b.WriteBigFloat32(v.Ratio); err != nil {
+		return err
+	}
+	if err := b.WriteFloat64(v.Score); err != nil {
+		return err
+	}
+	if err := b.WriteString(v.Title); err != nil {
+		return err
+	}
+	if len(v.Payload) > bytes.MaxStringLength {
+		return buffer.ErrExceedBufferLimit
+	}
+	if _, err := b.WriteVarint32(int32(len(v.Payload))); err != nil {
+		return err
+	}
+	if n, err := b.Write(v.Payload); err != nil {
+		return err
+	} else if n != len(v.Payload) {
+		return buffer.ErrExceedBufferLimit
+	}
+	if len(v.Blob) > bytes.MaxStringLength {
+		return buffer.ErrExceedBufferLimit
+	}
+	if _, err := b.WriteVarint32(int32(len(v.Blob))); err != nil {
+		return err
+	}
+	if n, err := b.Write(v.Blob); err != nil {
+		return err
+	} else if n != len(v.Blob) {
+		return buffer.ErrExceedBufferLimit
+	}
+	if len(v.Scores) > bytes.MaxStringLength {
+		return buffer.ErrExceedBufferLimit
+	}
+	if _, err := b.WriteVarint32(int32(len(v.Scores))); err != nil {
+		return err
+	}
+	for _, e1 := range v.Scores {
+		if _, err := b.WriteVarint16(e1); err != nil {
+			return err
+		}
+	}
+	for i2 := range v.Pair {
+		if err := b.WriteUint32(v.Pair[i2]); err != nil {
+			return err
+		}
+	}
+	if len(v.Attrs) > bytes.MaxStringLength {
+		return buffer.ErrExceedBufferLimit
+	}
+	if _, err := b.WriteVarint32(int32(len(v.Attrs))); err != nil {
+		return err
+	}
+	keys3 := make([]string, 0, len(v.Attrs))
+	for k4 := range v.Attrs {
+		keys3 = append(keys3, k4)
+	}
+	slices.Sort(keys3)
+	for _, k4 := range keys3 {
+		if err := b.WriteString(k4); err != nil {
+			return err
+		}
+		e5 := v.Attrs[k4]
+		if _, err := b.WriteVarint64(e5); err != nil {
+			return err
+		}
+	}
+	if err := v.Inner.EncodeTo(b); err != nil {
+		return err
+	}
+	if len(v.Items) > bytes.MaxStringLength {
+		return buffer.ErrExceedBufferLimit
+	}
+	if _, err := b.WriteVarint32(int32(len(v.Items))); err != nil {
+		return err
+	}
+	for _, e6 := range v.Items {
+		if err := e6.EncodeTo(b); err != nil {
+			return err
+		}
+	}
+	if len(v.ByID) > bytes.MaxStringLength {
+		return buffer.ErrExceedBufferLimit
+	}
+	if _, err := b.WriteVarint32(int32(len(v.ByID))); err != nil {
+		return err
+	}
+	keys7 := make([]int32, 0, len(v.ByID))
+	for k8 := range v.ByID {
+		keys7 = append(keys7, k8)
+	}
+	slices.Sort(keys7)
+	for _, k8 := range keys7 {
+		if err := b.WriteInt32(k8); err != nil {
+			return err
+		}
+		e9 := v.ByID[k8]
+		if e9 == nil {
+			if err := b.WriteBool(false); err != nil {
+				return err
+			}
+		} else {
+			if err := b.WriteBool(true); err != nil {
+				return err
+			}
+			if err := (*e9).EncodeTo(b); err != nil {
+				return err
+			}
+		}
+	}
+	if v.Parent == nil {
+		if err := b.WriteBool(false); err != nil {
+			return err
+		}
+	} else {
+		if err := b.WriteBool(true); err != nil {
+			return err
+		}
+		if err := (*v.Parent).EncodeTo(b); err != nil {
+			return err
+		}
+	}
+	if v.Opt == nil {
+		if err := b.WriteBool(false); err != nil {
+			return err
+		}
+	} else {
+		if err := b.WriteBool(true); err != nil {
+			return err
+		}
+		if err := b.WriteBigUint32((*v.Opt)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeFrom 自 b 中解码 Message
+func (v *Message) DecodeFrom(b *bytes.Buffer) (err error) {
+	readable := b.Readable()
+	defer func() {
+		if err == io.EOF && b.Readable() != readable {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	{
+		x1, err := b.ReadBool()
+		if err != nil {
+			return err
+		}
+		v.Flag = x1
+	}
+	{
+		x2, err := b.ReadInt8()
+		if err != nil {
+			return err
+		}
+		v.Small = x2
+	}
+	{
+		x3, err := b.ReadBigUint16()
+		if err != nil {
+			return err
+		}
+		v.Port = x3
+	}
+	{
+		x4, err := b.ReadLitInt32()
+		if err != nil {
+			return err
+		}
+		v.Code = x4
+	}
+	{
+		x5, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		v.Level = Level(x5)
+	}
+	{
+		x6, err := b.ReadUvarint64()
+		if err != nil {
+			return err
+		}
+		v.Seq = x6
+	}
+	{
+		x7, err := b.ReadInt64()
+		if err != nil {
+			return err
+		}
+		v.Count = int(x7)
+	}
+	{
+		x8, err := b.ReadBigFloat32()
+		if err != nil {
+			return err
+		}
+		v.Ratio = x8
+	}
+	{
+		x9, err := b.ReadFloat64()
+		if err != nil {
+			return err
+		}
+		v.Score = x9
+	}
+	{
+		x10, err := b.ReadString()
+		if err != nil {
+			return err
+		}
+		v.Title = x10
+	}
+	{
+		n11, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		if n11 < 0 {
+			return buffer.ErrExceedBufferLimit
+		}
+		if int(n11) > b.Readable() {
+			return io.ErrUnexpectedEOF
+		}
+		if n11 == 0 {
+			v.Payload = nil
+		} else {
+			v.Payload = make([]byte, n11)
+			copy(v.Payload, b.UnreadData())
+			_, _ = b.Skip(int(n11))
+		}
+	}
+	{
+		n12, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		if n12 < 0 {
+			return buffer.ErrExceedBufferLimit
+		}
+		if int(n12) > b.Readable() {
+			return io.ErrUnexpectedEOF
+		}
+		if n12 == 0 {
+			v.Blob = nil
+		} else {
+			v.Blob = make(Blob, n12)
+			copy(v.Blob, b.UnreadData())
+			_, _ = b.Skip(int(n12))
+		}
+	}
+	{
+		n13, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		if n13 < 0 {
+			return buffer.ErrExceedBufferLimit
+		}
+		if n13 == 0 {
+			v.Scores = nil
+		} else {
+			s14 := make([]int16, 0, min(int(n13), b.Readable()))
+			for i15 := 0; i15 < int(n13); i15++ {
+				var e16 int16
+				{
+					x17, err := b.ReadVarint16()
+					if err != nil {
+						return err
+					}
+					e16 = x17
+				}
+				s14 = append(s14, e16)
+			}
+			v.Scores = s14
+		}
+	}
+	for i18 := range v.Pair {
+		{
+			x19, err := b.ReadUint32()
+			if err != nil {
+				return err
+			}
+			v.Pair[i18] = x19
+		}
+	}
+	{
+		n20, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		if n20 < 0 {
+			return buffer.ErrExceedBufferLimit
+		}
+		if n20 == 0 {
+			v.Attrs = nil
+		} else {
+			m21 := make(map[string]int64, min(int(n20), b.Readable()))
+			for i22 := 0; i22 < int(n20); i22++ {
+				var k23 string
+				{
+					x25, err := b.ReadString()
+					if err != nil {
+						return err
+					}
+					k23 = x25
+				}
+				var e24 int64
+				{
+					x26, err := b.ReadVarint64()
+					if err != nil {
+						return err
+					}
+					e24 = x26
+				}
+				m21[k23] = e24
+			}
+			v.Attrs = m21
+		}
+	}
+	if err := v.Inner.DecodeFrom(b); err != nil {
+		return err
+	}
+	{
+		n27, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		if n27 < 0 {
+			return buffer.ErrExceedBufferLimit
+		}
+		if n27 == 0 {
+			v.Items = nil
+		} else {
+			s28 := make([]Inner, 0, min(int(n27), b.Readable()))
+			for i29 := 0; i29 < int(n27); i29++ {
+				var e30 Inner
+				if err := e30.DecodeFrom(b); err != nil {
+					return err
+				}
+				s28 = append(s28, e30)
+			}
+			v.Items = s28
+		}
+	}
+	{
+		n31, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		if n31 < 0 {
+			return buffer.ErrExceedBufferLimit
+		}
+		if n31 == 0 {
+			v.ByID = nil
+		} else {
+			m32 := make(map[int32]*Inner, min(int(n31), b.Readable()))
+			for i33 := 0; i33 < int(n31); i33++ {
+				var k34 int32
+				{
+					x36, err := b.ReadInt32()
+					if err != nil {
+						return err
+					}
+					k34 = x36
+				}
+				var e35 *Inner
+				{
+					ok37, err := b.ReadBool()
+					if err != nil {
+						return err
+					}
+					if !ok37 {
+						e35 = nil
+					} else {
+						if e35 == nil {
+							e35 = new(Inner)
+						}
+						if err := (*e35).DecodeFrom(b); err != nil {
+							return err
+						}
+					}
+				}
+				m32[k34] = e35
+			}
+			v.ByID = m32
+		}
+	}
+	{
+		ok38, err := b.ReadBool()
+		if err != nil {
+			return err
+		}
+		if !ok38 {
+			v.Parent = nil
+		} else {
+			if v.Parent == nil {
+				v.Parent = new(Message)
+			}
+			if err := (*v.Parent).DecodeFrom(b); err != nil {
+				return err
+			}
+		}
+	}
+	{
+		ok39, err := b.ReadBool()
+		if err != nil {
+			return err
+		}
+		if !ok39 {
+			v.Opt = nil
+		} else {
+			if v.Opt == nil {
+				v.Opt = new(uint32)
+			}
+			{
+				x40, err := b.ReadBigUint32()
+				if err != nil {
+					return err
+				}
+				(*v.Opt) = x40
+			}
+		}
+	}
+	return nil
+}
+
+// EncodeToFixed 将 Message 编码写入 b
+func (v *Message) EncodeToFixed(b *bytes.FixedBuffer) error {
+	if err := b.WriteBool(v.Flag); err != nil {
+		return err
+	}
+	if err := b.WriteInt8(v.Small); err != nil {
+		return err
+	}
+	if err := b.WriteBigUint16(v.Port); err != nil {
+		return err
+	}
+	if err := b.WriteLitInt32(v.Code); err != nil {
+		return err
+	}
+	if _, err := b.WriteVarint32(int32(v.Level)); err != nil {
+		return err
+	}
+	if _, err := b.WriteUvarint64(v.Seq); err != nil {
+		return err
+	}
+	if err := b.WriteInt64(int64(v.Count)); err != nil {
+		return err
+	}
+	if err := b.WriteBigFloat32(v.Ratio); err != nil {
+		return err
+	}
+	if err := b.WriteFloat64(v.Score); err != nil {
+		return err
+	}
+	if err := b.WriteString(v.Title); err != nil {
+		return err
+	}
+	if len(v.Payload) > bytes.MaxStringLength {
+		return buffer.ErrExceedBufferLimit
+	}
+	if _, err := b.WriteVarint32(int32(len(v.Payload))); err != nil {
+		return err
+	}
+	if n, err := b.Write(v.Payload); err != nil {
+		return err
+	} else if n != len(v.Payload) {
+		return buffer.ErrExceedBufferLimit
+	}
+	if len(v.Blob) > bytes.MaxStringLength {
+		return buffer.ErrExceedBufferLimit
+	}
+	if _, err := b.WriteVarint32(int32(len(v.Blob))); err != nil {
+		return err
+	}
+	if n, err := b.Write(v.Blob); err != nil {
+		return err
+	} else if n != len(v.Blob) {
+		return buffer.ErrExceedBufferLimit
+	}
+	if len(v.Scores) > bytes.MaxStringLength {
+		return buffer.ErrExceedBufferLimit
+	}
+	if _, err := b.WriteVarint32(int32(len(v.Scores))); err != nil {
+		return err
+	}
+	for _, e1 := range v.Scores {
+		if _, err := b.WriteVarint16(e1); err != nil {
+			return err
+		}
+	}
+	for i2 := range v.Pair {
+		if err := b.WriteUint32(v.Pair[i2]); err != nil {
+			return err
+		}
+	}
+	if len(v.Attrs) > bytes.MaxStringLength {
+		return buffer.ErrExceedBufferLimit
+	}
+	if _, err := b.WriteVarint32(int32(len(v.Attrs))); err != nil {
+		return err
+	}
+	keys3 := make([]string, 0, len(v.Attrs))
+	for k4 := range v.Attrs {
+		keys3 = append(keys3, k4)
+	}
+	slices.Sort(keys3)
+	for _, k4 := range keys3 {
+		if err := b.WriteString(k4); err != nil {
+			return err
+		}
+		e5 := v.Attrs[k4]
+		if _, err := b.WriteVarint64(e5); err != nil {
+			return err
+		}
+	}
+	if err := v.Inner.EncodeToFixed(b); err != nil {
+		return err
+	}
+	if len(v.Items) > bytes.MaxStringLength {
+		return buffer.ErrExceedBufferLimit
+	}
+	if _, err := b.WriteVarint32(int32(len(v.Items))); err != nil {
+		return err
+	}
+	for _, e6 := range v.Items {
+		if err := e6.EncodeToFixed(b); err != nil {
+			return err
+		}
+	}
+	if len(v.ByID) > bytes.MaxStringLength {
+		return buffer.ErrExceedBufferLimit
+	}
+	if _, err := b.WriteVarint32(int32(len(v.ByID))); err != nil {
+		return err
+	}
+	keys7 := make([]int32, 0, len(v.ByID))
+	for k8 := range v.ByID {
+		keys7 = append(keys7, k8)
+	}
+	slices.Sort(keys7)
+	for _, k8 := range keys7 {
+		if err := b.WriteInt32(k8); err != nil {
+			return err
+		}
+		e9 := v.ByID[k8]
+		if e9 == nil {
+			if err := b.WriteBool(false); err != nil {
+				return err
+			}
+		} else {
+			if err := b.WriteBool(true); err != nil {
+				return err
+			}
+			if err := (*e9).EncodeToFixed(b); err != nil {
+				return err
+			}
+		}
+	}
+	if v.Parent == nil {
+		if err := b.WriteBool(false); err != nil {
+			return err
+		}
+	} else {
+		if err := b.WriteBool(true); err != nil {
+			return err
+		}
+		if err := (*v.Parent).EncodeToFixed(b); err != nil {
+			return err
+		}
+	}
+	if v.Opt == nil {
+		if err := b.WriteBool(false); err != nil {
+			return err
+		}
+	} else {
+		if err := b.WriteBool(true); err != nil {
+			return err
+		}
+		if err := b.WriteBigUint32((*v.Opt)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeFromFixed 自 b 中解码 Message
+func (v *Message) DecodeFromFixed(b *bytes.FixedBuffer) (err error) {
+	readable := b.Readable()
+	defer func() {
+		if err == io.EOF && b.Readable() != readable {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	{
+		x1, err := b.ReadBool()
+		if err != nil {
+			return err
+		}
+		v.Flag = x1
+	}
+	{
+		x2, err := b.ReadInt8()
+		if err != nil {
+			return err
+		}
+		v.Small = x2
+	}
+	{
+		x3, err := b.ReadBigUint16()
+		if err != nil {
+			return err
+		}
+		v.Port = x3
+	}
+	{
+		x4, err := b.ReadLitInt32()
+		if err != nil {
+			return err
+		}
+		v.Code = x4
+	}
+	{
+		x5, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		v.Level = Level(x5)
+	}
+	{
+		x6, err := b.ReadUvarint64()
+		if err != nil {
+			return err
+		}
+		v.Seq = x6
+	}
+	{
+		x7, err := b.ReadInt64()
+		if err != nil {
+			return err
+		}
+		v.Count = int(x7)
+	}
+	{
+		x8, err := b.ReadBigFloat32()
+		if err != nil {
+			return err
+		}
+		v.Ratio = x8
+	}
+	{
+		x9, err := b.ReadFloat64()
+		if err != nil {
+			return err
+		}
+		v.Score = x9
+	}
+	{
+		x10, err := b.ReadString()
+		if err != nil {
+			return err
+		}
+		v.Title = x10
+	}
+	{
+		n11, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		if n11 < 0 {
+			return buffer.ErrExceedBufferLimit
+		}
+		if int(n11) > b.Readable() {
+			return io.ErrUnexpectedEOF
+		}
+		if n11 == 0 {
+			v.Payload = nil
+		} else {
+			v.Payload = make([]byte, n11)
+			copy(v.Payload, b.UnreadData())
+			_, _ = b.Skip(int(n11))
+		}
+	}
+	{
+		n12, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		if n12 < 0 {
+			return buffer.ErrExceedBufferLimit
+		}
+		if int(n12) > b.Readable() {
+			return io.ErrUnexpectedEOF
+		}
+		if n12 == 0 {
+			v.Blob = nil
+		} else {
+			v.Blob = make(Blob, n12)
+			copy(v.Blob, b.UnreadData())
+			_, _ = b.Skip(int(n12))
+		}
+	}
+	{
+		n13, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		if n13 < 0 {
+			return buffer.ErrExceedBufferLimit
+		}
+		if n13 == 0 {
+			v.Scores = nil
+		} else {
+			s14 := make([]int16, 0, min(int(n13), b.Readable()))
+			for i15 := 0; i15 < int(n13); i15++ {
+				var e16 int16
+				{
+					x17, err := b.ReadVarint16()
+					if err != nil {
+						return err
+					}
+					e16 = x17
+				}
+				s14 = append(s14, e16)
+			}
+			v.Scores = s14
+		}
+	}
+	for i18 := range v.Pair {
+		{
+			x19, err := b.ReadUint32()
+			if err != nil {
+				return err
+			}
+			v.Pair[i18] = x19
+		}
+	}
+	{
+		n20, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		if n20 < 0 {
+			return buffer.ErrExceedBufferLimit
+		}
+		if n20 == 0 {
+			v.Attrs = nil
+		} else {
+			m21 := make(map[string]int64, min(int(n20), b.Readable()))
+			for i22 := 0; i22 < int(n20); i22++ {
+				var k23 string
+				{
+					x25, err := b.ReadString()
+					if err != nil {
+						return err
+					}
+					k23 = x25
+				}
+				var e24 int64
+				{
+					x26, err := b.ReadVarint64()
+					if err != nil {
+						return err
+					}
+					e24 = x26
+				}
+				m21[k23] = e24
+			}
+			v.Attrs = m21
+		}
+	}
+	if err := v.Inner.DecodeFromFixed(b); err != nil {
+		return err
+	}
+	{
+		n27, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		if n27 < 0 {
+			return buffer.ErrExceedBufferLimit
+		}
+		if n27 == 0 {
+			v.Items = nil
+		} else {
+			s28 := make([]Inner, 0, min(int(n27), b.Readable()))
+			for i29 := 0; i29 < int(n27); i29++ {
+				var e30 Inner
+				if err := e30.DecodeFromFixed(b); err != nil {
+					return err
+				}
+				s28 = append(s28, e30)
+			}
+			v.Items = s28
+		}
+	}
+	{
+		n31, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		if n31 < 0 {
+			return buffer.ErrExceedBufferLimit
+		}
+		if n31 == 0 {
+			v.ByID = nil
+		} else {
+			m32 := make(map[int32]*Inner, min(int(n31), b.Readable()))
+			for i33 := 0; i33 < int(n31); i33++ {
+				var k34 int32
+				{
+					x36, err := b.ReadInt32()
+					if err != nil {
+						return err
+					}
+					k34 = x36
+				}
+				var e35 *Inner
+				{
+					ok37, err := b.ReadBool()
+					if err != nil {
+						return err
+					}
+					if !ok37 {
+						e35 = nil
+					} else {
+						if e35 == nil {
+							e35 = new(Inner)
+						}
+						if err := (*e35).DecodeFromFixed(b); err != nil {
+							return err
+						}
+					}
+				}
+				m32[k34] = e35
+			}
+			v.ByID = m32
+		}
+	}
+	{
+		ok38, err := b.ReadBool()
+		if err != nil {
+			return err
+		}
+		if !ok38 {
+			v.Parent = nil
+		} else {
+			if v.Parent == nil {
+				v.Parent = new(Message)
+			}
+			if err := (*v.Parent).DecodeFromFixed(b); err != nil {
+				return err
+			}
+		}
+	}
+	{
+		ok39, err := b.ReadBool()
+		if err != nil {
+			return err
+		}
+		if !ok39 {
+			v.Opt = nil
+		} else {
+			if v.Opt == nil {
+				v.Opt = new(uint32)
+			}
+			{
+				x40, err := b.ReadBigUint32()
+				if err != nil {
+					return err
+				}
+				(*v.Opt) = x40
+			}
+		}
+	}
+	return nil
+}
+
+// EncodeTo 将 Inner 编码写入 b
+func (v *Inner) EncodeTo(b *bytes.Buffer) error {
+	if _, err := b.WriteVarint32(v.ID); err != nil {
+		return err
+	}
+	if err := b.WriteString(v.Name); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DecodeFrom 自 b 中解码 Inner
+func (v *Inner) DecodeFrom(b *bytes.Buffer) (err error) {
+	readable := b.Readable()
+	defer func() {
+		if err == io.EOF && b.Readable() != readable {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	{
+		x1, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		v.ID = x1
+	}
+	{
+		x2, err := b.ReadString()
+		if err != nil {
+			return err
+		}
+		v.Name = x2
+	}
+	return nil
+}
+
+// EncodeToFixed 将 Inner 编码写入 b
+func (v *Inner) EncodeToFixed(b *bytes.FixedBuffer) error {
+	if _, err := b.WriteVarint32(v.ID); err != nil {
+		return err
+	}
+	if err := b.WriteString(v.Name); err != nil {
+		return err
+	}
+	return nil
+}
+
+// DecodeFromFixed 自 b 中解码 Inner
+func (v *Inner) DecodeFromFixed(b *bytes.FixedBuffer) (err error) {
+	readable := b.Readable()
+	defer func() {
+		if err == io.EOF && b.Readable() != readable {
+			err = io.ErrUnexpectedEOF
+		}
+	}()
+	{
+		x1, err := b.ReadVarint32()
+		if err != nil {
+			return err
+		}
+		v.ID = x1
+	}
+	{
+		x2, err := b.ReadString()
+		if err != nil {
+			return err
+		}
+		v.Name = x2
+	}
+	return nil
+}
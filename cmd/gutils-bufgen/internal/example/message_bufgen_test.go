@@ -0,0 +1,207 @@
+// Code generated by gutils-bufgen. DO NOT EDIT.
+
+package example
+
+import (
+	"math/rand"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/godyy/gutils/buffer/bytes"
+)
+
+func TestBufgen_Message(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		v := bufgenSampleMessage(r, 0)
+		want, err := bytes.Marshal(&v)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+
+		b := bytes.NewBuffer(nil)
+		if err := v.EncodeTo(b); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+		if string(b.UnreadData()) != string(want) {
+			t.Fatalf("encode data mismatch\n got: %v\nwant: %v", b.UnreadData(), want)
+		}
+		var got Message
+		if err := got.DecodeFrom(b); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if !reflect.DeepEqual(got, v) {
+			t.Fatalf("decode mismatch\n got: %+v\nwant: %+v", got, v)
+		}
+
+		fb := bytes.NewFixedBuffer(len(want) + 1)
+		if err := v.EncodeToFixed(fb); err != nil {
+			t.Fatalf("encode fixed: %v", err)
+		}
+		if string(fb.UnreadData()) != string(want) {
+			t.Fatalf("encode fixed data mismatch\n got: %v\nwant: %v", fb.UnreadData(), want)
+		}
+		var gotFixed Message
+		if err := gotFixed.DecodeFromFixed(fb); err != nil {
+			t.Fatalf("decode fixed: %v", err)
+		}
+		if !reflect.DeepEqual(gotFixed, v) {
+			t.Fatalf("decode fixed mismatch\n got: %+v\nwant: %+v", gotFixed, v)
+		}
+	}
+}
+
+func bufgenSampleMessage(r *rand.Rand, depth int) (v Message) {
+	v.Flag = bool(r.Intn(2) == 1)
+	v.Small = int8(r.Uint64())
+	v.Port = uint16(r.Uint64())
+	v.Code = int32(r.Uint64())
+	v.Level = Level(r.Uint64())
+	v.Seq = uint64(r.Uint64())
+	v.Count = int(r.Uint64())
+	v.Ratio = float32(r.NormFloat64())
+	v.Score = float64(r.NormFloat64())
+	v.Title = string(strconv.FormatInt(r.Int63(), 36))
+	v.Payload = func() []byte {
+		n := r.Intn(4)
+		if n == 0 || depth > 3 {
+			return nil
+		}
+		s := make([]byte, n)
+		for i := range s {
+			s[i] = byte(r.Uint64())
+		}
+		return s
+	}()
+	v.Blob = func() Blob {
+		n := r.Intn(4)
+		if n == 0 || depth > 3 {
+			return nil
+		}
+		s := make(Blob, n)
+		for i := range s {
+			s[i] = byte(r.Uint64())
+		}
+		return s
+	}()
+	v.Scores = func() []int16 {
+		n := r.Intn(4)
+		if n == 0 || depth > 3 {
+			return nil
+		}
+		s := make([]int16, n)
+		for i := range s {
+			s[i] = int16(r.Uint64())
+		}
+		return s
+	}()
+	v.Pair = func() (a [2]uint32) {
+		for i := range a {
+			a[i] = uint32(r.Uint64())
+		}
+		return
+	}()
+	v.Attrs = func() map[string]int64 {
+		n := r.Intn(4)
+		if n == 0 || depth > 3 {
+			return nil
+		}
+		m := make(map[string]int64, n)
+		for i := 0; i < n; i++ {
+			m[string(strconv.FormatInt(r.Int63(), 36))] = int64(r.Uint64())
+		}
+		return m
+	}()
+	v.Inner = bufgenSampleInner(r, depth+1)
+	v.Items = func() []Inner {
+		n := r.Intn(4)
+		if n == 0 || depth > 3 {
+			return nil
+		}
+		s := make([]Inner, n)
+		for i := range s {
+			s[i] = bufgenSampleInner(r, depth+1)
+		}
+		return s
+	}()
+	v.ByID = func() map[int32]*Inner {
+		n := r.Intn(4)
+		if n == 0 || depth > 3 {
+			return nil
+		}
+		m := make(map[int32]*Inner, n)
+		for i := 0; i < n; i++ {
+			m[int32(r.Uint64())] = func() *Inner {
+				if r.Intn(2) == 0 || depth > 3 {
+					return nil
+				}
+				x := bufgenSampleInner(r, depth+1)
+				return &x
+			}()
+		}
+		return m
+	}()
+	v.Parent = func() *Message {
+		if r.Intn(2) == 0 || depth > 3 {
+			return nil
+		}
+		x := bufgenSampleMessage(r, depth+1)
+		return &x
+	}()
+	v.Opt = func() *uint32 {
+		if r.Intn(2) == 0 || depth > 3 {
+			return nil
+		}
+		x := uint32(r.Uint64())
+		return &x
+	}()
+	return
+}
+
+func TestBufgen_Inner(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		v := bufgenSampleInner(r, 0)
+		want, err := bytes.Marshal(&v)
+		if err != nil {
+			t.Fatalf("marshal: %v", err)
+		}
+
+		b := bytes.NewBuffer(nil)
+		if err := v.EncodeTo(b); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+		if string(b.UnreadData()) != string(want) {
+			t.Fatalf("encode data mismatch\n got: %v\nwant: %v", b.UnreadData(), want)
+		}
+		var got Inner
+		if err := got.DecodeFrom(b); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if !reflect.DeepEqual(got, v) {
+			t.Fatalf("decode mismatch\n got: %+v\nwant: %+v", got, v)
+		}
+
+		fb := bytes.NewFixedBuffer(len(want) + 1)
+		if err := v.EncodeToFixed(fb); err != nil {
+			t.Fatalf("encode fixed: %v", err)
+		}
+		if string(fb.UnreadData()) != string(want) {
+			t.Fatalf("encode fixed data mismatch\n got: %v\nwant: %v", fb.UnreadData(), want)
+		}
+		var gotFixed Inner
+		if err := gotFixed.DecodeFromFixed(fb); err != nil {
+			t.Fatalf("decode fixed: %v", err)
+		}
+		if !reflect.DeepEqual(gotFixed, v) {
+			t.Fatalf("decode fixed mismatch\n got: %+v\nwant: %+v", gotFixed, v)
+		}
+	}
+}
+
+func bufgenSampleInner(r *rand.Rand, depth int) (v Inner) {
+	v.ID = int32(r.Uint64())
+	v.Name = string(strconv.FormatInt(r.Int63(), 36))
+	return
+}
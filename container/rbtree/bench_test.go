@@ -0,0 +1,128 @@
+package rbtree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/godyy/gutils/container/skiplist"
+)
+
+// orderedMap is the set of operations shared by Map and skiplist.SkipList
+// that the benchmarks exercise.
+type orderedMap interface {
+	Set(key, value int)
+	Get(key int) (int, bool)
+	Delete(key int) bool
+	GetRank(key int) int
+	GetByRank(rank int) (int, int, bool)
+	Ascend(iterator func(key, value int) bool)
+}
+
+type skipListMap struct {
+	*skiplist.SkipList[int, int]
+}
+
+func (s skipListMap) Delete(key int) bool { return s.Remove(key) }
+
+var benchImpls = []struct {
+	name string
+	new  func() orderedMap
+}{
+	{"rbtree", func() orderedMap { return New[int, int]() }},
+	{"skiplist", func() orderedMap { return skipListMap{skiplist.New[int, int]()} }},
+}
+
+const benchSize = 100000
+
+// benchKeys returns a fixed permutation of [0, n) so every implementation
+// runs the same workload.
+func benchKeys(n int) []int {
+	return rand.New(rand.NewSource(1)).Perm(n)
+}
+
+func benchFilled(newMap func() orderedMap) orderedMap {
+	m := newMap()
+	for _, k := range benchKeys(benchSize) {
+		m.Set(k, k)
+	}
+	return m
+}
+
+func BenchmarkSet(b *testing.B) {
+	keys := benchKeys(benchSize)
+	for _, impl := range benchImpls {
+		b.Run(impl.name, func(b *testing.B) {
+			m := impl.new()
+			for i := 0; i < b.N; i++ {
+				k := keys[i%benchSize]
+				m.Set(k, k)
+			}
+		})
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	keys := benchKeys(benchSize)
+	for _, impl := range benchImpls {
+		b.Run(impl.name, func(b *testing.B) {
+			m := benchFilled(impl.new)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.Get(keys[i%benchSize])
+			}
+		})
+	}
+}
+
+func BenchmarkDelete(b *testing.B) {
+	keys := benchKeys(benchSize)
+	for _, impl := range benchImpls {
+		b.Run(impl.name, func(b *testing.B) {
+			m := benchFilled(impl.new)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				k := keys[i%benchSize]
+				m.Delete(k)
+				m.Set(k, k)
+			}
+		})
+	}
+}
+
+func BenchmarkGetRank(b *testing.B) {
+	keys := benchKeys(benchSize)
+	for _, impl := range benchImpls {
+		b.Run(impl.name, func(b *testing.B) {
+			m := benchFilled(impl.new)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.GetRank(keys[i%benchSize])
+			}
+		})
+	}
+}
+
+func BenchmarkGetByRank(b *testing.B) {
+	keys := benchKeys(benchSize)
+	for _, impl := range benchImpls {
+		b.Run(impl.name, func(b *testing.B) {
+			m := benchFilled(impl.new)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.GetByRank(keys[i%benchSize] + 1)
+			}
+		})
+	}
+}
+
+func BenchmarkAscend(b *testing.B) {
+	for _, impl := range benchImpls {
+		b.Run(impl.name, func(b *testing.B) {
+			m := benchFilled(impl.new)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				m.Ascend(func(key, value int) bool { return true })
+			}
+		})
+	}
+}
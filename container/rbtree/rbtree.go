@@ -0,0 +1,525 @@
+// Package rbtree implements an ordered map based on a red-black tree.
+//
+// Unlike container/skiplist, the shape of the tree is deterministic: every
+// operation is O(log n) in the worst case and each entry costs exactly one
+// node allocation.
+package rbtree
+
+import "cmp"
+
+type node[K, V any] struct {
+	key    K
+	value  V
+	left   *node[K, V]
+	right  *node[K, V]
+	parent *node[K, V]
+	red    bool
+	size   int // size is the number of nodes in the subtree rooted at this node
+}
+
+// Map is an ordered map backed by a red-black tree. Each node keeps the size
+// of its subtree, which allows rank and select queries in O(log n).
+// A Map must be created with New or NewFunc.
+type Map[K, V any] struct {
+	compare func(a, b K) int
+	root    *node[K, V]
+}
+
+// New creates a new empty Map whose keys are ordered by cmp.Compare.
+func New[K cmp.Ordered, V any]() *Map[K, V] {
+	return &Map[K, V]{compare: cmp.Compare[K]}
+}
+
+// NewFunc creates a new empty Map whose keys are ordered by cmp, which must
+// return a negative number when a < b, a positive number when a > b and zero
+// when a == b, and define a strict weak ordering.
+// It panics if cmp is nil.
+func NewFunc[K, V any](cmp func(a, b K) int) *Map[K, V] {
+	if cmp == nil {
+		panic("rbtree.NewFunc: nil cmp")
+	}
+	return &Map[K, V]{compare: cmp}
+}
+
+func size[K, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func isRed[K, V any](n *node[K, V]) bool {
+	return n != nil && n.red
+}
+
+func minNode[K, V any](n *node[K, V]) *node[K, V] {
+	for n.left != nil {
+		n = n.left
+	}
+	return n
+}
+
+func maxNode[K, V any](n *node[K, V]) *node[K, V] {
+	for n.right != nil {
+		n = n.right
+	}
+	return n
+}
+
+// next returns the in-order successor of n.
+func next[K, V any](n *node[K, V]) *node[K, V] {
+	if n.right != nil {
+		return minNode(n.right)
+	}
+	p := n.parent
+	for p != nil && n == p.right {
+		n, p = p, p.parent
+	}
+	return p
+}
+
+// prev returns the in-order predecessor of n.
+func prev[K, V any](n *node[K, V]) *node[K, V] {
+	if n.left != nil {
+		return maxNode(n.left)
+	}
+	p := n.parent
+	for p != nil && n == p.left {
+		n, p = p, p.parent
+	}
+	return p
+}
+
+// Len returns the number of elements in the Map.
+func (m *Map[K, V]) Len() int {
+	return size(m.root)
+}
+
+// find returns the node with the given key, or nil.
+func (m *Map[K, V]) find(key K) *node[K, V] {
+	n := m.root
+	for n != nil {
+		if c := m.compare(key, n.key); c < 0 {
+			n = n.left
+		} else if c > 0 {
+			n = n.right
+		} else {
+			return n
+		}
+	}
+	return nil
+}
+
+// Get retrieves the value associated with the given key.
+// Returns the value and true if found, otherwise zero value and false.
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	if n := m.find(key); n != nil {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Set inserts or updates a key-value pair in the Map.
+func (m *Map[K, V]) Set(key K, value V) {
+	var parent *node[K, V]
+	c := 0
+	cur := m.root
+	for cur != nil {
+		parent = cur
+		if c = m.compare(key, cur.key); c < 0 {
+			cur = cur.left
+		} else if c > 0 {
+			cur = cur.right
+		} else {
+			cur.value = value
+			return
+		}
+	}
+
+	n := &node[K, V]{key: key, value: value, parent: parent, red: true, size: 1}
+	if parent == nil {
+		m.root = n
+	} else if c < 0 {
+		parent.left = n
+	} else {
+		parent.right = n
+	}
+	for p := parent; p != nil; p = p.parent {
+		p.size++
+	}
+	m.insertFixup(n)
+}
+
+// Delete removes the key from the Map.
+// Returns true if the key was found and removed, false otherwise.
+func (m *Map[K, V]) Delete(key K) bool {
+	z := m.find(key)
+	if z == nil {
+		return false
+	}
+
+	// A node with two children takes over its successor's entry, and the
+	// successor, which has at most one child, is unlinked instead.
+	if z.left != nil && z.right != nil {
+		s := minNode(z.right)
+		z.key, z.value = s.key, s.value
+		z = s
+	}
+
+	child := z.left
+	if child == nil {
+		child = z.right
+	}
+	parent := z.parent
+	if child != nil {
+		child.parent = parent
+	}
+	m.replaceChild(parent, z, child)
+	for p := parent; p != nil; p = p.parent {
+		p.size--
+	}
+	if !z.red {
+		m.deleteFixup(child, parent)
+	}
+
+	var zeroK K
+	var zeroV V
+	z.key, z.value = zeroK, zeroV
+	z.left, z.right, z.parent = nil, nil, nil
+	return true
+}
+
+// replaceChild replaces the child old of parent with n.
+func (m *Map[K, V]) replaceChild(parent, old, n *node[K, V]) {
+	if parent == nil {
+		m.root = n
+	} else if parent.left == old {
+		parent.left = n
+	} else {
+		parent.right = n
+	}
+}
+
+func (m *Map[K, V]) rotateLeft(x *node[K, V]) {
+	y := x.right
+	x.right = y.left
+	if y.left != nil {
+		y.left.parent = x
+	}
+	y.parent = x.parent
+	m.replaceChild(x.parent, x, y)
+	y.left = x
+	x.parent = y
+
+	y.size = x.size
+	x.size = size(x.left) + size(x.right) + 1
+}
+
+func (m *Map[K, V]) rotateRight(x *node[K, V]) {
+	y := x.left
+	x.left = y.right
+	if y.right != nil {
+		y.right.parent = x
+	}
+	y.parent = x.parent
+	m.replaceChild(x.parent, x, y)
+	y.right = x
+	x.parent = y
+
+	y.size = x.size
+	x.size = size(x.left) + size(x.right) + 1
+}
+
+// insertFixup restores the red-black properties after inserting the red node z.
+func (m *Map[K, V]) insertFixup(z *node[K, V]) {
+	for isRed(z.parent) {
+		p := z.parent
+		g := p.parent
+		if p == g.left {
+			if u := g.right; isRed(u) {
+				p.red, u.red, g.red = false, false, true
+				z = g
+				continue
+			}
+			if z == p.right {
+				z = p
+				m.rotateLeft(z)
+				p = z.parent
+			}
+			p.red, g.red = false, true
+			m.rotateRight(g)
+		} else {
+			if u := g.left; isRed(u) {
+				p.red, u.red, g.red = false, false, true
+				z = g
+				continue
+			}
+			if z == p.left {
+				z = p
+				m.rotateRight(z)
+				p = z.parent
+			}
+			p.red, g.red = false, true
+			m.rotateLeft(g)
+		}
+	}
+	m.root.red = false
+}
+
+// deleteFixup restores the red-black properties after a black node has been
+// unlinked. x is the node that took its place, possibly nil, and parent is
+// the parent of x.
+func (m *Map[K, V]) deleteFixup(x, parent *node[K, V]) {
+	for x != m.root && !isRed(x) {
+		if x == parent.left {
+			w := parent.right
+			if w.red {
+				w.red, parent.red = false, true
+				m.rotateLeft(parent)
+				w = parent.right
+			}
+			if !isRed(w.left) && !isRed(w.right) {
+				w.red = true
+				x, parent = parent, parent.parent
+				continue
+			}
+			if !isRed(w.right) {
+				w.left.red, w.red = false, true
+				m.rotateRight(w)
+				w = parent.right
+			}
+			w.red, parent.red, w.right.red = parent.red, false, false
+			m.rotateLeft(parent)
+		} else {
+			w := parent.left
+			if w.red {
+				w.red, parent.red = false, true
+				m.rotateRight(parent)
+				w = parent.left
+			}
+			if !isRed(w.left) && !isRed(w.right) {
+				w.red = true
+				x, parent = parent, parent.parent
+				continue
+			}
+			if !isRed(w.left) {
+				w.right.red, w.red = false, true
+				m.rotateLeft(w)
+				w = parent.left
+			}
+			w.red, parent.red, w.left.red = parent.red, false, false
+			m.rotateRight(parent)
+		}
+		x = m.root
+	}
+	if x != nil {
+		x.red = false
+	}
+}
+
+// Min returns the smallest key and its value.
+// Returns zero values and false if the Map is empty.
+func (m *Map[K, V]) Min() (K, V, bool) {
+	if m.root == nil {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	n := minNode(m.root)
+	return n.key, n.value, true
+}
+
+// Max returns the largest key and its value.
+// Returns zero values and false if the Map is empty.
+func (m *Map[K, V]) Max() (K, V, bool) {
+	if m.root == nil {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	n := maxNode(m.root)
+	return n.key, n.value, true
+}
+
+// floorNode returns the node with the largest key less than or equal to key.
+func (m *Map[K, V]) floorNode(key K) *node[K, V] {
+	var found *node[K, V]
+	n := m.root
+	for n != nil {
+		if c := m.compare(key, n.key); c < 0 {
+			n = n.left
+		} else if c > 0 {
+			found = n
+			n = n.right
+		} else {
+			return n
+		}
+	}
+	return found
+}
+
+// ceilingNode returns the node with the smallest key greater than or equal to key.
+func (m *Map[K, V]) ceilingNode(key K) *node[K, V] {
+	var found *node[K, V]
+	n := m.root
+	for n != nil {
+		if c := m.compare(key, n.key); c < 0 {
+			found = n
+			n = n.left
+		} else if c > 0 {
+			n = n.right
+		} else {
+			return n
+		}
+	}
+	return found
+}
+
+// Floor returns the largest key less than or equal to key and its value.
+// Returns zero values and false if there is no such key.
+func (m *Map[K, V]) Floor(key K) (K, V, bool) {
+	if n := m.floorNode(key); n != nil {
+		return n.key, n.value, true
+	}
+	var zeroK K
+	var zeroV V
+	return zeroK, zeroV, false
+}
+
+// Ceiling returns the smallest key greater than or equal to key and its value.
+// Returns zero values and false if there is no such key.
+func (m *Map[K, V]) Ceiling(key K) (K, V, bool) {
+	if n := m.ceilingNode(key); n != nil {
+		return n.key, n.value, true
+	}
+	var zeroK K
+	var zeroV V
+	return zeroK, zeroV, false
+}
+
+// GetRank returns the rank (1-based index) of the key.
+// Returns 0 if the key is not found.
+func (m *Map[K, V]) GetRank(key K) int {
+	rank := 0
+	n := m.root
+	for n != nil {
+		if c := m.compare(key, n.key); c < 0 {
+			n = n.left
+		} else if c > 0 {
+			rank += size(n.left) + 1
+			n = n.right
+		} else {
+			return rank + size(n.left) + 1
+		}
+	}
+	return 0
+}
+
+// nodeByRank returns the node at the given rank (1-based index), or nil.
+func (m *Map[K, V]) nodeByRank(rank int) *node[K, V] {
+	if rank < 1 || rank > m.Len() {
+		return nil
+	}
+	n := m.root
+	for {
+		ls := size(n.left)
+		if rank <= ls {
+			n = n.left
+		} else if rank == ls+1 {
+			return n
+		} else {
+			rank -= ls + 1
+			n = n.right
+		}
+	}
+}
+
+// GetByRank returns the key and value at the specified rank (1-based index).
+// Returns zero values and false if the rank is out of bounds.
+func (m *Map[K, V]) GetByRank(rank int) (K, V, bool) {
+	if n := m.nodeByRank(rank); n != nil {
+		return n.key, n.value, true
+	}
+	var zeroK K
+	var zeroV V
+	return zeroK, zeroV, false
+}
+
+// Ascend iterates over the Map in ascending order.
+// It calls the iterator function for each element.
+// If the iterator returns false, the iteration stops.
+// The Map must not be modified during the iteration.
+func (m *Map[K, V]) Ascend(iterator func(key K, value V) bool) {
+	if m.root == nil {
+		return
+	}
+	for n := minNode(m.root); n != nil; n = next(n) {
+		if !iterator(n.key, n.value) {
+			return
+		}
+	}
+}
+
+// Descend iterates over the Map in descending order.
+// See Ascend for the iterator semantics.
+func (m *Map[K, V]) Descend(iterator func(key K, value V) bool) {
+	if m.root == nil {
+		return
+	}
+	for n := maxNode(m.root); n != nil; n = prev(n) {
+		if !iterator(n.key, n.value) {
+			return
+		}
+	}
+}
+
+// AscendGreaterOrEqual iterates in ascending order over the elements whose
+// keys are greater than or equal to pivot.
+func (m *Map[K, V]) AscendGreaterOrEqual(pivot K, iterator func(key K, value V) bool) {
+	for n := m.ceilingNode(pivot); n != nil; n = next(n) {
+		if !iterator(n.key, n.value) {
+			return
+		}
+	}
+}
+
+// DescendLessOrEqual iterates in descending order over the elements whose
+// keys are less than or equal to pivot.
+func (m *Map[K, V]) DescendLessOrEqual(pivot K, iterator func(key K, value V) bool) {
+	for n := m.floorNode(pivot); n != nil; n = prev(n) {
+		if !iterator(n.key, n.value) {
+			return
+		}
+	}
+}
+
+// AscendRange iterates in ascending order over the elements whose keys are
+// in the range [greaterOrEqual, lessThan).
+func (m *Map[K, V]) AscendRange(greaterOrEqual, lessThan K, iterator func(key K, value V) bool) {
+	for n := m.ceilingNode(greaterOrEqual); n != nil && m.compare(n.key, lessThan) < 0; n = next(n) {
+		if !iterator(n.key, n.value) {
+			return
+		}
+	}
+}
+
+// DescendRange iterates in descending order over the elements whose keys are
+// in the range (greaterThan, lessOrEqual].
+func (m *Map[K, V]) DescendRange(lessOrEqual, greaterThan K, iterator func(key K, value V) bool) {
+	for n := m.floorNode(lessOrEqual); n != nil && m.compare(greaterThan, n.key) < 0; n = prev(n) {
+		if !iterator(n.key, n.value) {
+			return
+		}
+	}
+}
+
+// AscendFromRank iterates in ascending order starting from the element at
+// the given rank (1-based index).
+func (m *Map[K, V]) AscendFromRank(rank int, iterator func(key K, value V) bool) {
+	for n := m.nodeByRank(rank); n != nil; n = next(n) {
+		if !iterator(n.key, n.value) {
+			return
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package rbtree
+
+import (
+	"cmp"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// checkInvariants verifies the red-black properties, the subtree sizes, the
+// parent pointers and the key order of m.
+func checkInvariants[K, V any](t *testing.T, m *Map[K, V]) {
+	t.Helper()
+	if isRed(m.root) {
+		t.Fatal("root is red")
+	}
+	if m.root != nil && m.root.parent != nil {
+		t.Fatal("root has a parent")
+	}
+
+	var walk func(n *node[K, V]) int
+	walk = func(n *node[K, V]) int {
+		if n == nil {
+			return 1
+		}
+		if n.red && (isRed(n.left) || isRed(n.right)) {
+			t.Fatalf("red node %v has a red child", n.key)
+		}
+		if n.left != nil && (n.left.parent != n || m.compare(n.left.key, n.key) >= 0) {
+			t.Fatalf("invalid left child of %v", n.key)
+		}
+		if n.right != nil && (n.right.parent != n || m.compare(n.key, n.right.key) >= 0) {
+			t.Fatalf("invalid right child of %v", n.key)
+		}
+		if n.size != size(n.left)+size(n.right)+1 {
+			t.Fatalf("invalid size of %v", n.key)
+		}
+		lh, rh := walk(n.left), walk(n.right)
+		if lh != rh {
+			t.Fatalf("unbalanced black height at %v", n.key)
+		}
+		if n.red {
+			return lh
+		}
+		return lh + 1
+	}
+	walk(m.root)
+}
+
+func TestMap_Basic(t *testing.T) {
+	m := New[int, string]()
+
+	m.Set(10, "10")
+	m.Set(5, "5")
+	m.Set(20, "20")
+	if m.Len() != 3 {
+		t.Errorf("Expected length 3, got %d", m.Len())
+	}
+
+	if val, ok := m.Get(10); !ok || val != "10" {
+		t.Errorf("Expected 10, got %v", val)
+	}
+	if _, ok := m.Get(15); ok {
+		t.Error("Expected key 15 not to exist")
+	}
+
+	m.Set(10, "10-new")
+	if val, ok := m.Get(10); !ok || val != "10-new" || m.Len() != 3 {
+		t.Errorf("Expected 10-new, got %v", val)
+	}
+
+	if !m.Delete(5) {
+		t.Error("Expected to delete key 5")
+	}
+	if m.Delete(5) {
+		t.Error("Expected key 5 to be deleted already")
+	}
+	if _, ok := m.Get(5); ok || m.Len() != 2 {
+		t.Error("Expected key 5 to be deleted")
+	}
+
+	empty := New[int, int]()
+	if _, _, ok := empty.Min(); ok {
+		t.Error("Expected empty map to have no min")
+	}
+	if _, _, ok := empty.Max(); ok {
+		t.Error("Expected empty map to have no max")
+	}
+}
+
+// entry is a composite key ordered by score descending, then by id.
+type entry struct {
+	score int
+	id    string
+}
+
+func compareEntry(a, b entry) int {
+	if a.score != b.score {
+		return cmp.Compare(b.score, a.score)
+	}
+	return cmp.Compare(a.id, b.id)
+}
+
+func TestMap_NewFunc(t *testing.T) {
+	m := NewFunc[entry, int](compareEntry)
+	m.Set(entry{10, "b"}, 1)
+	m.Set(entry{20, "c"}, 2)
+	m.Set(entry{10, "a"}, 3)
+	m.Set(entry{10, "b"}, 4)
+	checkInvariants(t, m)
+
+	var got []entry
+	m.Ascend(func(k entry, _ int) bool {
+		got = append(got, k)
+		return true
+	})
+	want := []entry{{20, "c"}, {10, "a"}, {10, "b"}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("Ascend: got %v, want %v", got, want)
+	}
+	if v, ok := m.Get(entry{10, "b"}); !ok || v != 4 {
+		t.Errorf("Expected 4, got %v", v)
+	}
+	if rank := m.GetRank(entry{10, "a"}); rank != 2 {
+		t.Errorf("Expected rank 2, got %d", rank)
+	}
+	if k, _, ok := m.Ceiling(entry{15, ""}); !ok || k != (entry{10, "a"}) {
+		t.Errorf("Expected ceiling {10 a}, got %v", k)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected NewFunc to panic on nil cmp")
+		}
+	}()
+	NewFunc[entry, int](nil)
+}
+
+func TestMap_Random(t *testing.T) {
+	m := New[int, int]()
+	ref := make(map[int]int)
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		k := r.Intn(2000)
+		if r.Intn(3) == 0 {
+			_, ok := ref[k]
+			if m.Delete(k) != ok {
+				t.Fatalf("Delete(%d) mismatch", k)
+			}
+			delete(ref, k)
+		} else {
+			m.Set(k, i)
+			ref[k] = i
+		}
+		if i%500 == 0 {
+			checkInvariants(t, m)
+		}
+	}
+	checkInvariants(t, m)
+
+	keys := make([]int, 0, len(ref))
+	for k := range ref {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	if m.Len() != len(keys) {
+		t.Fatalf("Expected length %d, got %d", len(keys), m.Len())
+	}
+
+	for i, k := range keys {
+		if v, ok := m.Get(k); !ok || v != ref[k] {
+			t.Fatalf("Get(%d) = %d, %v", k, v, ok)
+		}
+		if rank := m.GetRank(k); rank != i+1 {
+			t.Fatalf("GetRank(%d) = %d, want %d", k, rank, i+1)
+		}
+		if key, _, ok := m.GetByRank(i + 1); !ok || key != k {
+			t.Fatalf("GetByRank(%d) = %d, %v", i+1, key, ok)
+		}
+	}
+	if _, _, ok := m.GetByRank(0); ok {
+		t.Fatal("Expected rank 0 to be out of bounds")
+	}
+	if _, _, ok := m.GetByRank(len(keys) + 1); ok {
+		t.Fatal("Expected rank len+1 to be out of bounds")
+	}
+
+	for q := -1; q <= 2001; q++ {
+		i := sort.SearchInts(keys, q)
+		key, _, ok := m.Ceiling(q)
+		if wantOK := i < len(keys); ok != wantOK || (ok && key != keys[i]) {
+			t.Fatalf("Ceiling(%d) = %d, %v", q, key, ok)
+		}
+		j := i
+		if j == len(keys) || keys[j] != q {
+			j--
+		}
+		key, _, ok = m.Floor(q)
+		if wantOK := j >= 0; ok != wantOK || (ok && key != keys[j]) {
+			t.Fatalf("Floor(%d) = %d, %v", q, key, ok)
+		}
+	}
+
+	for _, k := range keys {
+		m.Delete(k)
+	}
+	checkInvariants(t, m)
+	if m.Len() != 0 || m.root != nil {
+		t.Fatal("Expected empty map")
+	}
+}
+
+func collect(iterate func(func(int, int) bool)) []int {
+	var keys []int
+	iterate(func(k, _ int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+func equal(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMap_Iterate(t *testing.T) {
+	m := New[int, int]()
+	for i := 0; i < 10; i++ {
+		m.Set(i*2, i)
+	}
+
+	tests := []struct {
+		name string
+		got  []int
+		want []int
+	}{
+		{"Ascend", collect(m.Ascend), []int{0, 2, 4, 6, 8, 10, 12, 14, 16, 18}},
+		{"Descend", collect(m.Descend), []int{18, 16, 14, 12, 10, 8, 6, 4, 2, 0}},
+		{"AscendGreaterOrEqual", collect(func(f func(int, int) bool) { m.AscendGreaterOrEqual(13, f) }), []int{14, 16, 18}},
+		{"DescendLessOrEqual", collect(func(f func(int, int) bool) { m.DescendLessOrEqual(4, f) }), []int{4, 2, 0}},
+		{"AscendRange", collect(func(f func(int, int) bool) { m.AscendRange(3, 10, f) }), []int{4, 6, 8}},
+		{"DescendRange", collect(func(f func(int, int) bool) { m.DescendRange(10, 3, f) }), []int{10, 8, 6, 4}},
+		{"AscendFromRank", collect(func(f func(int, int) bool) { m.AscendFromRank(9, f) }), []int{16, 18}},
+	}
+	for _, tt := range tests {
+		if !equal(tt.got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	count := 0
+	m.Ascend(func(k, v int) bool {
+		count++
+		return k < 4
+	})
+	if count != 3 {
+		t.Errorf("Expected iteration to stop after 3 elements, got %d", count)
+	}
+}
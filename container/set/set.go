@@ -1,6 +1,18 @@
+// Package set 提供泛型的集合容器
+//
+// nil *Set 视为空集合: 只读操作及删除操作可以在 nil 上调用, 添加操作要求非 nil.
+// 集合运算等返回新集合的方法总是返回非 nil 的集合, 参数为 nil 时同样视为空集合.
 package set
 
+import (
+	"bytes"
+	"encoding/json"
+	"iter"
+	"slices"
+)
+
 // Set 泛型的集合容器实现
+// 零值为可用的空集合.
 type Set[T comparable] struct {
 	values map[T]bool
 }
@@ -25,32 +37,265 @@ func NewSetWithValues[T comparable](values []T) *Set[T] {
 	return s
 }
 
+// newSetWithSize 构造预分配 size 个值空间的集合
+func newSetWithSize[T comparable](size int) *Set[T] {
+	return &Set[T]{
+		values: make(map[T]bool, size),
+	}
+}
+
 // Size 集合的大小
 func (s *Set[T]) Size() int {
+	if s == nil {
+		return 0
+	}
 	return len(s.values)
 }
 
 // Add 添加值
 func (s *Set[T]) Add(v T) {
+	if s.values == nil {
+		s.values = make(map[T]bool)
+	}
 	s.values[v] = true
 }
 
+// AddAll 添加多个值
+func (s *Set[T]) AddAll(values ...T) {
+	if s.values == nil {
+		s.values = make(map[T]bool, len(values))
+	}
+	for _, v := range values {
+		s.values[v] = true
+	}
+}
+
 // Del 删除值
 func (s *Set[T]) Del(v T) {
+	if s == nil {
+		return
+	}
 	delete(s.values, v)
 }
 
+// RemoveAll 删除多个值
+func (s *Set[T]) RemoveAll(values ...T) {
+	if s == nil {
+		return
+	}
+	for _, v := range values {
+		delete(s.values, v)
+	}
+}
+
+// Clear 删除所有值
+func (s *Set[T]) Clear() {
+	if s == nil {
+		return
+	}
+	clear(s.values)
+}
+
 // Contains 返回集合中是否包含提供的值
 func (s *Set[T]) Contains(v T) bool {
+	if s == nil {
+		return false
+	}
 	_, exist := s.values[v]
 	return exist
 }
 
 // ToSlice 将集合中的值转换为切片
 func (s *Set[T]) ToSlice() []T {
-	slice := make([]T, 0, len(s.values))
+	slice := make([]T, 0, s.Size())
+	if s == nil {
+		return slice
+	}
 	for v := range s.values {
 		slice = append(slice, v)
 	}
 	return slice
 }
+
+// All 返回遍历集合中所有值的迭代器, 遍历顺序不确定
+func (s *Set[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if s == nil {
+			return
+		}
+		for v := range s.values {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Clone 复制集合
+func (s *Set[T]) Clone() *Set[T] {
+	c := newSetWithSize[T](s.Size())
+	if s == nil {
+		return c
+	}
+	for v := range s.values {
+		c.values[v] = true
+	}
+	return c
+}
+
+// Union 返回 s 与 other 的并集
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	u := newSetWithSize[T](s.Size() + other.Size())
+	if s != nil {
+		for v := range s.values {
+			u.values[v] = true
+		}
+	}
+	if other != nil {
+		for v := range other.values {
+			u.values[v] = true
+		}
+	}
+	return u
+}
+
+// Intersection 返回 s 与 other 的交集
+func (s *Set[T]) Intersection(other *Set[T]) *Set[T] {
+	small, large := s, other
+	if small.Size() > large.Size() {
+		small, large = large, small
+	}
+
+	r := newSetWithSize[T](small.Size())
+	if small == nil {
+		return r
+	}
+	for v := range small.values {
+		if large.Contains(v) {
+			r.values[v] = true
+		}
+	}
+	return r
+}
+
+// Difference 返回 s 与 other 的差集, 即在 s 中而不在 other 中的值
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	r := newSetWithSize[T](s.Size())
+	if s == nil {
+		return r
+	}
+	for v := range s.values {
+		if !other.Contains(v) {
+			r.values[v] = true
+		}
+	}
+	return r
+}
+
+// SymmetricDifference 返回 s 与 other 的对称差集, 即仅在其中一个集合中的值
+func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	r := s.Difference(other)
+	if other == nil {
+		return r
+	}
+	for v := range other.values {
+		if !s.Contains(v) {
+			r.values[v] = true
+		}
+	}
+	return r
+}
+
+// IsSubset 返回 s 是否为 other 的子集
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if s.Size() > other.Size() {
+		return false
+	}
+	if s == nil {
+		return true
+	}
+	for v := range s.values {
+		if !other.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSuperset 返回 s 是否为 other 的超集
+func (s *Set[T]) IsSuperset(other *Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+// Equal 返回 s 与 other 是否包含相同的值
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	return s.Size() == other.Size() && s.IsSubset(other)
+}
+
+// Filter 返回由 s 中满足 keep 的值组成的新集合
+func (s *Set[T]) Filter(keep func(v T) bool) *Set[T] {
+	r := NewSet[T]()
+	if s == nil {
+		return r
+	}
+	for v := range s.values {
+		if keep(v) {
+			r.values[v] = true
+		}
+	}
+	return r
+}
+
+// Map 返回由 s 中的值经 f 转换后组成的新集合
+// 不同的值可能转换为相同的值, 因此结果的大小可能小于 s.
+func Map[T, U comparable](s *Set[T], f func(v T) U) *Set[U] {
+	r := newSetWithSize[U](s.Size())
+	if s == nil {
+		return r
+	}
+	for v := range s.values {
+		r.values[f(v)] = true
+	}
+	return r
+}
+
+// MarshalJSON 实现 json.Marshaler, 将集合编码为 JSON 数组
+// 数组元素按照其 JSON 编码排序, 因此相同集合的编码结果总是相同.
+func (s *Set[T]) MarshalJSON() ([]byte, error) {
+	elems := make([][]byte, 0, s.Size())
+	if s != nil {
+		for v := range s.values {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, data)
+		}
+	}
+	slices.SortFunc(elems, bytes.Compare)
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	buf.Write(bytes.Join(elems, []byte{','}))
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler, 以 JSON 数组中的值替换集合的内容
+// JSON null 不修改集合.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+
+	s.values = make(map[T]bool, len(values))
+	for _, v := range values {
+		s.values[v] = true
+	}
+	return nil
+}
@@ -0,0 +1,275 @@
+package set
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+// sorted 返回集合中排序后的值
+func sorted(s *Set[int]) []int {
+	values := s.ToSlice()
+	slices.Sort(values)
+	return values
+}
+
+func of(values ...int) *Set[int] {
+	return NewSetWithValues(values)
+}
+
+func TestSet_Basic(t *testing.T) {
+	var s Set[int]
+	s.Add(1)
+	s.AddAll(2, 3, 3)
+	if s.Size() != 3 || !s.Contains(2) || s.Contains(4) {
+		t.Fatalf("unexpected set %v", sorted(&s))
+	}
+
+	s.Del(1)
+	s.RemoveAll(2, 4)
+	if got := sorted(&s); !slices.Equal(got, []int{3}) {
+		t.Fatalf("expected [3], got %v", got)
+	}
+
+	s.Clear()
+	if s.Size() != 0 || s.Contains(3) {
+		t.Fatalf("expected empty set, got %v", sorted(&s))
+	}
+	s.Add(5)
+	if s.Size() != 1 {
+		t.Fatal("expected set to be usable after Clear")
+	}
+
+	var empty Set[int]
+	empty.AddAll()
+	if empty.Size() != 0 {
+		t.Fatal("expected AddAll without values to keep the set empty")
+	}
+}
+
+func TestSet_Nil(t *testing.T) {
+	var s *Set[int]
+	if s.Size() != 0 || s.Contains(1) || len(s.ToSlice()) != 0 {
+		t.Fatal("expected nil set to be empty")
+	}
+	for range s.All() {
+		t.Fatal("expected nil set to yield nothing")
+	}
+
+	// 删除操作在 nil 上为空操作
+	s.Del(1)
+	s.RemoveAll(1, 2)
+	s.Clear()
+
+	if c := s.Clone(); c == nil || c.Size() != 0 {
+		t.Fatal("expected Clone of nil set to be a non-nil empty set")
+	}
+	if f := s.Filter(func(int) bool { return true }); f == nil || f.Size() != 0 {
+		t.Fatal("expected Filter of nil set to be a non-nil empty set")
+	}
+	if m := Map(s, func(v int) int { return v }); m == nil || m.Size() != 0 {
+		t.Fatal("expected Map of nil set to be a non-nil empty set")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected Add on nil set to panic")
+		}
+	}()
+	s.Add(1)
+}
+
+func TestSet_Algebra(t *testing.T) {
+	var null *Set[int]
+	tests := []struct {
+		name                                       string
+		a, b                                       *Set[int]
+		union, intersection, difference, symmetric []int
+	}{
+		{"overlap", of(1, 2, 3), of(3, 4), []int{1, 2, 3, 4}, []int{3}, []int{1, 2}, []int{1, 2, 4}},
+		{"disjoint", of(1), of(2), []int{1, 2}, []int{}, []int{1}, []int{1, 2}},
+		{"equal", of(1, 2), of(2, 1), []int{1, 2}, []int{1, 2}, []int{}, []int{}},
+		{"subset", of(1), of(1, 2), []int{1, 2}, []int{1}, []int{}, []int{2}},
+		{"nil right", of(1, 2), null, []int{1, 2}, []int{}, []int{1, 2}, []int{1, 2}},
+		{"nil left", null, of(1, 2), []int{1, 2}, []int{}, []int{}, []int{1, 2}},
+		{"nil both", null, null, []int{}, []int{}, []int{}, []int{}},
+		{"empty", of(), of(1), []int{1}, []int{}, []int{}, []int{1}},
+	}
+	for _, tt := range tests {
+		results := []struct {
+			op   string
+			got  *Set[int]
+			want []int
+		}{
+			{"Union", tt.a.Union(tt.b), tt.union},
+			{"Intersection", tt.a.Intersection(tt.b), tt.intersection},
+			{"Difference", tt.a.Difference(tt.b), tt.difference},
+			{"SymmetricDifference", tt.a.SymmetricDifference(tt.b), tt.symmetric},
+		}
+		for _, r := range results {
+			if r.got == nil {
+				t.Fatalf("%s: %s returned nil", tt.name, r.op)
+			}
+			if got := sorted(r.got); !slices.Equal(got, r.want) {
+				t.Errorf("%s: %s = %v, want %v", tt.name, r.op, got, r.want)
+			}
+			if r.got == tt.a || r.got == tt.b {
+				t.Errorf("%s: %s must return a new set", tt.name, r.op)
+			}
+		}
+	}
+
+	a := of(1, 2)
+	a.Union(of(3)).Add(4)
+	if got := sorted(a); !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("expected operands to be unchanged, got %v", got)
+	}
+}
+
+func TestSet_Compare(t *testing.T) {
+	var null *Set[int]
+	tests := []struct {
+		name                      string
+		a, b                      *Set[int]
+		subset, superset, isEqual bool
+	}{
+		{"proper subset", of(1), of(1, 2), true, false, false},
+		{"proper superset", of(1, 2), of(2), false, true, false},
+		{"equal", of(1, 2), of(2, 1), true, true, true},
+		{"same size different values", of(1, 2), of(1, 3), false, false, false},
+		{"disjoint", of(1), of(2), false, false, false},
+		{"empty and non-empty", of(), of(1), true, false, false},
+		{"nil left", null, of(1), true, false, false},
+		{"nil right", of(1), null, false, true, false},
+		{"nil and empty", null, of(), true, true, true},
+		{"nil both", null, null, true, true, true},
+	}
+	for _, tt := range tests {
+		if got := tt.a.IsSubset(tt.b); got != tt.subset {
+			t.Errorf("%s: IsSubset = %v, want %v", tt.name, got, tt.subset)
+		}
+		if got := tt.a.IsSuperset(tt.b); got != tt.superset {
+			t.Errorf("%s: IsSuperset = %v, want %v", tt.name, got, tt.superset)
+		}
+		if got := tt.a.Equal(tt.b); got != tt.isEqual {
+			t.Errorf("%s: Equal = %v, want %v", tt.name, got, tt.isEqual)
+		}
+	}
+}
+
+func TestSet_Clone(t *testing.T) {
+	s := of(1, 2)
+	c := s.Clone()
+	c.Add(3)
+	s.Del(1)
+	if got := sorted(s); !slices.Equal(got, []int{2}) {
+		t.Errorf("original: got %v", got)
+	}
+	if got := sorted(c); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("clone: got %v", got)
+	}
+}
+
+func TestSet_All(t *testing.T) {
+	s := of(1, 2, 3, 4)
+	var got []int
+	for v := range s.All() {
+		got = append(got, v)
+	}
+	slices.Sort(got)
+	if !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Fatalf("All: got %v", got)
+	}
+
+	n := 0
+	for range s.All() {
+		n++
+		if n == 2 {
+			break
+		}
+	}
+	if n != 2 {
+		t.Fatalf("expected iteration to stop after 2 values, got %d", n)
+	}
+}
+
+func TestSet_FilterMap(t *testing.T) {
+	s := of(1, 2, 3, 4)
+
+	odd := s.Filter(func(v int) bool { return v%2 == 1 })
+	if got := sorted(odd); !slices.Equal(got, []int{1, 3}) {
+		t.Errorf("Filter: got %v", got)
+	}
+
+	// 不同的值转换为相同的值时结果会变小
+	parity := Map(s, func(v int) bool { return v%2 == 0 })
+	if parity.Size() != 2 || !parity.Contains(true) || !parity.Contains(false) {
+		t.Errorf("Map: got %v", parity.ToSlice())
+	}
+	if got := sorted(s); !slices.Equal(got, []int{1, 2, 3, 4}) {
+		t.Errorf("expected source set to be unchanged, got %v", got)
+	}
+}
+
+func TestSet_JSON(t *testing.T) {
+	var null *Set[int]
+	tests := []struct {
+		name string
+		s    *Set[int]
+		want string
+	}{
+		{"values", of(10, 2, 33, 1), "[1,10,2,33]"},
+		{"empty", of(), "[]"},
+		{"nil", null, "[]"},
+	}
+	for _, tt := range tests {
+		// 多次编码以检查结果与 map 的遍历顺序无关
+		for i := 0; i < 10; i++ {
+			data, err := tt.s.MarshalJSON()
+			if err != nil || string(data) != tt.want {
+				t.Fatalf("%s: MarshalJSON = %s, %v, want %s", tt.name, data, err, tt.want)
+			}
+		}
+	}
+
+	strs := NewSetWithValues([]string{"b", "a", "c"})
+	if data, err := json.Marshal(strs); err != nil || string(data) != `["a","b","c"]` {
+		t.Fatalf("json.Marshal = %s, %v", data, err)
+	}
+
+	s := of(7, 8)
+	if err := json.Unmarshal([]byte("[1,2,2,3]"), s); err != nil {
+		t.Fatal(err)
+	}
+	if got := sorted(s); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("UnmarshalJSON must replace the set, got %v", got)
+	}
+
+	if err := s.UnmarshalJSON([]byte("null")); err != nil {
+		t.Fatal(err)
+	}
+	if got := sorted(s); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Errorf("UnmarshalJSON null must leave the set unchanged, got %v", got)
+	}
+
+	if err := s.UnmarshalJSON([]byte(`["x"]`)); err == nil {
+		t.Error("expected error for invalid element")
+	}
+
+	var v struct {
+		P *Set[int]
+		V Set[int]
+	}
+	if err := json.Unmarshal([]byte(`{"P":[1],"V":[2]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if !v.P.Equal(of(1)) || !v.V.Equal(of(2)) {
+		t.Errorf("struct fields: got %v, %v", v.P.ToSlice(), v.V.ToSlice())
+	}
+
+	data, _ := json.Marshal(of(3, 1))
+	var back Set[int]
+	if err := json.Unmarshal(data, &back); err != nil || !back.Equal(of(1, 3)) {
+		t.Errorf("round trip: got %v, %v", back.ToSlice(), err)
+	}
+}
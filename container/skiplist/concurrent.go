@@ -0,0 +1,724 @@
+package skiplist
+
+import (
+	"cmp"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+
+	"github.com/godyy/gutils/buffer"
+	"github.com/godyy/gutils/buffer/bytes"
+)
+
+// maxOptimisticReads is the number of times a read is attempted without
+// locking before it falls back to the lock.
+const maxOptimisticReads = 4
+
+// iterateBatch is the number of elements an iteration collects per read.
+const iterateBatch = 32
+
+// cnode is a node of a ConcurrentSkipList. The key is immutable once the node
+// is linked, all other fields are accessed atomically so that readers can
+// traverse the list while it is modified.
+type cnode[K, V any] struct {
+	key   K
+	value atomic.Pointer[V]
+	prev  atomic.Pointer[cnode[K, V]] // prev is the previous node at level 0, nil for the first node
+	links []clink[K, V]
+}
+
+// clink is the link of a cnode at one level.
+type clink[K, V any] struct {
+	next atomic.Pointer[cnode[K, V]]
+	span atomic.Int64 // span is the distance to the next node at this level
+}
+
+func newCnode[K, V any](key K, value V, level int) *cnode[K, V] {
+	n := &cnode[K, V]{key: key, links: make([]clink[K, V], level)}
+	n.value.Store(&value)
+	return n
+}
+
+func (n *cnode[K, V]) next(i int) *cnode[K, V] {
+	return n.links[i].next.Load()
+}
+
+func (n *cnode[K, V]) span(i int) int {
+	return int(n.links[i].span.Load())
+}
+
+// ConcurrentSkipList is a SkipList that is safe for concurrent use.
+//
+// Reads are optimistic: Get, rank queries and the other lookups traverse the
+// list without locking, and are validated against a sequence number that
+// modifications increment before and after changing the structure. A read
+// that overlapped a modification is retried, and falls back to the lock after
+// a few attempts. Readers thus neither block each other nor write to shared
+// memory, unlike with a read-write lock.
+//
+// Modifications that insert or remove elements are serialized by a mutex:
+// every insertion or removal changes the spans of nodes on all levels above
+// it, which rank queries rely on, so writers would contend on the upper
+// levels even with per-node locks. Setting the value of an existing key does
+// not change the structure and does not disturb concurrent reads.
+//
+// Iterations read the list in batches and call the iterator outside of any
+// lock, so iterators may call any method of the list. Elements are visited in
+// order without duplicates, each as it was at some point during the
+// iteration, but the iteration as a whole is not a snapshot when the list is
+// modified concurrently. Encode and MarshalBinary write a consistent snapshot.
+type ConcurrentSkipList[K, V any] struct {
+	compare  func(a, b K) int
+	head     *cnode[K, V]
+	tail     atomic.Pointer[cnode[K, V]]
+	level    atomic.Int64
+	length   atomic.Int64
+	maxLevel int
+	p        float64
+
+	mu   sync.Mutex    // mu serializes modifications
+	seq  atomic.Uint64 // seq is odd while the structure is being modified
+	rand *rand.Rand    // rand is guarded by mu
+}
+
+// NewConcurrent creates a new ConcurrentSkipList configured by opts whose keys
+// are ordered by cmp.Compare.
+func NewConcurrent[K Ordered, V any](opts ...Option) *ConcurrentSkipList[K, V] {
+	return newConcurrent[K, V](cmp.Compare[K], buildConfig(opts))
+}
+
+// NewConcurrentFunc creates a new ConcurrentSkipList configured by opts whose
+// keys are ordered by cmp. See NewFunc.
+func NewConcurrentFunc[K, V any](cmp func(a, b K) int, opts ...Option) *ConcurrentSkipList[K, V] {
+	if cmp == nil {
+		panic("skiplist.NewConcurrentFunc: nil cmp")
+	}
+	return newConcurrent[K, V](cmp, buildConfig(opts))
+}
+
+func newConcurrent[K, V any](compare func(a, b K) int, c config) *ConcurrentSkipList[K, V] {
+	return &ConcurrentSkipList[K, V]{
+		compare:  compare,
+		head:     &cnode[K, V]{links: make([]clink[K, V], c.maxLevel)},
+		maxLevel: c.maxLevel,
+		p:        c.p,
+		rand:     c.rand,
+	}
+}
+
+// read runs fn, which must only read the list, without locking. fn is run
+// again if the list was modified meanwhile, and under the lock after
+// maxOptimisticReads attempts.
+func (c *ConcurrentSkipList[K, V]) read(fn func()) {
+	for i := 0; i < maxOptimisticReads; i++ {
+		seq := c.seq.Load()
+		if seq&1 == 0 {
+			fn()
+			if c.seq.Load() == seq {
+				return
+			}
+		}
+		runtime.Gosched()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	fn()
+}
+
+// beginWrite marks the start of a structural modification, c.mu must be held.
+// The returned function marks its end.
+func (c *ConcurrentSkipList[K, V]) beginWrite() func() {
+	c.seq.Add(1)
+	return func() { c.seq.Add(1) }
+}
+
+// randomLevel generates a random level for a new node, c.mu must be held.
+func (c *ConcurrentSkipList[K, V]) randomLevel() int {
+	level := 1
+	for c.rand.Float64() < c.p && level < c.maxLevel {
+		level++
+	}
+	return level
+}
+
+// search returns the last node before key at each level and the rank of
+// those nodes, c.mu must be held.
+func (c *ConcurrentSkipList[K, V]) search(key K) (update []*cnode[K, V], rank []int) {
+	update = make([]*cnode[K, V], c.maxLevel)
+	rank = make([]int, c.maxLevel)
+	current := c.head
+	update[0] = current
+
+	level := int(c.level.Load())
+	for i := level - 1; i >= 0; i-- {
+		if i < level-1 {
+			rank[i] = rank[i+1]
+		}
+		for n := current.next(i); n != nil && c.compare(n.key, key) < 0; n = current.next(i) {
+			rank[i] += current.span(i)
+			current = n
+		}
+		update[i] = current
+	}
+	return update, rank
+}
+
+// insert links a new node after update[0], where update and rank are the
+// result of search, c.mu must be held.
+func (c *ConcurrentSkipList[K, V]) insert(update []*cnode[K, V], rank []int, key K, value V) {
+	defer c.beginWrite()()
+
+	level := int(c.level.Load())
+	length := c.length.Load()
+	newLevel := c.randomLevel()
+	if newLevel > level {
+		for i := level; i < newLevel; i++ {
+			rank[i] = 0
+			update[i] = c.head
+			c.head.links[i].span.Store(length)
+		}
+		c.level.Store(int64(newLevel))
+	}
+
+	// Link the node at every level only after all its links are set.
+	newNode := newCnode(key, value, newLevel)
+	for i := 0; i < newLevel; i++ {
+		newNode.links[i].next.Store(update[i].next(i))
+		newNode.links[i].span.Store(int64(update[i].span(i) - (rank[0] - rank[i])))
+	}
+	if update[0] != c.head {
+		newNode.prev.Store(update[0])
+	}
+	for i := 0; i < newLevel; i++ {
+		update[i].links[i].next.Store(newNode)
+		update[i].links[i].span.Store(int64(rank[0] - rank[i] + 1))
+	}
+
+	// Increment span for untouched levels
+	for i := newLevel; i < max(level, newLevel); i++ {
+		update[i].links[i].span.Add(1)
+	}
+
+	if next := newNode.next(0); next != nil {
+		next.prev.Store(newNode)
+	} else {
+		c.tail.Store(newNode)
+	}
+
+	c.length.Add(1)
+}
+
+// deleteNode unlinks x, where update[i] is the last node before x at level i,
+// c.mu must be held. update remains valid for x's successor afterwards.
+func (c *ConcurrentSkipList[K, V]) deleteNode(x *cnode[K, V], update []*cnode[K, V]) {
+	level := int(c.level.Load())
+	for i := 0; i < level; i++ {
+		if update[i].next(i) == x {
+			update[i].links[i].span.Add(int64(x.span(i) - 1))
+			update[i].links[i].next.Store(x.next(i))
+		} else {
+			update[i].links[i].span.Add(-1)
+		}
+	}
+
+	if next := x.next(0); next != nil {
+		next.prev.Store(x.prev.Load())
+	} else {
+		c.tail.Store(x.prev.Load())
+	}
+
+	// Decrease the level of the list if necessary
+	for level > 0 && c.head.next(level-1) == nil {
+		level--
+	}
+	c.level.Store(int64(level))
+
+	c.length.Add(-1)
+}
+
+// Set inserts or updates a key-value pair.
+func (c *ConcurrentSkipList[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	update, rank := c.search(key)
+	if n := update[0].next(0); n != nil && c.compare(n.key, key) == 0 {
+		n.value.Store(&value)
+		return
+	}
+	c.insert(update, rank, key, value)
+}
+
+// Update atomically sets the value of key to the result of fn, which receives
+// the current value and whether the key is present.
+// fn must not modify the list.
+func (c *ConcurrentSkipList[K, V]) Update(key K, fn func(value V, ok bool) V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	update, rank := c.search(key)
+	if n := update[0].next(0); n != nil && c.compare(n.key, key) == 0 {
+		value := fn(*n.value.Load(), true)
+		n.value.Store(&value)
+		return
+	}
+	var zero V
+	c.insert(update, rank, key, fn(zero, false))
+}
+
+// Remove deletes a key-value pair.
+// Returns true if the key was found and removed, false otherwise.
+func (c *ConcurrentSkipList[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	update, _ := c.search(key)
+	x := update[0].next(0)
+	if x == nil || c.compare(x.key, key) != 0 {
+		return false
+	}
+	defer c.beginWrite()()
+	c.deleteNode(x, update)
+	return true
+}
+
+// RemoveRangeByRank removes the elements with ranks in [start, stop].
+// See SkipList.RemoveRangeByRank.
+func (c *ConcurrentSkipList[K, V]) RemoveRangeByRank(start, stop int) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	start = max(start, 1)
+	stop = min(stop, int(c.length.Load()))
+	if start > stop {
+		return 0
+	}
+	defer c.beginWrite()()
+
+	update := make([]*cnode[K, V], c.maxLevel)
+	current := c.head
+	traversed := 0
+
+	for i := int(c.level.Load()) - 1; i >= 0; i-- {
+		for n := current.next(i); n != nil && traversed+current.span(i) < start; n = current.next(i) {
+			traversed += current.span(i)
+			current = n
+		}
+		update[i] = current
+	}
+
+	current = current.next(0)
+	for rank := start; rank <= stop; rank++ {
+		next := current.next(0)
+		c.deleteNode(current, update)
+		current = next
+	}
+
+	return stop - start + 1
+}
+
+// RemoveRangeByKey removes the elements with keys in [greaterOrEqual, lessOrEqual].
+// See SkipList.RemoveRangeByKey.
+func (c *ConcurrentSkipList[K, V]) RemoveRangeByKey(greaterOrEqual, lessOrEqual K) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	update, _ := c.search(greaterOrEqual)
+	current := update[0].next(0)
+	if current == nil || c.compare(current.key, lessOrEqual) > 0 {
+		return 0
+	}
+	defer c.beginWrite()()
+
+	removed := 0
+	for current != nil && c.compare(current.key, lessOrEqual) <= 0 {
+		next := current.next(0)
+		c.deleteNode(current, update)
+		current = next
+		removed++
+	}
+
+	return removed
+}
+
+// lastBefore returns the last node whose key is less than key, or less than
+// or equal to key if inclusive, or the head if there is no such node.
+func (c *ConcurrentSkipList[K, V]) lastBefore(key K, inclusive bool) *cnode[K, V] {
+	current := c.head
+	for i := int(c.level.Load()) - 1; i >= 0; i-- {
+		for n := current.next(i); n != nil; n = current.next(i) {
+			if d := c.compare(n.key, key); d > 0 || d == 0 && !inclusive {
+				break
+			}
+			current = n
+		}
+	}
+	return current
+}
+
+// notHead returns n, or nil if n is the head.
+func (c *ConcurrentSkipList[K, V]) notHead(n *cnode[K, V]) *cnode[K, V] {
+	if n == c.head {
+		return nil
+	}
+	return n
+}
+
+// nodeByRank returns the node at the given rank (1-based index), or nil.
+func (c *ConcurrentSkipList[K, V]) nodeByRank(rank int) *cnode[K, V] {
+	if rank < 1 || rank > int(c.length.Load()) {
+		return nil
+	}
+
+	current := c.head
+	traversed := 0
+
+	for i := int(c.level.Load()) - 1; i >= 0; i-- {
+		for n := current.next(i); n != nil && traversed+current.span(i) <= rank; n = current.next(i) {
+			traversed += current.span(i)
+			current = n
+		}
+	}
+
+	if traversed == rank {
+		return current
+	}
+
+	return nil
+}
+
+// rank returns the rank (1-based index) of key, or 0 if it is not found.
+func (c *ConcurrentSkipList[K, V]) rank(key K) int {
+	rank := 0
+	current := c.head
+
+	for i := int(c.level.Load()) - 1; i >= 0; i-- {
+		for n := current.next(i); n != nil && c.compare(n.key, key) < 0; n = current.next(i) {
+			rank += current.span(i)
+			current = n
+		}
+	}
+
+	if n := current.next(0); n != nil && c.compare(n.key, key) == 0 {
+		return rank + 1
+	}
+
+	return 0
+}
+
+// readNode reads the key and value of the node returned by find.
+// Returns zero values and false if find returns nil.
+func (c *ConcurrentSkipList[K, V]) readNode(find func() *cnode[K, V]) (key K, value V, ok bool) {
+	c.read(func() {
+		var zeroK K
+		var zeroV V
+		key, value, ok = zeroK, zeroV, false
+		if n := find(); n != nil {
+			key, value, ok = n.key, *n.value.Load(), true
+		}
+	})
+	return key, value, ok
+}
+
+// Get retrieves the value associated with the given key.
+// Returns the value and true if found, otherwise zero value and false.
+func (c *ConcurrentSkipList[K, V]) Get(key K) (V, bool) {
+	_, value, ok := c.readNode(func() *cnode[K, V] {
+		n := c.lastBefore(key, false).next(0)
+		if n == nil || c.compare(n.key, key) != 0 {
+			return nil
+		}
+		return n
+	})
+	return value, ok
+}
+
+// GetRank returns the rank (1-based index) of the key.
+// Returns 0 if the key is not found.
+func (c *ConcurrentSkipList[K, V]) GetRank(key K) int {
+	var rank int
+	c.read(func() { rank = c.rank(key) })
+	return rank
+}
+
+// GetByRank returns the key and value at the specified rank (1-based index).
+// Returns zero values and false if the rank is out of bounds.
+func (c *ConcurrentSkipList[K, V]) GetByRank(rank int) (K, V, bool) {
+	return c.readNode(func() *cnode[K, V] { return c.nodeByRank(rank) })
+}
+
+// First returns the smallest key and its value.
+// Returns zero values and false if the list is empty.
+func (c *ConcurrentSkipList[K, V]) First() (K, V, bool) {
+	return c.readNode(func() *cnode[K, V] { return c.head.next(0) })
+}
+
+// Last returns the largest key and its value.
+// Returns zero values and false if the list is empty.
+func (c *ConcurrentSkipList[K, V]) Last() (K, V, bool) {
+	return c.readNode(c.tail.Load)
+}
+
+// Floor returns the largest key less than or equal to key and its value.
+// Returns zero values and false if there is no such key.
+func (c *ConcurrentSkipList[K, V]) Floor(key K) (K, V, bool) {
+	return c.readNode(func() *cnode[K, V] { return c.notHead(c.lastBefore(key, true)) })
+}
+
+// Ceiling returns the smallest key greater than or equal to key and its value.
+// Returns zero values and false if there is no such key.
+func (c *ConcurrentSkipList[K, V]) Ceiling(key K) (K, V, bool) {
+	return c.readNode(func() *cnode[K, V] { return c.lastBefore(key, false).next(0) })
+}
+
+// Len returns the number of elements.
+func (c *ConcurrentSkipList[K, V]) Len() int {
+	return int(c.length.Load())
+}
+
+// Stats returns statistics about the structure of the list.
+// See SkipList.Stats.
+func (c *ConcurrentSkipList[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	level := int(c.level.Load())
+	st := Stats{
+		Len:         int(c.length.Load()),
+		Level:       level,
+		MaxLevel:    c.maxLevel,
+		Probability: c.p,
+		LevelCounts: make([]int, level),
+	}
+
+	var n cnode[K, V]
+	var v V
+	linkSize := int(unsafe.Sizeof(n.links[0]))
+	nodeSize := int(unsafe.Sizeof(n))
+	valueSize := int(unsafe.Sizeof(v))
+
+	links := c.maxLevel // the head has maxLevel links
+	for current := c.head.next(0); current != nil; current = current.next(0) {
+		levels := len(current.links)
+		for i := 0; i < levels; i++ {
+			st.LevelCounts[i]++
+		}
+		links += levels
+	}
+	st.MemoryBytes = (st.Len+1)*nodeSize + st.Len*valueSize + links*linkSize
+
+	return st
+}
+
+// iterEntry is an element collected by an iteration.
+type iterEntry[K, V any] struct {
+	key   K
+	value *V
+}
+
+// iterate calls iterator for the elements from the node returned by first,
+// moving forward or backward while in reports true for their keys. Elements
+// are read in batches, and after a batch the iteration continues from the
+// element following the last key visited.
+func (c *ConcurrentSkipList[K, V]) iterate(first func() *cnode[K, V], forward bool, in func(key K) bool, iterator func(key K, value V) bool) {
+	batch := make([]iterEntry[K, V], 0, iterateBatch)
+	for resume := false; ; resume = true {
+		var last K
+		if resume {
+			last = batch[len(batch)-1].key
+		}
+
+		c.read(func() {
+			batch = batch[:0]
+			var current *cnode[K, V]
+			switch {
+			case !resume:
+				current = first()
+			case forward:
+				current = c.lastBefore(last, true).next(0)
+			default:
+				current = c.notHead(c.lastBefore(last, false))
+			}
+			for current != nil && len(batch) < iterateBatch && in(current.key) {
+				batch = append(batch, iterEntry[K, V]{key: current.key, value: current.value.Load()})
+				if forward {
+					current = current.next(0)
+				} else {
+					current = current.prev.Load()
+				}
+			}
+		})
+
+		for _, e := range batch {
+			if !iterator(e.key, *e.value) {
+				return
+			}
+		}
+		if len(batch) < iterateBatch {
+			return
+		}
+	}
+}
+
+// all reports true for every key.
+func all[K any](K) bool {
+	return true
+}
+
+// Ascend iterates over the list in ascending order. See SkipList.Ascend.
+func (c *ConcurrentSkipList[K, V]) Ascend(iterator func(key K, value V) bool) {
+	c.iterate(func() *cnode[K, V] { return c.head.next(0) }, true, all[K], iterator)
+}
+
+// Descend iterates over the list in descending order. See SkipList.Descend.
+func (c *ConcurrentSkipList[K, V]) Descend(iterator func(key K, value V) bool) {
+	c.iterate(c.tail.Load, false, all[K], iterator)
+}
+
+// AscendGreaterOrEqual iterates in ascending order over the elements whose
+// keys are greater than or equal to pivot.
+func (c *ConcurrentSkipList[K, V]) AscendGreaterOrEqual(pivot K, iterator func(key K, value V) bool) {
+	c.iterate(func() *cnode[K, V] { return c.lastBefore(pivot, false).next(0) }, true, all[K], iterator)
+}
+
+// DescendLessOrEqual iterates in descending order over the elements whose
+// keys are less than or equal to pivot.
+func (c *ConcurrentSkipList[K, V]) DescendLessOrEqual(pivot K, iterator func(key K, value V) bool) {
+	c.iterate(func() *cnode[K, V] { return c.notHead(c.lastBefore(pivot, true)) }, false, all[K], iterator)
+}
+
+// AscendRange iterates in ascending order over the elements whose keys are
+// in the range [greaterOrEqual, lessThan).
+func (c *ConcurrentSkipList[K, V]) AscendRange(greaterOrEqual, lessThan K, iterator func(key K, value V) bool) {
+	c.iterate(func() *cnode[K, V] { return c.lastBefore(greaterOrEqual, false).next(0) }, true,
+		func(key K) bool { return c.compare(key, lessThan) < 0 }, iterator)
+}
+
+// DescendRange iterates in descending order over the elements whose keys are
+// in the range (greaterThan, lessOrEqual].
+func (c *ConcurrentSkipList[K, V]) DescendRange(lessOrEqual, greaterThan K, iterator func(key K, value V) bool) {
+	c.iterate(func() *cnode[K, V] { return c.notHead(c.lastBefore(lessOrEqual, true)) }, false,
+		func(key K) bool { return c.compare(greaterThan, key) < 0 }, iterator)
+}
+
+// AscendFromRank iterates in ascending order starting from the element at
+// the given rank (1-based index).
+func (c *ConcurrentSkipList[K, V]) AscendFromRank(rank int, iterator func(key K, value V) bool) {
+	c.iterate(func() *cnode[K, V] { return c.nodeByRank(rank) }, true, all[K], iterator)
+}
+
+// DescendFromRank iterates in descending order starting from the element at
+// the given rank (1-based index).
+func (c *ConcurrentSkipList[K, V]) DescendFromRank(rank int, iterator func(key K, value V) bool) {
+	c.iterate(func() *cnode[K, V] { return c.nodeByRank(rank) }, false, all[K], iterator)
+}
+
+// newSkipList creates an empty SkipList with the same comparator and level
+// distribution as c, c.mu must be held.
+func (c *ConcurrentSkipList[K, V]) newSkipList() *SkipList[K, V] {
+	return newSkipList[K, V](c.compare, config{maxLevel: c.maxLevel, p: c.p, rand: c.rand})
+}
+
+// load replaces the contents of c with the elements of s, keeping their
+// levels, c.mu must be held.
+func (c *ConcurrentSkipList[K, V]) load(s *SkipList[K, V]) {
+	defer c.beginWrite()()
+
+	last := make([]*cnode[K, V], c.maxLevel)
+	for i := range last {
+		last[i] = c.head
+		c.head.links[i].next.Store(nil)
+		c.head.links[i].span.Store(int64(s.head.span[i]))
+	}
+
+	var prev *cnode[K, V]
+	for current := s.head.next[0]; current != nil; current = current.next[0] {
+		n := newCnode(current.key, current.value, len(current.next))
+		n.prev.Store(prev)
+		for i := range current.next {
+			n.links[i].span.Store(int64(current.span[i]))
+			last[i].links[i].next.Store(n)
+			last[i] = n
+		}
+		prev = n
+	}
+
+	c.tail.Store(prev)
+	c.level.Store(int64(s.level))
+	c.length.Store(int64(s.length))
+}
+
+// BuildFromSorted replaces the contents of the list with the given keys and
+// values. See SkipList.BuildFromSorted.
+func (c *ConcurrentSkipList[K, V]) BuildFromSorted(keys []K, values []V) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.newSkipList()
+	if err := s.BuildFromSorted(keys, values); err != nil {
+		return err
+	}
+	c.load(s)
+	return nil
+}
+
+// Encode writes the elements of the list to w. See SkipList.Encode.
+// Modifications are blocked while the list is encoded.
+func (c *ConcurrentSkipList[K, V]) Encode(w buffer.Writer, encodeKey func(w buffer.Writer, key K) error, encodeValue func(w buffer.Writer, value V) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := w.WriteUvarint64(uint64(c.length.Load())); err != nil {
+		return err
+	}
+	for current := c.head.next(0); current != nil; current = current.next(0) {
+		if err := encodeKey(w, current.key); err != nil {
+			return err
+		}
+		if err := encodeValue(w, *current.value.Load()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Decode replaces the contents of the list with the elements read from r.
+// See SkipList.Decode.
+func (c *ConcurrentSkipList[K, V]) Decode(r buffer.Reader, decodeKey func(r buffer.Reader) (K, error), decodeValue func(r buffer.Reader) (V, error)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.newSkipList()
+	if err := s.Decode(r, decodeKey, decodeValue); err != nil {
+		return err
+	}
+	c.load(s)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+// See SkipList.MarshalBinary.
+func (c *ConcurrentSkipList[K, V]) MarshalBinary() ([]byte, error) {
+	b := bytes.NewBuffer(nil)
+	if err := c.Encode(b, encodeValue[K], encodeValue[V]); err != nil {
+		return nil, err
+	}
+	return b.Data(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+// See SkipList.UnmarshalBinary.
+func (c *ConcurrentSkipList[K, V]) UnmarshalBinary(data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.newSkipList()
+	if err := s.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	c.load(s)
+	return nil
+}
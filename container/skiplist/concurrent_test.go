@@ -0,0 +1,457 @@
+package skiplist
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godyy/gutils/buffer"
+	"github.com/godyy/gutils/buffer/bytes"
+)
+
+// verify checks the ordering, links and ranks of c while holding its lock.
+func verify(c *ConcurrentSkipList[int, int]) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.seq.Load()&1 != 0 {
+		return fmt.Errorf("sequence %d left odd", c.seq.Load())
+	}
+	var keys []int
+	var prev *cnode[int, int]
+	for n := c.head.next(0); n != nil; n = n.next(0) {
+		if n.prev.Load() != prev {
+			return fmt.Errorf("wrong prev link of %d", n.key)
+		}
+		keys = append(keys, n.key)
+		prev = n
+	}
+	if c.tail.Load() != prev {
+		return fmt.Errorf("wrong tail")
+	}
+	if len(keys) != c.Len() {
+		return fmt.Errorf("iterated %d elements, length %d", len(keys), c.Len())
+	}
+	for i, key := range keys {
+		if i > 0 && keys[i-1] >= key {
+			return fmt.Errorf("keys out of order at rank %d", i+1)
+		}
+		if rank := c.rank(key); rank != i+1 {
+			return fmt.Errorf("rank(%d) = %d, want %d", key, rank, i+1)
+		}
+		if n := c.nodeByRank(i + 1); n == nil || n.key != key {
+			return fmt.Errorf("nodeByRank(%d) mismatch", i+1)
+		}
+	}
+	return nil
+}
+
+func TestConcurrentSkipList_Stress(t *testing.T) {
+	const (
+		writers = 8
+		readers = 4
+		ops     = 1000
+		keys    = 200
+	)
+	c := NewConcurrent[int, int]()
+
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for i := 0; i < ops; i++ {
+				k := r.Intn(keys)
+				switch r.Intn(4) {
+				case 0:
+					c.Remove(k)
+				case 1:
+					c.RemoveRangeByKey(k, k+2)
+				default:
+					c.Set(k, i)
+				}
+			}
+		}(int64(w))
+	}
+
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			r := rand.New(rand.NewSource(seed))
+			for i := 0; i < ops; i++ {
+				if i%50 == 0 {
+					if err := verify(c); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+				c.GetByRank(1 + r.Intn(keys))
+				c.GetRank(r.Intn(keys))
+				c.Get(r.Intn(keys))
+				c.Floor(r.Intn(keys))
+				c.DescendFromRank(c.Len(), func(int, int) bool { return r.Intn(10) != 0 })
+			}
+		}(int64(100 + i))
+	}
+
+	wg.Wait()
+	if err := verify(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConcurrentSkipList_Update(t *testing.T) {
+	const (
+		workers = 8
+		ops     = 1000
+		keys    = 10
+	)
+	c := NewConcurrentFunc[int, int](func(a, b int) int { return b - a })
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < ops; i++ {
+				c.Update(i%keys, func(value int, ok bool) int { return value + 1 })
+			}
+		}()
+	}
+	wg.Wait()
+
+	if c.Len() != keys {
+		t.Fatalf("Expected length %d, got %d", keys, c.Len())
+	}
+	if k, _, _ := c.First(); k != keys-1 {
+		t.Errorf("Expected first key %d, got %d", keys-1, k)
+	}
+	for k := 0; k < keys; k++ {
+		if v, _ := c.Get(k); v != workers*ops/keys {
+			t.Errorf("Get(%d) = %d, want %d", k, v, workers*ops/keys)
+		}
+	}
+}
+
+// collect returns the keys visited by an iteration.
+func collect(iterate func(iterator func(key, value int) bool)) []int {
+	var keys []int
+	iterate(func(key, _ int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	return keys
+}
+
+// TestConcurrentSkipList_Model checks that ConcurrentSkipList behaves like
+// SkipList for the same sequence of operations.
+func TestConcurrentSkipList_Model(t *testing.T) {
+	const keys = 300
+	s := New[int, int](WithSeed(7))
+	c := NewConcurrent[int, int](WithSeed(7))
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 20000; i++ {
+		k := r.Intn(keys)
+		switch r.Intn(8) {
+		case 0:
+			if s.Remove(k) != c.Remove(k) {
+				t.Fatalf("Remove(%d) mismatch", k)
+			}
+		case 1:
+			if s.RemoveRangeByKey(k, k+3) != c.RemoveRangeByKey(k, k+3) {
+				t.Fatalf("RemoveRangeByKey(%d) mismatch", k)
+			}
+		case 2:
+			if s.RemoveRangeByRank(k/10, k/10+2) != c.RemoveRangeByRank(k/10, k/10+2) {
+				t.Fatalf("RemoveRangeByRank(%d) mismatch", k/10)
+			}
+		default:
+			s.Set(k, i)
+			c.Set(k, i)
+		}
+		if i%500 != 0 {
+			continue
+		}
+
+		if err := verify(c); err != nil {
+			t.Fatal(err)
+		}
+		ss, cs := s.Stats(), c.Stats()
+		if ss.Len != cs.Len || ss.Level != cs.Level || fmt.Sprint(ss.LevelCounts) != fmt.Sprint(cs.LevelCounts) {
+			t.Fatalf("Stats mismatch: %+v, %+v", ss, cs)
+		}
+		for k := -1; k <= keys; k++ {
+			sv, sok := s.Get(k)
+			cv, cok := c.Get(k)
+			if sv != cv || sok != cok || s.GetRank(k) != c.GetRank(k) {
+				t.Fatalf("Get/GetRank(%d) mismatch", k)
+			}
+			sk, sv, sok := s.Floor(k)
+			ck, cv, cok := c.Floor(k)
+			if sk != ck || sv != cv || sok != cok {
+				t.Fatalf("Floor(%d) mismatch", k)
+			}
+			sk, sv, sok = s.Ceiling(k)
+			ck, cv, cok = c.Ceiling(k)
+			if sk != ck || sv != cv || sok != cok {
+				t.Fatalf("Ceiling(%d) mismatch", k)
+			}
+			sk, sv, sok = s.GetByRank(k)
+			ck, cv, cok = c.GetByRank(k)
+			if sk != ck || sv != cv || sok != cok {
+				t.Fatalf("GetByRank(%d) mismatch", k)
+			}
+		}
+		sk, sv, sok := s.First()
+		ck, cv, cok := c.First()
+		if sk != ck || sv != cv || sok != cok {
+			t.Fatal("First mismatch")
+		}
+		sk, sv, sok = s.Last()
+		ck, cv, cok = c.Last()
+		if sk != ck || sv != cv || sok != cok {
+			t.Fatal("Last mismatch")
+		}
+
+		lo, hi, rank := r.Intn(keys), r.Intn(keys), r.Intn(c.Len()+2)
+		iterations := []struct {
+			name string
+			s, c func(iterator func(key, value int) bool)
+		}{
+			{"Ascend", s.Ascend, c.Ascend},
+			{"Descend", s.Descend, c.Descend},
+			{"AscendGreaterOrEqual", func(f func(key, value int) bool) { s.AscendGreaterOrEqual(lo, f) }, func(f func(key, value int) bool) { c.AscendGreaterOrEqual(lo, f) }},
+			{"DescendLessOrEqual", func(f func(key, value int) bool) { s.DescendLessOrEqual(hi, f) }, func(f func(key, value int) bool) { c.DescendLessOrEqual(hi, f) }},
+			{"AscendRange", func(f func(key, value int) bool) { s.AscendRange(lo, hi, f) }, func(f func(key, value int) bool) { c.AscendRange(lo, hi, f) }},
+			{"DescendRange", func(f func(key, value int) bool) { s.DescendRange(hi, lo, f) }, func(f func(key, value int) bool) { c.DescendRange(hi, lo, f) }},
+			{"AscendFromRank", func(f func(key, value int) bool) { s.AscendFromRank(rank, f) }, func(f func(key, value int) bool) { c.AscendFromRank(rank, f) }},
+			{"DescendFromRank", func(f func(key, value int) bool) { s.DescendFromRank(rank, f) }, func(f func(key, value int) bool) { c.DescendFromRank(rank, f) }},
+		}
+		for _, it := range iterations {
+			if want, got := collect(it.s), collect(it.c); fmt.Sprint(want) != fmt.Sprint(got) {
+				t.Fatalf("%s: got %v, want %v", it.name, got, want)
+			}
+		}
+	}
+}
+
+// TestConcurrentSkipList_LockFreeReads checks that reads do not take the lock
+// held by modifications.
+func TestConcurrentSkipList_LockFreeReads(t *testing.T) {
+	c := NewConcurrent[int, int]()
+	for i := 0; i < 100; i++ {
+		c.Set(i, i)
+	}
+
+	c.mu.Lock()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Get(50)
+		c.GetRank(50)
+		c.GetByRank(50)
+		c.Floor(50)
+		c.Ascend(func(int, int) bool { return true })
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("reads blocked by the lock")
+	}
+	c.mu.Unlock()
+}
+
+// TestConcurrentSkipList_Iterate checks that iterations running concurrently
+// with modifications visit keys in order, and that iterators may modify the
+// list.
+func TestConcurrentSkipList_Iterate(t *testing.T) {
+	const keys = 1000
+	c := NewConcurrent[int, int]()
+	for i := 0; i < keys; i++ {
+		c.Set(i, i)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		r := rand.New(rand.NewSource(1))
+		for i := 0; i < 5000; i++ {
+			if k := r.Intn(keys); r.Intn(2) == 0 {
+				c.Remove(k)
+			} else {
+				c.Set(k, i)
+			}
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		asc := collect(c.Ascend)
+		for j := 1; j < len(asc); j++ {
+			if asc[j-1] >= asc[j] {
+				t.Fatalf("Ascend out of order: %d, %d", asc[j-1], asc[j])
+			}
+		}
+		desc := collect(c.Descend)
+		for j := 1; j < len(desc); j++ {
+			if desc[j-1] <= desc[j] {
+				t.Fatalf("Descend out of order: %d, %d", desc[j-1], desc[j])
+			}
+		}
+	}
+	wg.Wait()
+
+	c.Ascend(func(key, value int) bool {
+		c.Remove(key)
+		return true
+	})
+	if c.Len() != 0 {
+		t.Fatalf("Expected an empty list, got %d elements", c.Len())
+	}
+	if err := verify(c); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// mutexSkipList is the baseline of a SkipList guarded by a single mutex.
+type mutexSkipList struct {
+	mu   sync.Mutex
+	list *SkipList[int, int]
+}
+
+func (m *mutexSkipList) Set(key, value int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.list.Set(key, value)
+}
+
+func (m *mutexSkipList) Get(key int) (int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.list.Get(key)
+}
+
+func (m *mutexSkipList) GetRank(key int) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.list.GetRank(key)
+}
+
+type concurrentMap interface {
+	Set(key, value int)
+	Get(key int) (int, bool)
+	GetRank(key int) int
+}
+
+// benchmarkConcurrent runs a parallel workload where writePercent percent of
+// the operations are Set and the rest alternate between Get and GetRank.
+func benchmarkConcurrent(b *testing.B, writePercent int) {
+	const limit = 100000
+	impls := []struct {
+		name string
+		m    concurrentMap
+	}{
+		{"Mutex", &mutexSkipList{list: New[int, int]()}},
+		{"Concurrent", NewConcurrent[int, int]()},
+	}
+	for _, impl := range impls {
+		for i := 0; i < limit; i++ {
+			impl.m.Set(i, i)
+		}
+		b.Run(impl.name, func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				r := rand.New(rand.NewSource(rand.Int63()))
+				for pb.Next() {
+					k := r.Intn(limit)
+					switch n := r.Intn(100); {
+					case n < writePercent:
+						impl.m.Set(k, k)
+					case n%2 == 0:
+						impl.m.Get(k)
+					default:
+						impl.m.GetRank(k)
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkConcurrentSkipList_ReadOnly(b *testing.B) {
+	benchmarkConcurrent(b, 0)
+}
+
+func BenchmarkConcurrentSkipList_ReadMostly(b *testing.B) {
+	benchmarkConcurrent(b, 10)
+}
+
+func BenchmarkConcurrentSkipList_WriteHeavy(b *testing.B) {
+	benchmarkConcurrent(b, 50)
+}
+
+func TestConcurrentSkipList_Persist(t *testing.T) {
+	keys := make([]int, 1000)
+	for i := range keys {
+		keys[i] = i
+	}
+	c := NewConcurrent[int, int](WithSeed(1))
+	if err := c.BuildFromSorted(keys, keys); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(c); err != nil {
+		t.Fatal(err)
+	}
+
+	// Snapshots taken while writers run must each be consistent.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 500; i++ {
+			c.Remove(i)
+			c.Set(1000+i, i)
+		}
+	}()
+	for i := 0; i < 20; i++ {
+		data, err := c.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		restored := NewConcurrent[int, int]()
+		if err := restored.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if err := verify(restored); err != nil {
+			t.Fatal(err)
+		}
+		if n := restored.Len(); n < 999 || n > 1000 {
+			t.Fatalf("Expected 999 or 1000 elements in snapshot, got %d", n)
+		}
+	}
+	wg.Wait()
+
+	b := bytes.NewBuffer(nil)
+	err := c.Encode(b,
+		func(w buffer.Writer, key int) error { _, err := w.WriteVarint64(int64(key)); return err },
+		func(w buffer.Writer, value int) error { _, err := w.WriteVarint64(int64(value)); return err })
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := NewConcurrent[int, int]()
+	err = restored.Decode(b,
+		func(r buffer.Reader) (int, error) { v, err := r.ReadVarint64(); return int(v), err },
+		func(r buffer.Reader) (int, error) { v, err := r.ReadVarint64(); return int(v), err })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if k, v, _ := restored.First(); restored.Len() != 1000 || k != 500 || v != 500 {
+		t.Fatalf("Unexpected decoded list: len %d, first %d: %d", restored.Len(), k, v)
+	}
+	if k, v, _ := restored.Last(); k != 1499 || v != 499 {
+		t.Fatalf("Unexpected last element %d: %d", k, v)
+	}
+}
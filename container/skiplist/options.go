@@ -0,0 +1,97 @@
+package skiplist
+
+import (
+	"math/rand"
+	"time"
+)
+
+// config configures a SkipList.
+type config struct {
+	maxLevel int
+	p        float64
+	rand     *rand.Rand
+}
+
+// Option configures a SkipList.
+type Option interface {
+	apply(*config)
+}
+
+// buildConfig merges opts into the default configuration.
+func buildConfig(opts []Option) config {
+	c := config{
+		maxLevel: defaultMaxLevel,
+		p:        defaultP,
+	}
+	for _, opt := range opts {
+		opt.apply(&c)
+	}
+	if c.rand == nil {
+		c.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return c
+}
+
+type seedOption int64
+
+func (o seedOption) apply(c *config) {
+	c.rand = rand.New(rand.NewSource(int64(o)))
+}
+
+// WithSeed seeds the random level generator, making the shape of the list
+// reproducible for the same sequence of operations. By default the generator
+// is seeded with the current time.
+func WithSeed(seed int64) Option {
+	return seedOption(seed)
+}
+
+type randOption struct {
+	rand *rand.Rand
+}
+
+func (o randOption) apply(c *config) {
+	c.rand = o.rand
+}
+
+// WithRand uses r to generate random levels. r is not safe for concurrent use,
+// so it must not be shared with other goroutines or lists.
+// It panics if r is nil.
+func WithRand(r *rand.Rand) Option {
+	if r == nil {
+		panic("skiplist.WithRand: nil rand")
+	}
+	return randOption{rand: r}
+}
+
+type maxLevelOption int
+
+func (o maxLevelOption) apply(c *config) {
+	c.maxLevel = int(o)
+}
+
+// WithMaxLevel limits the number of levels, 32 by default. The list stays
+// efficient for up to about (1/p)^maxLevel elements.
+// It panics if n < 1.
+func WithMaxLevel(n int) Option {
+	if n < 1 {
+		panic("skiplist.WithMaxLevel: n must be at least 1")
+	}
+	return maxLevelOption(n)
+}
+
+type probabilityOption float64
+
+func (o probabilityOption) apply(c *config) {
+	c.p = float64(o)
+}
+
+// WithProbability sets the probability that a node is promoted to the next
+// level, 0.25 by default. Nodes have 1/(1-p) levels on average: a lower p
+// uses less memory, a higher p makes searches take fewer steps per level.
+// It panics if p is not in the range (0, 1).
+func WithProbability(p float64) Option {
+	if !(p > 0 && p < 1) {
+		panic("skiplist.WithProbability: p must be in the range (0, 1)")
+	}
+	return probabilityOption(p)
+}
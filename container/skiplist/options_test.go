@@ -0,0 +1,111 @@
+package skiplist
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// levels returns the number of levels of every node in ascending order.
+func levels(s *SkipList[int, int]) []int {
+	var result []int
+	for current := s.head.next[0]; current != nil; current = current.next[0] {
+		result = append(result, len(current.next))
+	}
+	return result
+}
+
+func TestSkipList_Options(t *testing.T) {
+	build := func(opts ...Option) *SkipList[int, int] {
+		sl := New[int, int](opts...)
+		for i := 0; i < 1000; i++ {
+			sl.Set(i*7%1000, i)
+		}
+		return sl
+	}
+
+	if !equalKeys(levels(build(WithSeed(42))), levels(build(WithSeed(42)))) {
+		t.Error("Expected WithSeed to produce the same levels")
+	}
+	if !equalKeys(levels(build(WithRand(rand.New(rand.NewSource(7))))), levels(build(WithSeed(7)))) {
+		t.Error("Expected WithRand to produce the same levels as WithSeed")
+	}
+
+	sl := build(WithSeed(1), WithMaxLevel(3), WithProbability(0.9))
+	if sl.Level() != 3 {
+		t.Errorf("Expected level 3, got %d", sl.Level())
+	}
+	for _, l := range levels(sl) {
+		if l > 3 {
+			t.Fatalf("Expected at most 3 levels, got %d", l)
+		}
+	}
+	checkStructure(t, sl)
+
+	// BuildFromSorted keeps the configuration.
+	keys := make([]int, 100)
+	for i := range keys {
+		keys[i] = i
+	}
+	if err := sl.BuildFromSorted(keys, keys); err != nil {
+		t.Fatal(err)
+	}
+	if sl.maxLevel != 3 || sl.p != 0.9 || len(sl.head.next) != 3 {
+		t.Error("Expected BuildFromSorted to keep the configuration")
+	}
+	checkStructure(t, sl)
+
+	for name, f := range map[string]func(){
+		"WithMaxLevel(0)":      func() { WithMaxLevel(0) },
+		"WithProbability(0)":   func() { WithProbability(0) },
+		"WithProbability(1)":   func() { WithProbability(1) },
+		"WithProbability(NaN)": func() { WithProbability(math.NaN()) },
+		"WithRand(nil)":        func() { WithRand(nil) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Expected %s to panic", name)
+				}
+			}()
+			f()
+		}()
+	}
+}
+
+func TestSkipList_Stats(t *testing.T) {
+	sl := New[int, int](WithSeed(1))
+	if st := sl.Stats(); st.Len != 0 || st.Level != 0 || len(st.LevelCounts) != 0 || st.MemoryBytes <= 0 {
+		t.Errorf("Unexpected stats of empty list: %+v", st)
+	}
+
+	for i := 0; i < 10000; i++ {
+		sl.Set(i, i)
+	}
+	st := sl.Stats()
+	if st.Len != 10000 || st.Level != sl.Level() || st.MaxLevel != 32 || st.Probability != 0.25 {
+		t.Errorf("Unexpected stats: %+v", st)
+	}
+	if len(st.LevelCounts) != st.Level || st.LevelCounts[0] != st.Len {
+		t.Fatalf("Unexpected level counts: %v", st.LevelCounts)
+	}
+
+	want := make([]int, st.Level)
+	links := 0
+	for _, l := range levels(sl) {
+		for i := 0; i < l; i++ {
+			want[i]++
+		}
+		links += l
+	}
+	if !equalKeys(st.LevelCounts, want) {
+		t.Errorf("LevelCounts = %v, want %v", st.LevelCounts, want)
+	}
+	// About 1/(1-p) links per node.
+	if links < 12000 || links > 14700 {
+		t.Errorf("Unexpected number of links %d", links)
+	}
+	if st.MemoryBytes < links*16 {
+		t.Errorf("MemoryBytes %d is less than the size of the links", st.MemoryBytes)
+	}
+}
@@ -0,0 +1,195 @@
+package skiplist
+
+import (
+	"errors"
+	"io"
+
+	"github.com/godyy/gutils/buffer"
+	"github.com/godyy/gutils/buffer/bytes"
+	pkg_errors "github.com/pkg/errors"
+)
+
+// ErrNotSorted is returned when the input of BuildFromSorted or Decode is not
+// in strictly ascending key order.
+var ErrNotSorted = errors.New("skiplist: keys are not in strictly ascending order")
+
+// builder appends nodes in ascending key order to an empty SkipList in O(1)
+// time each, without searching for the insert position.
+type builder[K, V any] struct {
+	s        *SkipList[K, V]
+	last     []*node[K, V] // last[i] is the last node at level i
+	lastRank []int         // lastRank[i] is the rank of last[i]
+}
+
+// newBuilder creates a builder for an empty SkipList with the same comparator
+// and level distribution as s.
+func (s *SkipList[K, V]) newBuilder() *builder[K, V] {
+	t := newSkipList[K, V](s.compare, config{maxLevel: s.maxLevel, p: s.p, rand: s.rand})
+
+	b := &builder[K, V]{
+		s:        t,
+		last:     make([]*node[K, V], t.maxLevel),
+		lastRank: make([]int, t.maxLevel),
+	}
+	for i := range b.last {
+		b.last[i] = t.head
+	}
+	return b
+}
+
+// append adds key and value after the last node.
+// Returns ErrNotSorted if key is not greater than the last key.
+func (b *builder[K, V]) append(key K, value V) error {
+	s := b.s
+	if s.tail != nil && s.compare(s.tail.key, key) >= 0 {
+		return ErrNotSorted
+	}
+
+	level := s.randomLevel()
+	s.level = max(s.level, level)
+	rank := s.length + 1
+	n := &node[K, V]{
+		key:   key,
+		value: value,
+		prev:  s.tail,
+		next:  make([]*node[K, V], level),
+		span:  make([]int, level),
+	}
+	for i := 0; i < level; i++ {
+		b.last[i].next[i] = n
+		b.last[i].span[i] = rank - b.lastRank[i]
+		b.last[i], b.lastRank[i] = n, rank
+	}
+
+	s.tail = n
+	s.length = rank
+	return nil
+}
+
+// finish sets the spans of the last nodes and returns the built SkipList.
+func (b *builder[K, V]) finish() *SkipList[K, V] {
+	for i := 0; i < b.s.level; i++ {
+		b.last[i].span[i] = b.s.length - b.lastRank[i]
+	}
+	return b.s
+}
+
+// BuildFromSorted replaces the contents of the SkipList with the given keys
+// and values in O(n) time. keys must be in strictly ascending order,
+// otherwise ErrNotSorted is returned and the SkipList is left unchanged.
+// It panics if keys and values have different lengths.
+func (s *SkipList[K, V]) BuildFromSorted(keys []K, values []V) error {
+	if len(keys) != len(values) {
+		panic("skiplist.BuildFromSorted: keys and values have different lengths")
+	}
+
+	b := s.newBuilder()
+	for i := range keys {
+		if err := b.append(keys[i], values[i]); err != nil {
+			return pkg_errors.WithMessagef(err, "index %d", i)
+		}
+	}
+	*s = *b.finish()
+	return nil
+}
+
+// Encode writes the number of elements followed by each key and value in
+// ascending order to w, using encodeKey and encodeValue.
+func (s *SkipList[K, V]) Encode(w buffer.Writer, encodeKey func(w buffer.Writer, key K) error, encodeValue func(w buffer.Writer, value V) error) error {
+	if _, err := w.WriteUvarint64(uint64(s.length)); err != nil {
+		return err
+	}
+	for current := s.head.next[0]; current != nil; current = current.next[0] {
+		if err := encodeKey(w, current.key); err != nil {
+			return err
+		}
+		if err := encodeValue(w, current.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Decode replaces the contents of the SkipList with the elements written by
+// Encode, reading them from r with decodeKey and decodeValue. The list is
+// built in O(n) time; on error it is left unchanged.
+func (s *SkipList[K, V]) Decode(r buffer.Reader, decodeKey func(r buffer.Reader) (K, error), decodeValue func(r buffer.Reader) (V, error)) error {
+	t, err := s.decode(r, decodeKey, decodeValue)
+	if err != nil {
+		return err
+	}
+	*s = *t
+	return nil
+}
+
+// decode reads the elements written by Encode into a new SkipList with the
+// same comparator and level distribution as s.
+func (s *SkipList[K, V]) decode(r buffer.Reader, decodeKey func(r buffer.Reader) (K, error), decodeValue func(r buffer.Reader) (V, error)) (*SkipList[K, V], error) {
+	n, err := r.ReadUvarint64()
+	if err != nil {
+		return nil, err
+	}
+
+	b := s.newBuilder()
+	for i := uint64(0); i < n; i++ {
+		key, err := decodeKey(r)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		value, err := decodeValue(r)
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		if err := b.append(key, value); err != nil {
+			return nil, pkg_errors.WithMessagef(err, "index %d", i)
+		}
+	}
+	return b.finish(), nil
+}
+
+// unexpectedEOF converts io.EOF to io.ErrUnexpectedEOF, as the element count
+// promised more data.
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. Keys and values are
+// encoded with bytes.EncodeValue, so their types must be supported by
+// bytes.Marshal.
+func (s *SkipList[K, V]) MarshalBinary() ([]byte, error) {
+	b := bytes.NewBuffer(nil)
+	if err := s.Encode(b, encodeValue[K], encodeValue[V]); err != nil {
+		return nil, err
+	}
+	return b.Data(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler, decoding data
+// produced by MarshalBinary. The SkipList must have been created with New or
+// NewFunc, and its comparator must order the keys as when they were encoded.
+// On error the SkipList is left unchanged.
+func (s *SkipList[K, V]) UnmarshalBinary(data []byte) error {
+	b := bytes.NewBuffer(data)
+	t, err := s.decode(b, decodeValue[K], decodeValue[V])
+	if err != nil {
+		return err
+	}
+	if b.Readable() > 0 {
+		return pkg_errors.Errorf("skiplist: %d bytes of trailing data", b.Readable())
+	}
+	*s = *t
+	return nil
+}
+
+func encodeValue[T any](w buffer.Writer, v T) error {
+	return bytes.EncodeValue(w, &v)
+}
+
+func decodeValue[T any](r buffer.Reader) (T, error) {
+	var v T
+	err := bytes.DecodeValue(r, &v)
+	return v, err
+}
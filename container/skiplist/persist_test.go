@@ -0,0 +1,172 @@
+package skiplist
+
+import (
+	"cmp"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/godyy/gutils/buffer"
+	"github.com/godyy/gutils/buffer/bytes"
+)
+
+func TestSkipList_BuildFromSorted(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 100, 5000} {
+		keys := make([]int, n)
+		values := make([]int, n)
+		for i := range keys {
+			keys[i], values[i] = i*2, i
+		}
+
+		sl := New[int, int]()
+		sl.Set(-1, -1)
+		if err := sl.BuildFromSorted(keys, values); err != nil {
+			t.Fatalf("n=%d: %v", n, err)
+		}
+		checkStructure(t, sl)
+		for i, k := range keys {
+			if rank := sl.GetRank(k); rank != i+1 {
+				t.Fatalf("n=%d: GetRank(%d) = %d, want %d", n, k, rank, i+1)
+			}
+			if key, v, ok := sl.GetByRank(i + 1); !ok || key != k || v != i {
+				t.Fatalf("n=%d: GetByRank(%d) = %d, %d, %v", n, i+1, key, v, ok)
+			}
+		}
+
+		// The built list must keep working with regular updates.
+		for i := 0; i < n; i += 3 {
+			sl.Remove(i * 2)
+			sl.Set(i*2+1, i)
+		}
+		sl.RemoveRangeByRank(n/3, n/2)
+		checkStructure(t, sl)
+	}
+
+	sl := New[int, int]()
+	sl.Set(1, 1)
+	err := sl.BuildFromSorted([]int{1, 3, 3}, []int{1, 2, 3})
+	if !errors.Is(err, ErrNotSorted) {
+		t.Fatalf("Expected ErrNotSorted, got %v", err)
+	}
+	if sl.Len() != 1 {
+		t.Fatal("Expected list to be unchanged on error")
+	}
+}
+
+type record struct {
+	Name  string
+	Score int64 `buf:"varint"`
+	Tags  []string
+}
+
+func TestSkipList_MarshalBinary(t *testing.T) {
+	sl := New[string, record]()
+	sl.Set("b", record{Name: "bob", Score: -3, Tags: []string{"x"}})
+	sl.Set("a", record{Name: "alice", Score: 10})
+	sl.Set("c", record{Name: "carol"})
+
+	data, err := sl.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := New[string, record]()
+	if err := got.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if got.Len() != 3 {
+		t.Fatalf("Expected length 3, got %d", got.Len())
+	}
+	sl.Ascend(func(key string, want record) bool {
+		v, ok := got.Get(key)
+		if !ok || v.Name != want.Name || v.Score != want.Score || len(v.Tags) != len(want.Tags) {
+			t.Errorf("Get(%q) = %+v, want %+v", key, v, want)
+		}
+		return true
+	})
+	if k, _, _ := got.Last(); k != "c" {
+		t.Errorf("Expected last key c, got %q", k)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"empty", nil, io.EOF},
+		{"truncated", data[:len(data)-1], io.ErrUnexpectedEOF},
+		{"missing elements", data[:1], io.ErrUnexpectedEOF},
+	}
+	for _, tt := range tests {
+		if err := got.UnmarshalBinary(tt.data); err != tt.err {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.err, err)
+		}
+	}
+	if err := got.UnmarshalBinary(append(data, 0)); err == nil {
+		t.Error("Expected error for trailing data")
+	}
+
+	// Data encoded in ascending order cannot be loaded into a descending list.
+	desc := NewFunc[string, record](func(a, b string) int { return -cmp.Compare(a, b) })
+	if err := desc.UnmarshalBinary(data); !errors.Is(err, ErrNotSorted) {
+		t.Errorf("Expected ErrNotSorted, got %v", err)
+	}
+	if got.Len() != 3 || desc.Len() != 0 {
+		t.Error("Expected lists to be unchanged on error")
+	}
+}
+
+func TestSkipList_EncodeDecode(t *testing.T) {
+	sl := NewFunc[int32, string](func(a, b int32) int { return cmp.Compare(b, a) })
+	for i := int32(0); i < 100; i++ {
+		sl.Set(i, string(rune('a'+i%26)))
+	}
+
+	b := bytes.NewBuffer(nil)
+	err := sl.Encode(b,
+		func(w buffer.Writer, key int32) error { _, err := w.WriteVarint32(key); return err },
+		func(w buffer.Writer, value string) error { return w.WriteString(value) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := NewFunc[int32, string](func(a, b int32) int { return cmp.Compare(b, a) })
+	err = got.Decode(b,
+		func(r buffer.Reader) (int32, error) { return r.ReadVarint32() },
+		func(r buffer.Reader) (string, error) { return r.ReadString() })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Readable() != 0 {
+		t.Fatalf("Expected all data to be read, %d bytes left", b.Readable())
+	}
+	for rank := 1; rank <= 100; rank++ {
+		k1, v1, _ := sl.GetByRank(rank)
+		k2, v2, ok := got.GetByRank(rank)
+		if !ok || k1 != k2 || v1 != v2 {
+			t.Fatalf("GetByRank(%d) = %d, %q, want %d, %q", rank, k2, v2, k1, v1)
+		}
+	}
+}
+
+func BenchmarkSkipList_BuildFromSorted(b *testing.B) {
+	const n = 100000
+	keys := make([]int, n)
+	for i := range keys {
+		keys[i] = i
+	}
+
+	b.Run("BuildFromSorted", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			_ = New[int, int]().BuildFromSorted(keys, keys)
+		}
+	})
+	b.Run("Set", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sl := New[int, int]()
+			for _, k := range keys {
+				sl.Set(k, k)
+			}
+		}
+	})
+}
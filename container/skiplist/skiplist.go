@@ -1,8 +1,8 @@
 package skiplist
 
 import (
+	"cmp"
 	"math/rand"
-	"time"
 )
 
 // Ordered represents types that can be compared with <, <=, >, >=.
@@ -18,9 +18,10 @@ const (
 	defaultP        = 0.25
 )
 
-type node[K Ordered, V any] struct {
+type node[K, V any] struct {
 	key   K
 	value V
+	prev  *node[K, V] // prev is the previous node at level 0, nil for the first node
 	next  []*node[K, V]
 	span  []int // span[i] is the distance to the next node at level i
 }
@@ -28,8 +29,11 @@ type node[K Ordered, V any] struct {
 // SkipList is a probabilistic data structure that allows O(log n) search complexity
 // as well as O(log n) insertion complexity within an ordered sequence of elements.
 // It also supports efficient rank calculation (finding the position of an element).
-type SkipList[K Ordered, V any] struct {
+// A SkipList must be created with New or NewFunc.
+type SkipList[K, V any] struct {
+	compare  func(a, b K) int
 	head     *node[K, V]
+	tail     *node[K, V]
 	maxLevel int
 	level    int
 	p        float64
@@ -37,17 +41,34 @@ type SkipList[K Ordered, V any] struct {
 	length   int
 }
 
-// New creates a new SkipList with default parameters.
-func New[K Ordered, V any]() *SkipList[K, V] {
+// New creates a new SkipList configured by opts.
+// Keys are ordered by cmp.Compare.
+func New[K Ordered, V any](opts ...Option) *SkipList[K, V] {
+	return newSkipList[K, V](cmp.Compare[K], buildConfig(opts))
+}
+
+// NewFunc creates a new SkipList configured by opts whose keys are ordered by
+// cmp, which must return a negative number when a < b, a positive number when
+// a > b and zero when a == b, and define a strict weak ordering.
+// It panics if cmp is nil.
+func NewFunc[K, V any](cmp func(a, b K) int, opts ...Option) *SkipList[K, V] {
+	if cmp == nil {
+		panic("skiplist.NewFunc: nil cmp")
+	}
+	return newSkipList[K, V](cmp, buildConfig(opts))
+}
+
+func newSkipList[K, V any](compare func(a, b K) int, c config) *SkipList[K, V] {
 	return &SkipList[K, V]{
+		compare: compare,
 		head: &node[K, V]{
-			next: make([]*node[K, V], defaultMaxLevel),
-			span: make([]int, defaultMaxLevel),
+			next: make([]*node[K, V], c.maxLevel),
+			span: make([]int, c.maxLevel),
 		},
-		maxLevel: defaultMaxLevel,
+		maxLevel: c.maxLevel,
 		level:    0,
-		p:        defaultP,
-		rand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		p:        c.p,
+		rand:     c.rand,
 		length:   0,
 	}
 }
@@ -75,7 +96,7 @@ func (s *SkipList[K, V]) Set(key K, value V) {
 		} else {
 			rank[i] = rank[i+1]
 		}
-		for current.next[i] != nil && current.next[i].key < key {
+		for current.next[i] != nil && s.compare(current.next[i].key, key) < 0 {
 			rank[i] += current.span[i]
 			current = current.next[i]
 		}
@@ -83,7 +104,7 @@ func (s *SkipList[K, V]) Set(key K, value V) {
 	}
 
 	// If the key already exists, update the value
-	if current.next[0] != nil && current.next[0].key == key {
+	if current.next[0] != nil && s.compare(current.next[0].key, key) == 0 {
 		current.next[0].value = value
 		return
 	}
@@ -110,6 +131,9 @@ func (s *SkipList[K, V]) Set(key K, value V) {
 	}
 
 	// Insert the node by updating pointers and spans
+	if update[0] != s.head {
+		newNode.prev = update[0]
+	}
 	for i := 0; i < newLevel; i++ {
 		newNode.next[i] = update[i].next[i]
 		update[i].next[i] = newNode
@@ -123,6 +147,12 @@ func (s *SkipList[K, V]) Set(key K, value V) {
 		update[i].span[i]++
 	}
 
+	if newNode.next[0] != nil {
+		newNode.next[0].prev = newNode
+	} else {
+		s.tail = newNode
+	}
+
 	s.length++
 }
 
@@ -131,13 +161,13 @@ func (s *SkipList[K, V]) Set(key K, value V) {
 func (s *SkipList[K, V]) Get(key K) (V, bool) {
 	current := s.head
 	for i := s.level - 1; i >= 0; i-- {
-		for current.next[i] != nil && current.next[i].key < key {
+		for current.next[i] != nil && s.compare(current.next[i].key, key) < 0 {
 			current = current.next[i]
 		}
 	}
 
 	current = current.next[0]
-	if current != nil && current.key == key {
+	if current != nil && s.compare(current.key, key) == 0 {
 		return current.value, true
 	}
 
@@ -152,34 +182,45 @@ func (s *SkipList[K, V]) Remove(key K) bool {
 	current := s.head
 
 	for i := s.level - 1; i >= 0; i-- {
-		for current.next[i] != nil && current.next[i].key < key {
+		for current.next[i] != nil && s.compare(current.next[i].key, key) < 0 {
 			current = current.next[i]
 		}
 		update[i] = current
 	}
 
 	current = current.next[0]
+	if current != nil && s.compare(current.key, key) == 0 {
+		s.deleteNode(current, update)
+		return true
+	}
 
-	if current != nil && current.key == key {
-		for i := 0; i < s.level; i++ {
-			if update[i].next[i] == current {
-				update[i].span[i] += current.span[i] - 1
-				update[i].next[i] = current.next[i]
-			} else {
-				update[i].span[i]--
-			}
-		}
+	return false
+}
 
-		// Decrease the level of the skip list if necessary
-		for s.level > 0 && s.head.next[s.level-1] == nil {
-			s.level--
+// deleteNode unlinks x from the SkipList, where update[i] is the last node
+// before x at level i. update remains valid for x's successor afterwards.
+func (s *SkipList[K, V]) deleteNode(x *node[K, V], update []*node[K, V]) {
+	for i := 0; i < s.level; i++ {
+		if update[i].next[i] == x {
+			update[i].span[i] += x.span[i] - 1
+			update[i].next[i] = x.next[i]
+		} else {
+			update[i].span[i]--
 		}
+	}
 
-		s.length--
-		return true
+	if x.next[0] != nil {
+		x.next[0].prev = x.prev
+	} else {
+		s.tail = x.prev
 	}
 
-	return false
+	// Decrease the level of the skip list if necessary
+	for s.level > 0 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+
+	s.length--
 }
 
 // GetRank returns the rank (1-based index) of the key.
@@ -189,28 +230,43 @@ func (s *SkipList[K, V]) GetRank(key K) int {
 	current := s.head
 
 	for i := s.level - 1; i >= 0; i-- {
-		for current.next[i] != nil && current.next[i].key < key {
+		for current.next[i] != nil && s.compare(current.next[i].key, key) < 0 {
 			rank += current.span[i]
 			current = current.next[i]
 		}
 	}
 
 	// Check if the next node is the key
-	if current.next[0] != nil && current.next[0].key == key {
+	if current.next[0] != nil && s.compare(current.next[0].key, key) == 0 {
 		return rank + 1
 	}
 
 	return 0
 }
 
-// GetByRank returns the key and value at the specified rank (1-based index).
-// Returns zero values and false if the rank is out of bounds.
-func (s *SkipList[K, V]) GetByRank(rank int) (K, V, bool) {
-	var zeroK K
-	var zeroV V
+// Search returns the rank (1-based index) of the first element whose key
+// satisfies f, or Len()+1 if there is no such element. Like sort.Search, f
+// must be false for some (possibly empty) prefix of the keys in ascending
+// order and true for the rest. It is useful to find a bound that cannot be
+// expressed as a key, such as the first key with a given prefix.
+func (s *SkipList[K, V]) Search(f func(key K) bool) int {
+	rank := 0
+	current := s.head
+
+	for i := s.level - 1; i >= 0; i-- {
+		for current.next[i] != nil && !f(current.next[i].key) {
+			rank += current.span[i]
+			current = current.next[i]
+		}
+	}
+
+	return rank + 1
+}
 
+// nodeByRank returns the node at the given rank (1-based index), or nil.
+func (s *SkipList[K, V]) nodeByRank(rank int) *node[K, V] {
 	if rank < 1 || rank > s.length {
-		return zeroK, zeroV, false
+		return nil
 	}
 
 	current := s.head
@@ -224,10 +280,75 @@ func (s *SkipList[K, V]) GetByRank(rank int) (K, V, bool) {
 	}
 
 	if traversed == rank {
-		return current.key, current.value, true
+		return current
 	}
 
-	return zeroK, zeroV, false
+	return nil
+}
+
+// GetByRank returns the key and value at the specified rank (1-based index).
+// Returns zero values and false if the rank is out of bounds.
+func (s *SkipList[K, V]) GetByRank(rank int) (K, V, bool) {
+	return result(s.nodeByRank(rank))
+}
+
+// result returns the key and value of n, or zero values and false if n is nil.
+func result[K, V any](n *node[K, V]) (K, V, bool) {
+	if n == nil {
+		var zeroK K
+		var zeroV V
+		return zeroK, zeroV, false
+	}
+	return n.key, n.value, true
+}
+
+// ceilingNode returns the first node whose key is greater than or equal to key, or nil.
+func (s *SkipList[K, V]) ceilingNode(key K) *node[K, V] {
+	current := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for current.next[i] != nil && s.compare(current.next[i].key, key) < 0 {
+			current = current.next[i]
+		}
+	}
+	return current.next[0]
+}
+
+// floorNode returns the last node whose key is less than or equal to key, or nil.
+func (s *SkipList[K, V]) floorNode(key K) *node[K, V] {
+	current := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for current.next[i] != nil && s.compare(current.next[i].key, key) <= 0 {
+			current = current.next[i]
+		}
+	}
+	if current == s.head {
+		return nil
+	}
+	return current
+}
+
+// First returns the smallest key and its value.
+// Returns zero values and false if the SkipList is empty.
+func (s *SkipList[K, V]) First() (K, V, bool) {
+	return result(s.head.next[0])
+}
+
+// Last returns the largest key and its value.
+// Returns zero values and false if the SkipList is empty.
+func (s *SkipList[K, V]) Last() (K, V, bool) {
+	return result(s.tail)
+}
+
+// Floor returns the largest key less than or equal to key and its value.
+// Returns zero values and false if there is no such key.
+func (s *SkipList[K, V]) Floor(key K) (K, V, bool) {
+	return result(s.floorNode(key))
+}
+
+// Ceiling returns the smallest key greater than or equal to key and its value.
+// Returns zero values and false if there is no such key.
+func (s *SkipList[K, V]) Ceiling(key K) (K, V, bool) {
+	return result(s.ceilingNode(key))
 }
 
 // Len returns the number of elements in the SkipList.
@@ -247,3 +368,131 @@ func (s *SkipList[K, V]) Ascend(iterator func(key K, value V) bool) {
 		current = current.next[0]
 	}
 }
+
+// Descend iterates over the SkipList in descending order.
+// See Ascend for the iterator semantics.
+func (s *SkipList[K, V]) Descend(iterator func(key K, value V) bool) {
+	for current := s.tail; current != nil; current = current.prev {
+		if !iterator(current.key, current.value) {
+			return
+		}
+	}
+}
+
+// AscendGreaterOrEqual iterates in ascending order over the elements whose
+// keys are greater than or equal to pivot.
+func (s *SkipList[K, V]) AscendGreaterOrEqual(pivot K, iterator func(key K, value V) bool) {
+	for current := s.ceilingNode(pivot); current != nil; current = current.next[0] {
+		if !iterator(current.key, current.value) {
+			return
+		}
+	}
+}
+
+// DescendLessOrEqual iterates in descending order over the elements whose
+// keys are less than or equal to pivot.
+func (s *SkipList[K, V]) DescendLessOrEqual(pivot K, iterator func(key K, value V) bool) {
+	for current := s.floorNode(pivot); current != nil; current = current.prev {
+		if !iterator(current.key, current.value) {
+			return
+		}
+	}
+}
+
+// AscendRange iterates in ascending order over the elements whose keys are
+// in the range [greaterOrEqual, lessThan).
+func (s *SkipList[K, V]) AscendRange(greaterOrEqual, lessThan K, iterator func(key K, value V) bool) {
+	for current := s.ceilingNode(greaterOrEqual); current != nil && s.compare(current.key, lessThan) < 0; current = current.next[0] {
+		if !iterator(current.key, current.value) {
+			return
+		}
+	}
+}
+
+// DescendRange iterates in descending order over the elements whose keys are
+// in the range (greaterThan, lessOrEqual].
+func (s *SkipList[K, V]) DescendRange(lessOrEqual, greaterThan K, iterator func(key K, value V) bool) {
+	for current := s.floorNode(lessOrEqual); current != nil && s.compare(greaterThan, current.key) < 0; current = current.prev {
+		if !iterator(current.key, current.value) {
+			return
+		}
+	}
+}
+
+// AscendFromRank iterates in ascending order starting from the element at
+// the given rank (1-based index).
+func (s *SkipList[K, V]) AscendFromRank(rank int, iterator func(key K, value V) bool) {
+	for current := s.nodeByRank(rank); current != nil; current = current.next[0] {
+		if !iterator(current.key, current.value) {
+			return
+		}
+	}
+}
+
+// DescendFromRank iterates in descending order starting from the element at
+// the given rank (1-based index).
+func (s *SkipList[K, V]) DescendFromRank(rank int, iterator func(key K, value V) bool) {
+	for current := s.nodeByRank(rank); current != nil; current = current.prev {
+		if !iterator(current.key, current.value) {
+			return
+		}
+	}
+}
+
+// RemoveRangeByRank removes the elements with ranks (1-based index) in the
+// range [start, stop], like Redis ZREMRANGEBYRANK. Out-of-bounds ranks are
+// clamped to the list. Returns the number of elements removed.
+func (s *SkipList[K, V]) RemoveRangeByRank(start, stop int) int {
+	start = max(start, 1)
+	stop = min(stop, s.length)
+	if start > stop {
+		return 0
+	}
+
+	update := make([]*node[K, V], s.maxLevel)
+	current := s.head
+	traversed := 0
+
+	for i := s.level - 1; i >= 0; i-- {
+		for current.next[i] != nil && (traversed+current.span[i]) < start {
+			traversed += current.span[i]
+			current = current.next[i]
+		}
+		update[i] = current
+	}
+
+	current = current.next[0]
+	for rank := start; rank <= stop; rank++ {
+		next := current.next[0]
+		s.deleteNode(current, update)
+		current = next
+	}
+
+	return stop - start + 1
+}
+
+// RemoveRangeByKey removes the elements whose keys are in the range
+// [greaterOrEqual, lessOrEqual], like Redis ZREMRANGEBYSCORE.
+// Returns the number of elements removed.
+func (s *SkipList[K, V]) RemoveRangeByKey(greaterOrEqual, lessOrEqual K) int {
+	update := make([]*node[K, V], s.maxLevel)
+	current := s.head
+
+	for i := s.level - 1; i >= 0; i-- {
+		for current.next[i] != nil && s.compare(current.next[i].key, greaterOrEqual) < 0 {
+			current = current.next[i]
+		}
+		update[i] = current
+	}
+
+	removed := 0
+	current = current.next[0]
+	for current != nil && s.compare(current.key, lessOrEqual) <= 0 {
+		next := current.next[0]
+		s.deleteNode(current, update)
+		current = next
+		removed++
+	}
+
+	return removed
+}
@@ -1,6 +1,7 @@
 package skiplist
 
 import (
+	"cmp"
 	"math/rand"
 	"sort"
 	"testing"
@@ -178,3 +179,297 @@ func BenchmarkSkipList_GetRank(b *testing.B) {
 		sl.GetRank(rand.Intn(limit))
 	}
 }
+
+// checkStructure verifies the ordering, spans, backward pointers and tail of s.
+func checkStructure(t *testing.T, s *SkipList[int, int]) {
+	t.Helper()
+	rank := make(map[*node[int, int]]int)
+	var prev *node[int, int]
+	i := 0
+	for n := s.head.next[0]; n != nil; n = n.next[0] {
+		i++
+		rank[n] = i
+		if n.prev != prev {
+			t.Fatalf("invalid prev pointer at rank %d", i)
+		}
+		if prev != nil && !(prev.key < n.key) {
+			t.Fatalf("keys out of order at rank %d", i)
+		}
+		prev = n
+	}
+	if i != s.length || s.tail != prev {
+		t.Fatalf("invalid length %d or tail, counted %d", s.length, i)
+	}
+	for level := 0; level < s.level; level++ {
+		for n := s.head; n != nil; n = n.next[level] {
+			to := s.length + 1
+			if n.next[level] != nil {
+				to = rank[n.next[level]]
+			}
+			if n.next[level] != nil && n.span[level] != to-rank[n] {
+				t.Fatalf("invalid span at level %d rank %d", level, rank[n])
+			}
+		}
+	}
+}
+
+func collectKeys(iterate func(func(int, int) bool)) []int {
+	var keys []int
+	iterate(func(k, _ int) bool {
+		keys = append(keys, k)
+		return true
+	})
+	return keys
+}
+
+func equalKeys(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSkipList_Bounds(t *testing.T) {
+	sl := New[int, int]()
+	if _, _, ok := sl.First(); ok {
+		t.Error("Expected empty list to have no first element")
+	}
+	if _, _, ok := sl.Last(); ok {
+		t.Error("Expected empty list to have no last element")
+	}
+
+	for i := 1; i <= 5; i++ {
+		sl.Set(i*10, i)
+	}
+	if k, _, ok := sl.First(); !ok || k != 10 {
+		t.Errorf("Expected first key 10, got %d", k)
+	}
+	if k, _, ok := sl.Last(); !ok || k != 50 {
+		t.Errorf("Expected last key 50, got %d", k)
+	}
+
+	tests := []struct {
+		key            int
+		floor, ceiling int // 0 means no such key
+	}{
+		{5, 0, 10},
+		{10, 10, 10},
+		{25, 20, 30},
+		{50, 50, 50},
+		{55, 50, 0},
+	}
+	for _, tt := range tests {
+		if k, _, ok := sl.Floor(tt.key); ok != (tt.floor != 0) || k != tt.floor {
+			t.Errorf("Floor(%d) = %d, %v, want %d", tt.key, k, ok, tt.floor)
+		}
+		if k, _, ok := sl.Ceiling(tt.key); ok != (tt.ceiling != 0) || k != tt.ceiling {
+			t.Errorf("Ceiling(%d) = %d, %v, want %d", tt.key, k, ok, tt.ceiling)
+		}
+	}
+}
+
+func TestSkipList_Range(t *testing.T) {
+	sl := New[int, int]()
+	for i := 0; i < 10; i++ {
+		sl.Set(i*2, i)
+	}
+
+	tests := []struct {
+		name string
+		got  []int
+		want []int
+	}{
+		{"Descend", collectKeys(sl.Descend), []int{18, 16, 14, 12, 10, 8, 6, 4, 2, 0}},
+		{"AscendGreaterOrEqual", collectKeys(func(f func(int, int) bool) { sl.AscendGreaterOrEqual(13, f) }), []int{14, 16, 18}},
+		{"DescendLessOrEqual", collectKeys(func(f func(int, int) bool) { sl.DescendLessOrEqual(4, f) }), []int{4, 2, 0}},
+		{"AscendRange", collectKeys(func(f func(int, int) bool) { sl.AscendRange(3, 10, f) }), []int{4, 6, 8}},
+		{"AscendRange empty", collectKeys(func(f func(int, int) bool) { sl.AscendRange(10, 3, f) }), nil},
+		{"DescendRange", collectKeys(func(f func(int, int) bool) { sl.DescendRange(10, 3, f) }), []int{10, 8, 6, 4}},
+		{"AscendFromRank", collectKeys(func(f func(int, int) bool) { sl.AscendFromRank(9, f) }), []int{16, 18}},
+		{"AscendFromRank out of bounds", collectKeys(func(f func(int, int) bool) { sl.AscendFromRank(11, f) }), nil},
+		{"DescendFromRank", collectKeys(func(f func(int, int) bool) { sl.DescendFromRank(3, f) }), []int{4, 2, 0}},
+	}
+	for _, tt := range tests {
+		if !equalKeys(tt.got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+
+	count := 0
+	sl.Descend(func(k, v int) bool {
+		count++
+		return k > 14
+	})
+	if count != 3 {
+		t.Errorf("Expected iteration to stop after 3 elements, got %d", count)
+	}
+}
+
+func TestSkipList_RemoveRange(t *testing.T) {
+	sl := New[int, int]()
+	for i := 1; i <= 10; i++ {
+		sl.Set(i, i)
+	}
+
+	if n := sl.RemoveRangeByRank(2, 4); n != 3 {
+		t.Errorf("Expected 3 elements removed, got %d", n)
+	}
+	if got := collectKeys(sl.Ascend); !equalKeys(got, []int{1, 5, 6, 7, 8, 9, 10}) {
+		t.Errorf("After RemoveRangeByRank: got %v", got)
+	}
+	if n := sl.RemoveRangeByRank(6, 100); n != 2 {
+		t.Errorf("Expected 2 elements removed, got %d", n)
+	}
+	if n := sl.RemoveRangeByRank(3, 2); n != 0 {
+		t.Errorf("Expected no elements removed, got %d", n)
+	}
+
+	if n := sl.RemoveRangeByKey(5, 7); n != 3 {
+		t.Errorf("Expected 3 elements removed, got %d", n)
+	}
+	if got := collectKeys(sl.Ascend); !equalKeys(got, []int{1, 8}) {
+		t.Errorf("After RemoveRangeByKey: got %v", got)
+	}
+	if got := collectKeys(sl.Descend); !equalKeys(got, []int{8, 1}) {
+		t.Errorf("Descend after RemoveRangeByKey: got %v", got)
+	}
+	checkStructure(t, sl)
+
+	r := rand.New(rand.NewSource(1))
+	ref := make(map[int]bool)
+	for i := 0; i < 2000; i++ {
+		k := r.Intn(500)
+		sl.Set(k, k)
+		ref[k] = true
+	}
+	for i := 0; i < 50; i++ {
+		keys := make([]int, 0, len(ref))
+		for k := range ref {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+
+		if i%2 == 0 {
+			lo := r.Intn(500)
+			hi := lo + r.Intn(20)
+			want := 0
+			for _, k := range keys {
+				if k >= lo && k <= hi {
+					delete(ref, k)
+					want++
+				}
+			}
+			if n := sl.RemoveRangeByKey(lo, hi); n != want {
+				t.Fatalf("RemoveRangeByKey(%d, %d) = %d, want %d", lo, hi, n, want)
+			}
+		} else {
+			start := 1 + r.Intn(len(keys))
+			stop := start + r.Intn(10)
+			for _, k := range keys[start-1 : min(stop, len(keys))] {
+				delete(ref, k)
+			}
+			sl.RemoveRangeByRank(start, stop)
+		}
+		if sl.Len() != len(ref) {
+			t.Fatalf("Expected length %d, got %d", len(ref), sl.Len())
+		}
+		checkStructure(t, sl)
+	}
+}
+
+// entry is a composite key ordered by score descending, then by timestamp
+// and id.
+type entry struct {
+	score int
+	ts    int64
+	id    string
+}
+
+func compareEntry(a, b entry) int {
+	if a.score != b.score {
+		return cmp.Compare(b.score, a.score)
+	}
+	if a.ts != b.ts {
+		return cmp.Compare(a.ts, b.ts)
+	}
+	return cmp.Compare(a.id, b.id)
+}
+
+func TestSkipList_NewFunc(t *testing.T) {
+	sl := NewFunc[entry, int](compareEntry)
+	sl.Set(entry{10, 2, "b"}, 1)
+	sl.Set(entry{20, 5, "c"}, 2)
+	sl.Set(entry{10, 1, "a"}, 3)
+	sl.Set(entry{10, 2, "b"}, 4)
+
+	var got []entry
+	sl.Ascend(func(k entry, _ int) bool {
+		got = append(got, k)
+		return true
+	})
+	want := []entry{{20, 5, "c"}, {10, 1, "a"}, {10, 2, "b"}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] || got[2] != want[2] {
+		t.Fatalf("Ascend: got %v, want %v", got, want)
+	}
+	if v, ok := sl.Get(entry{10, 2, "b"}); !ok || v != 4 {
+		t.Errorf("Expected 4, got %v", v)
+	}
+	if rank := sl.GetRank(entry{10, 1, "a"}); rank != 2 {
+		t.Errorf("Expected rank 2, got %d", rank)
+	}
+	if k, _, ok := sl.Floor(entry{15, 0, ""}); !ok || k != (entry{20, 5, "c"}) {
+		t.Errorf("Expected floor {20 5 c}, got %v", k)
+	}
+
+	// NewFunc with cmp.Compare must behave like New.
+	a, b := New[int, int](), NewFunc[int, int](cmp.Compare[int])
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(1000)
+		if r.Intn(3) == 0 {
+			if a.Remove(k) != b.Remove(k) {
+				t.Fatalf("Remove(%d) mismatch", k)
+			}
+		} else {
+			a.Set(k, i)
+			b.Set(k, i)
+		}
+	}
+	if !equalKeys(collectKeys(a.Ascend), collectKeys(b.Ascend)) {
+		t.Fatal("Ascend mismatch")
+	}
+	for k := 0; k < 1000; k++ {
+		if a.GetRank(k) != b.GetRank(k) {
+			t.Fatalf("GetRank(%d) mismatch", k)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected NewFunc to panic on nil cmp")
+		}
+	}()
+	NewFunc[entry, int](nil)
+}
+
+func TestSkipList_Search(t *testing.T) {
+	sl := New[int, int]()
+	if rank := sl.Search(func(int) bool { return true }); rank != 1 {
+		t.Errorf("Expected rank 1 on empty list, got %d", rank)
+	}
+
+	for i := 0; i < 1000; i++ {
+		sl.Set(i*2, i)
+	}
+	for pivot := -1; pivot <= 2000; pivot++ {
+		want := min(max((pivot+1)/2, 0), 1000) + 1
+		if rank := sl.Search(func(key int) bool { return key >= pivot }); rank != want {
+			t.Fatalf("Search(>= %d) = %d, want %d", pivot, rank, want)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package skiplist
+
+import "unsafe"
+
+// Stats describes the structure of a SkipList.
+type Stats struct {
+	Len         int     // Len is the number of elements.
+	Level       int     // Level is the number of levels currently in use.
+	MaxLevel    int     // MaxLevel is the maximum number of levels.
+	Probability float64 // Probability is the level promotion probability.
+
+	// LevelCounts[i] is the number of nodes linked at level i, that is the
+	// nodes with more than i levels. LevelCounts[0] equals Len.
+	LevelCounts []int
+
+	// MemoryBytes is an estimate of the memory used by the nodes and their
+	// links. Memory referenced by keys and values, such as string contents,
+	// is not included.
+	MemoryBytes int
+}
+
+// Level returns the number of levels currently in use.
+func (s *SkipList[K, V]) Level() int {
+	return s.level
+}
+
+// Stats returns statistics about the structure of the SkipList.
+// It walks every node, taking O(n) time.
+func (s *SkipList[K, V]) Stats() Stats {
+	st := Stats{
+		Len:         s.length,
+		Level:       s.level,
+		MaxLevel:    s.maxLevel,
+		Probability: s.p,
+		LevelCounts: make([]int, s.level),
+	}
+
+	var n node[K, V]
+	linkSize := int(unsafe.Sizeof(n.next[0]) + unsafe.Sizeof(n.span[0]))
+	nodeSize := int(unsafe.Sizeof(n))
+
+	links := s.maxLevel // the head has maxLevel links
+	for current := s.head.next[0]; current != nil; current = current.next[0] {
+		levels := len(current.next)
+		for i := 0; i < levels; i++ {
+			st.LevelCounts[i]++
+		}
+		links += levels
+	}
+	st.MemoryBytes = (s.length+1)*nodeSize + links*linkSize
+
+	return st
+}
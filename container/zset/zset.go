@@ -0,0 +1,276 @@
+// Package zset implements a Redis-style sorted set: unique members ordered by
+// (score, member), with O(log n) updates, rank and score range queries.
+package zset
+
+import (
+	"cmp"
+	"errors"
+	"math"
+
+	"github.com/godyy/gutils/container/skiplist"
+)
+
+// ErrNaN is returned when an operation would produce a NaN score.
+var ErrNaN = errors.New("zset: resulting score is not a number (NaN)")
+
+// Element is a member of a ZSet together with its score.
+type Element[M cmp.Ordered] struct {
+	Member M
+	Score  float64
+}
+
+// Bound is a score range bound. See Inclusive, Exclusive, NegInf and PosInf.
+type Bound struct {
+	Score     float64
+	Exclusive bool
+}
+
+// Inclusive returns a bound that includes score.
+func Inclusive(score float64) Bound {
+	return Bound{Score: score}
+}
+
+// Exclusive returns a bound that excludes score.
+func Exclusive(score float64) Bound {
+	return Bound{Score: score, Exclusive: true}
+}
+
+var (
+	// NegInf is the lowest possible bound, equivalent to Redis "-inf".
+	NegInf = Inclusive(math.Inf(-1))
+
+	// PosInf is the highest possible bound, equivalent to Redis "+inf".
+	PosInf = Inclusive(math.Inf(1))
+)
+
+// lessOrEqual reports whether score satisfies b as a lower bound.
+func (b Bound) lessOrEqual(score float64) bool {
+	if b.Exclusive {
+		return b.Score < score
+	}
+	return b.Score <= score
+}
+
+// greaterOrEqual reports whether score satisfies b as an upper bound.
+func (b Bound) greaterOrEqual(score float64) bool {
+	if b.Exclusive {
+		return score < b.Score
+	}
+	return score <= b.Score
+}
+
+// key orders the elements of a ZSet by (score, member).
+type key[M cmp.Ordered] struct {
+	score  float64
+	member M
+}
+
+func compareKey[M cmp.Ordered](a, b key[M]) int {
+	if c := cmp.Compare(a.score, b.score); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.member, b.member)
+}
+
+// ZSet is a sorted set of unique members ordered by (score, member). It
+// combines a skiplist.SkipList for ordered access with a member to score map
+// for O(1) score lookups.
+//
+// Ranks follow Redis and are 0-based, with rank 0 being the member with the
+// lowest score. Where a range of ranks is accepted, negative indexes count
+// from the end, -1 being the member with the highest score.
+type ZSet[M cmp.Ordered] struct {
+	dict map[M]float64
+	zsl  *skiplist.SkipList[key[M], struct{}]
+}
+
+// New creates a new empty ZSet. opts configure the underlying skip list,
+// for example skiplist.WithSeed for reproducible tests and replays.
+func New[M cmp.Ordered](opts ...skiplist.Option) *ZSet[M] {
+	return &ZSet[M]{
+		dict: make(map[M]float64),
+		zsl:  skiplist.NewFunc[key[M], struct{}](compareKey[M], opts...),
+	}
+}
+
+// Len returns the number of members in the ZSet, like ZCARD.
+func (z *ZSet[M]) Len() int {
+	return z.zsl.Len()
+}
+
+// set moves member from its current score to score.
+func (z *ZSet[M]) set(member M, score float64) {
+	if cur, ok := z.dict[member]; ok {
+		if cur == score {
+			return
+		}
+		z.zsl.Remove(key[M]{cur, member})
+	}
+	z.zsl.Set(key[M]{score, member}, struct{}{})
+	z.dict[member] = score
+}
+
+// ZAdd adds member with the given score, or updates its score if it is
+// already present. Returns true if the member was newly added.
+// It panics if score is NaN.
+func (z *ZSet[M]) ZAdd(member M, score float64) bool {
+	if math.IsNaN(score) {
+		panic("zset.ZAdd: score is NaN")
+	}
+	_, ok := z.dict[member]
+	z.set(member, score)
+	return !ok
+}
+
+// ZIncrBy increments the score of member by delta and returns the new score.
+// If member is not present, it is added with delta as its score.
+// Returns ErrNaN and leaves the ZSet unchanged if the new score is NaN.
+func (z *ZSet[M]) ZIncrBy(member M, delta float64) (float64, error) {
+	cur := z.dict[member]
+	score := cur + delta
+	if math.IsNaN(score) {
+		return cur, ErrNaN
+	}
+	z.set(member, score)
+	return score, nil
+}
+
+// ZRem removes member. Returns true if the member was present.
+func (z *ZSet[M]) ZRem(member M) bool {
+	score, ok := z.dict[member]
+	if !ok {
+		return false
+	}
+	z.zsl.Remove(key[M]{score, member})
+	delete(z.dict, member)
+	return true
+}
+
+// ZScore returns the score of member.
+// Returns 0 and false if the member is not present.
+func (z *ZSet[M]) ZScore(member M) (float64, bool) {
+	score, ok := z.dict[member]
+	return score, ok
+}
+
+// ZRank returns the rank of member in ascending score order.
+// Returns -1 and false if the member is not present.
+func (z *ZSet[M]) ZRank(member M) (int, bool) {
+	score, ok := z.dict[member]
+	if !ok {
+		return -1, false
+	}
+	return z.zsl.GetRank(key[M]{score, member}) - 1, true
+}
+
+// ZRevRank returns the rank of member in descending score order.
+// Returns -1 and false if the member is not present.
+func (z *ZSet[M]) ZRevRank(member M) (int, bool) {
+	score, ok := z.dict[member]
+	if !ok {
+		return -1, false
+	}
+	return z.zsl.Len() - z.zsl.GetRank(key[M]{score, member}), true
+}
+
+// normalizeRange converts the Redis-style rank range [start, stop] to 1-based
+// skip list ranks. Returns false if the range is empty.
+func (z *ZSet[M]) normalizeRange(start, stop int) (int, int, bool) {
+	length := z.zsl.Len()
+	if start < 0 {
+		start += length
+	}
+	if stop < 0 {
+		stop += length
+	}
+	start = max(start, 0)
+	stop = min(stop, length-1)
+	if start > stop {
+		return 0, 0, false
+	}
+	return start + 1, stop + 1, true
+}
+
+// collect returns an iterator that appends up to count elements to result,
+// or all of them if count is negative, while keep reports true for the score.
+func collect[M cmp.Ordered](result *[]Element[M], count int, keep func(score float64) bool) func(k key[M], _ struct{}) bool {
+	return func(k key[M], _ struct{}) bool {
+		if count == 0 || !keep(k.score) {
+			return false
+		}
+		*result = append(*result, Element[M]{Member: k.member, Score: k.score})
+		count--
+		return count != 0
+	}
+}
+
+// anyScore accepts every score.
+func anyScore(float64) bool { return true }
+
+// ZRange returns the members with ranks in [start, stop] in ascending score
+// order. Negative indexes count from the end.
+func (z *ZSet[M]) ZRange(start, stop int) []Element[M] {
+	from, to, ok := z.normalizeRange(start, stop)
+	if !ok {
+		return nil
+	}
+	result := make([]Element[M], 0, to-from+1)
+	z.zsl.AscendFromRank(from, collect(&result, to-from+1, anyScore))
+	return result
+}
+
+// ZRevRange returns the members with ranks in [start, stop] in descending
+// score order. Negative indexes count from the end.
+func (z *ZSet[M]) ZRevRange(start, stop int) []Element[M] {
+	from, to, ok := z.normalizeRange(start, stop)
+	if !ok {
+		return nil
+	}
+	result := make([]Element[M], 0, to-from+1)
+	z.zsl.DescendFromRank(z.zsl.Len()-from+1, collect(&result, to-from+1, anyScore))
+	return result
+}
+
+// firstRank returns the rank (1-based index) of the first member whose score
+// satisfies min, or Len()+1 if there is none.
+func (z *ZSet[M]) firstRank(min Bound) int {
+	return z.zsl.Search(func(k key[M]) bool { return min.lessOrEqual(k.score) })
+}
+
+// lastRank returns the rank (1-based index) of the last member whose score
+// satisfies max, or 0 if there is none.
+func (z *ZSet[M]) lastRank(max Bound) int {
+	return z.zsl.Search(func(k key[M]) bool { return !max.greaterOrEqual(k.score) }) - 1
+}
+
+// ZCount returns the number of members with scores between min and max.
+func (z *ZSet[M]) ZCount(min, max Bound) int {
+	if n := z.lastRank(max) - z.firstRank(min) + 1; n > 0 {
+		return n
+	}
+	return 0
+}
+
+// ZRangeByScore returns the members with scores between min and max in
+// ascending score order. Like the LIMIT clause of Redis, offset members are
+// skipped and at most count members are returned; a negative count returns
+// all the remaining members.
+func (z *ZSet[M]) ZRangeByScore(min, max Bound, offset, count int) []Element[M] {
+	if offset < 0 || count == 0 {
+		return nil
+	}
+	var result []Element[M]
+	z.zsl.AscendFromRank(z.firstRank(min)+offset, collect(&result, count, max.greaterOrEqual))
+	return result
+}
+
+// ZRevRangeByScore returns the members with scores between min and max in
+// descending score order. See ZRangeByScore for offset and count.
+func (z *ZSet[M]) ZRevRangeByScore(max, min Bound, offset, count int) []Element[M] {
+	if offset < 0 || count == 0 {
+		return nil
+	}
+	var result []Element[M]
+	z.zsl.DescendFromRank(z.lastRank(max)-offset, collect(&result, count, min.lessOrEqual))
+	return result
+}
@@ -0,0 +1,251 @@
+package zset
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/godyy/gutils/container/skiplist"
+)
+
+// sorted returns the elements of ref ordered by (score, member).
+func sorted(ref map[string]float64) []Element[string] {
+	elems := make([]Element[string], 0, len(ref))
+	for m, s := range ref {
+		elems = append(elems, Element[string]{Member: m, Score: s})
+	}
+	sort.Slice(elems, func(i, j int) bool {
+		a, b := elems[i], elems[j]
+		return a.Score < b.Score || (a.Score == b.Score && a.Member < b.Member)
+	})
+	return elems
+}
+
+func equal(a, b []Element[string]) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkStructure verifies the order of z in both directions and its ranks
+// against the reference.
+func checkStructure(t *testing.T, z *ZSet[string], want []Element[string]) {
+	t.Helper()
+	if got := z.ZRange(0, -1); z.Len() != len(want) || !equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	i := len(want)
+	z.zsl.Descend(func(k key[string], _ struct{}) bool {
+		i--
+		if i < 0 || k.member != want[i].Member || k.score != want[i].Score {
+			t.Fatalf("Descend mismatch at %d", i)
+		}
+		return true
+	})
+	for i, e := range want {
+		if k, _, ok := z.zsl.GetByRank(i + 1); !ok || k.member != e.Member {
+			t.Fatalf("GetByRank(%d) mismatch", i+1)
+		}
+	}
+}
+
+func TestZSet_Basic(t *testing.T) {
+	z := New[string]()
+	if !z.ZAdd("a", 1) || !z.ZAdd("b", 2) || !z.ZAdd("c", 2) {
+		t.Fatal("Expected members to be newly added")
+	}
+	if z.ZAdd("a", 3) {
+		t.Fatal("Expected a to be updated")
+	}
+	if score, ok := z.ZScore("a"); !ok || score != 3 {
+		t.Errorf("Expected score 3, got %v", score)
+	}
+	if _, ok := z.ZScore("d"); ok {
+		t.Error("Expected d not to exist")
+	}
+
+	// b:2, c:2, a:3
+	if rank, ok := z.ZRank("c"); !ok || rank != 1 {
+		t.Errorf("Expected rank 1, got %d", rank)
+	}
+	if rank, ok := z.ZRevRank("a"); !ok || rank != 0 {
+		t.Errorf("Expected reverse rank 0, got %d", rank)
+	}
+	if rank, ok := z.ZRank("d"); ok || rank != -1 {
+		t.Errorf("Expected rank -1, got %d", rank)
+	}
+
+	if score, err := z.ZIncrBy("b", 5); err != nil || score != 7 {
+		t.Errorf("Expected score 7, got %v, %v", score, err)
+	}
+	if score, err := z.ZIncrBy("d", -1); err != nil || score != -1 {
+		t.Errorf("Expected score -1, got %v, %v", score, err)
+	}
+	z.ZAdd("inf", math.Inf(1))
+	if _, err := z.ZIncrBy("inf", math.Inf(-1)); err != ErrNaN {
+		t.Errorf("Expected ErrNaN, got %v", err)
+	}
+	if score, _ := z.ZScore("inf"); !math.IsInf(score, 1) {
+		t.Errorf("Expected score to be unchanged, got %v", score)
+	}
+
+	if !z.ZRem("inf") || z.ZRem("inf") {
+		t.Error("Expected inf to be removed once")
+	}
+
+	want := []Element[string]{{"d", -1}, {"c", 2}, {"a", 3}, {"b", 7}}
+	if got := z.ZRange(0, -1); !equal(got, want) {
+		t.Errorf("ZRange: got %v, want %v", got, want)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected ZAdd to panic on NaN")
+		}
+	}()
+	z.ZAdd("nan", math.NaN())
+}
+
+func TestZSet_Range(t *testing.T) {
+	z := New[string]()
+	for i, m := range []string{"a", "b", "c", "d", "e"} {
+		z.ZAdd(m, float64(i+1))
+	}
+
+	members := func(elems []Element[string]) string {
+		s := ""
+		for _, e := range elems {
+			s += e.Member
+		}
+		return s
+	}
+
+	tests := []struct {
+		name string
+		got  []Element[string]
+		want string
+	}{
+		{"ZRange", z.ZRange(1, 3), "bcd"},
+		{"ZRange negative", z.ZRange(-2, -1), "de"},
+		{"ZRange clamped", z.ZRange(-10, 10), "abcde"},
+		{"ZRange empty", z.ZRange(3, 1), ""},
+		{"ZRevRange", z.ZRevRange(0, 1), "ed"},
+		{"ZRevRange negative", z.ZRevRange(-1, -1), "a"},
+		{"ZRangeByScore", z.ZRangeByScore(Inclusive(2), Inclusive(4), 0, -1), "bcd"},
+		{"ZRangeByScore exclusive", z.ZRangeByScore(Exclusive(2), Exclusive(4), 0, -1), "c"},
+		{"ZRangeByScore limit", z.ZRangeByScore(NegInf, PosInf, 1, 2), "bc"},
+		{"ZRangeByScore offset past end", z.ZRangeByScore(NegInf, Inclusive(3), 3, -1), ""},
+		{"ZRangeByScore inverted", z.ZRangeByScore(Inclusive(4), Inclusive(2), 0, -1), ""},
+		{"ZRevRangeByScore", z.ZRevRangeByScore(Inclusive(4), Exclusive(1), 0, -1), "dcb"},
+		{"ZRevRangeByScore limit", z.ZRevRangeByScore(PosInf, NegInf, 1, 2), "dc"},
+		{"ZRevRangeByScore offset past end", z.ZRevRangeByScore(Inclusive(2), NegInf, 2, -1), ""},
+	}
+	for _, tt := range tests {
+		if got := members(tt.got); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	if n := z.ZCount(Exclusive(1), Inclusive(4)); n != 3 {
+		t.Errorf("Expected count 3, got %d", n)
+	}
+	if n := z.ZCount(Exclusive(5), PosInf); n != 0 {
+		t.Errorf("Expected count 0, got %d", n)
+	}
+}
+
+func TestZSet_Random(t *testing.T) {
+	z := New[string]()
+	ref := make(map[string]float64)
+	r := rand.New(rand.NewSource(1))
+	members := make([]string, 300)
+	for i := range members {
+		members[i] = string(rune('a'+i%26)) + string(rune('a'+i/26))
+	}
+
+	for i := 0; i < 20000; i++ {
+		m := members[r.Intn(len(members))]
+		score := float64(r.Intn(50))
+		switch r.Intn(4) {
+		case 0:
+			z.ZRem(m)
+			delete(ref, m)
+		case 1:
+			got, _ := z.ZIncrBy(m, score-25)
+			ref[m] += score - 25
+			if got != ref[m] {
+				t.Fatalf("ZIncrBy(%s) = %v, want %v", m, got, ref[m])
+			}
+		default:
+			z.ZAdd(m, score)
+			ref[m] = score
+		}
+		if i%1000 != 0 {
+			continue
+		}
+
+		want := sorted(ref)
+		checkStructure(t, z, want)
+		for rank, e := range want {
+			if got, _ := z.ZRank(e.Member); got != rank {
+				t.Fatalf("ZRank(%s) = %d, want %d", e.Member, got, rank)
+			}
+			if got, _ := z.ZRevRank(e.Member); got != len(want)-1-rank {
+				t.Fatalf("ZRevRank(%s) = %d, want %d", e.Member, got, len(want)-1-rank)
+			}
+		}
+
+		lo, hi := Bound{float64(r.Intn(100) - 50), r.Intn(2) == 0}, Bound{float64(r.Intn(100) - 50), r.Intn(2) == 0}
+		var inRange []Element[string]
+		for _, e := range want {
+			if lo.lessOrEqual(e.Score) && hi.greaterOrEqual(e.Score) {
+				inRange = append(inRange, e)
+			}
+		}
+		offset, count := r.Intn(5), r.Intn(10)-1
+		wantRange := inRange[min(offset, len(inRange)):]
+		if count >= 0 {
+			wantRange = wantRange[:min(count, len(wantRange))]
+		}
+		if got := z.ZRangeByScore(lo, hi, offset, count); !equal(got, wantRange) {
+			t.Fatalf("ZRangeByScore(%v, %v, %d, %d) = %v, want %v", lo, hi, offset, count, got, wantRange)
+		}
+		if got := z.ZCount(lo, hi); got != len(inRange) {
+			t.Fatalf("ZCount(%v, %v) = %d, want %d", lo, hi, got, len(inRange))
+		}
+
+		var wantRev []Element[string]
+		for i := len(inRange) - 1 - offset; i >= 0 && len(wantRev) != count; i-- {
+			wantRev = append(wantRev, inRange[i])
+		}
+		if got := z.ZRevRangeByScore(hi, lo, offset, count); !equal(got, wantRev) {
+			t.Fatalf("ZRevRangeByScore(%v, %v, %d, %d) = %v, want %v", hi, lo, offset, count, got, wantRev)
+		}
+	}
+}
+
+func TestZSet_Seed(t *testing.T) {
+	build := func() *ZSet[int] {
+		z := New[int](skiplist.WithSeed(42), skiplist.WithMaxLevel(8))
+		for i := 0; i < 1000; i++ {
+			z.ZAdd(i, float64(i%37))
+		}
+		return z
+	}
+	a, b := build().zsl.Stats(), build().zsl.Stats()
+	if a.MaxLevel != 8 || a.Level != b.Level || len(a.LevelCounts) != len(b.LevelCounts) {
+		t.Fatalf("Expected identical structures, got %+v and %+v", a, b)
+	}
+	for i := range a.LevelCounts {
+		if a.LevelCounts[i] != b.LevelCounts[i] {
+			t.Fatalf("Expected identical level counts, got %v and %v", a.LevelCounts, b.LevelCounts)
+		}
+	}
+}
@@ -1,8 +1,9 @@
 module github.com/godyy/gutils
 
-go 1.22
+go 1.23
 
 require (
+	github.com/cespare/xxhash/v2 v2.3.0
 	github.com/pkg/errors v0.9.1
 	github.com/rs/xid v1.6.0
 	golang.org/x/crypto v0.32.0